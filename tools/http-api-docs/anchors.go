@@ -0,0 +1,62 @@
+package docs
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Strategies for the anchors of the headings of the endpoints (see
+// MarkdownFormatter.Anchors). The docs site links to anchors like
+// "api-v0-pin-add", which other renderers don't derive from the headings.
+const (
+	// AnchorsVuePress relies on the slugs VuePress derives from the
+	// headings, e.g. "api-v0-pin-add".
+	AnchorsVuePress = "vuepress"
+	// AnchorsGitHub relies on the slugs of GitHub and Docusaurus
+	// (github-slugger), which drop the slashes, e.g. "apiv0pinadd".
+	AnchorsGitHub = "github"
+	// AnchorsExplicit keeps the anchors of the docs site with a heading
+	// ID, e.g. "## /api/v0/pin/add {#api-v0-pin-add}", for Docusaurus and
+	// the renderers supporting the syntax.
+	AnchorsExplicit = "explicit"
+	// AnchorsHTML keeps them with an HTML anchor before the heading, for
+	// the other renderers.
+	AnchorsHTML = "html"
+)
+
+// AnchorStrategies lists the accepted strategies.
+var AnchorStrategies = []string{AnchorsVuePress, AnchorsGitHub, AnchorsExplicit, AnchorsHTML}
+
+// Styles of the front matter of the pages (see
+// MarkdownFormatter.FrontMatter).
+const (
+	// FrontMatterVuePress sets the title and description.
+	FrontMatterVuePress = "vuepress"
+	// FrontMatterDocusaurus also sets the label of the page in the sidebar,
+	// and limits the table of contents of the page to the endpoints.
+	FrontMatterDocusaurus = "docusaurus"
+	// FrontMatterNone omits the front matter.
+	FrontMatterNone = "none"
+)
+
+// FrontMatterStyles lists the accepted styles.
+var FrontMatterStyles = []string{FrontMatterVuePress, FrontMatterDocusaurus, FrontMatterNone}
+
+// anchor returns the anchor of the heading of an endpoint with the anchor
+// strategy of the formatter.
+func (md *MarkdownFormatter) anchor(name string) string {
+	if md.Anchors == AnchorsGitHub {
+		return githubSlug(name)
+	}
+	return endpointAnchor(name)
+}
+
+// githubSlugStrip matches the characters github-slugger removes from
+// headings.
+var githubSlugStrip = regexp.MustCompile(`[^\p{L}\p{M}\p{N}\p{Pc} -]`)
+
+// githubSlug returns the slug GitHub and Docusaurus derive from a heading,
+// e.g. "apiv0pinadd" for "/api/v0/pin/add".
+func githubSlug(heading string) string {
+	return strings.ReplaceAll(githubSlugStrip.ReplaceAllString(strings.ToLower(heading), ""), " ", "-")
+}
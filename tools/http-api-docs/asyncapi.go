@@ -0,0 +1,182 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+// AsyncAPIFormatter generates an AsyncAPI 3.0 document describing the event
+// streams (see eventStreams), like pubsub/sub, which fit AsyncAPI better
+// than OpenAPI: each endpoint is a channel, received by a POST request
+// taking the same query parameters as in the OpenAPI spec.
+type AsyncAPIFormatter struct {
+	// Host is the host of the RPC API. Defaults to 127.0.0.1:5001.
+	Host string
+	// DocsURL is the URL of the RPC API reference the operations link to.
+	// Defaults to DefaultDocsURL.
+	DocsURL string
+	// TypeMap overrides the types of placeholders (see LoadTypeMap).
+	TypeMap TypeMap
+}
+
+// asyncAPIChannel returns the ID of the channel of an endpoint, e.g.
+// "pubsubSub" for /api/v0/pubsub/sub.
+func asyncAPIChannel(name string) string {
+	id := pascalName(name)
+	return strings.ToLower(id[:1]) + id[1:]
+}
+
+// Generate returns the AsyncAPI document as YAML. Endpoints which are not
+// event streams are ignored.
+func (af *AsyncAPIFormatter) Generate(ctx context.Context, api []*Endpoint) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	host := af.Host
+	if host == "" {
+		host = "127.0.0.1:5001"
+	}
+
+	channels := make(map[string]any)
+	operations := make(map[string]any)
+	for _, endp := range api {
+		stream, ok := eventStreams[endp.Name]
+		if !ok {
+			continue
+		}
+		w := &warnings{endpoint: endp.Name}
+		id := asyncAPIChannel(endp.Name)
+
+		payload := stream.Payload
+		if payload == "" {
+			payload = endp.Response
+		}
+		var doc any
+		if err := json.Unmarshal([]byte(payload), &doc); err != nil {
+			return "", &EndpointError{Endpoint: endp.Name, Err: fmt.Errorf("parsing the payload: %w", err)}
+		}
+		schema := genSchemaForResponse(w, af.TypeMap, doc)
+		if schema == nil {
+			return "", &EndpointError{Endpoint: endp.Name, Err: fmt.Errorf("unsupported payload %s", payload)}
+		}
+		payloadSchema, err := schemaMap(schema)
+		if err != nil {
+			return "", &EndpointError{Endpoint: endp.Name, Err: err}
+		}
+
+		query, err := asyncAPIQuery(w, endp)
+		if err != nil {
+			return "", &EndpointError{Endpoint: endp.Name, Err: err}
+		}
+
+		channels[id] = map[string]any{
+			"address":     endp.Name,
+			"description": stream.Description,
+			"messages": map[string]any{
+				"event": map[string]any{
+					"name":        "event",
+					"contentType": "application/json",
+					"summary":     "One line of the newline-delimited JSON response.",
+					"payload":     payloadSchema,
+				},
+			},
+		}
+		operations[id] = map[string]any{
+			"action":       "receive",
+			"summary":      endp.Description,
+			"channel":      map[string]any{"$ref": "#/channels/" + id},
+			"messages":     []any{map[string]any{"$ref": "#/channels/" + id + "/messages/event"}},
+			"externalDocs": map[string]any{"url": endpointDocsURL(af.DocsURL, endp.Name)},
+			"bindings": map[string]any{
+				"http": map[string]any{
+					"method":         "POST",
+					"query":          query,
+					"bindingVersion": "0.3.0",
+				},
+			},
+		}
+	}
+
+	doc := map[string]any{
+		"asyncapi": "3.0.0",
+		"info": map[string]any{
+			"title":       "Kubo RPC API events",
+			"version":     IPFSVersion(),
+			"description": "Event streams of the Kubo RPC API. Each event is a line of the response to a POST request on the channel address.",
+		},
+		"servers": map[string]any{
+			"kubo": map[string]any{"host": host, "protocol": "http"},
+		},
+		"channels":   channels,
+		"operations": operations,
+	}
+	out := new(bytes.Buffer)
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return "", err
+	}
+	return out.String(), enc.Close()
+}
+
+// asyncAPIQuery returns the schema of the query parameters of an endpoint,
+// for its HTTP operation binding.
+func asyncAPIQuery(w *warnings, endp *Endpoint) (map[string]any, error) {
+	properties := make(map[string]any)
+	var required []string
+	add := func(p *openapi3.Parameter) error {
+		if p == nil || inlineSchema(p.Schema) == nil {
+			return nil
+		}
+		// The document has no components to reference.
+		schema, err := schemaMap(inlineSchema(p.Schema))
+		if err != nil {
+			return err
+		}
+		if p.Description != nil {
+			schema["description"] = *p.Description
+		}
+		properties[p.Name] = schema
+		if p.Required != nil && *p.Required {
+			required = append(required, p.Name)
+		}
+		return nil
+	}
+
+	var args []*Argument
+	for _, arg := range endp.Arguments {
+		if arg.Type != "file" {
+			args = append(args, arg)
+		}
+	}
+	// Like in the OpenAPI spec, several arguments are a single array.
+	switch len(args) {
+	case 0:
+	case 1:
+		if err := add(genParameterForArgument(w, args[0], true)); err != nil {
+			return nil, err
+		}
+	default:
+		if err := add(genParameterForMultiArgument(w, args)); err != nil {
+			return nil, err
+		}
+	}
+	for _, opt := range endp.Options {
+		if err := add(genParameterForArgument(w, opt, false)); err != nil {
+			return nil, err
+		}
+	}
+	query := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		query["required"] = required
+	}
+	return query, nil
+}
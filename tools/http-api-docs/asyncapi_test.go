@@ -0,0 +1,85 @@
+package docs
+
+import (
+	"context"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestAsyncAPI(t *testing.T) {
+	api := []*Endpoint{
+		NewEndpoint("/api/v0/pubsub/sub", "Subscribe to messages on a given topic.", 0).
+			WithArguments(NewArgument("topic", "string", "Name of topic.", true)).
+			WithResponse(struct {
+				From string `json:"from"`
+				Data string `json:"data"`
+			}{}),
+		NewEndpoint("/api/v0/log/tail", "Read the event log.", 0),
+		NewEndpoint("/api/v0/id", "Show IPFS node id info.", 0),
+	}
+	out, err := new(AsyncAPIFormatter).Generate(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		AsyncAPI string
+		Channels map[string]struct {
+			Address  string
+			Messages map[string]struct {
+				Payload struct {
+					Properties map[string]any
+				}
+			}
+		}
+		Operations map[string]struct {
+			Action       string
+			ExternalDocs struct {
+				URL string
+			} `yaml:"externalDocs"`
+			Bindings struct {
+				HTTP struct {
+					Query struct {
+						Required []string
+					}
+				}
+			}
+		}
+	}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.AsyncAPI != "3.0.0" || len(doc.Channels) != 2 {
+		t.Fatalf("expected pubsub/sub and log/tail channels, got:\n%s", out)
+	}
+	if ch := doc.Channels["pubsubSub"]; ch.Address != "/api/v0/pubsub/sub" || ch.Messages["event"].Payload.Properties["from"] == nil {
+		t.Errorf("unexpected pubsub/sub channel:\n%s", out)
+	}
+	if doc.Channels["logTail"].Messages["event"].Payload.Properties["msg"] == nil {
+		t.Errorf("log/tail should use the curated payload:\n%s", out)
+	}
+	if op := doc.Operations["pubsubSub"]; op.Action != "receive" || len(op.Bindings.HTTP.Query.Required) != 1 {
+		t.Errorf("unexpected pubsub/sub operation:\n%s", out)
+	}
+
+	out, err = (&AsyncAPIFormatter{DocsURL: "https://staging.example.com/rpc/"}).Generate(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if url := doc.Operations["pubsubSub"].ExternalDocs.URL; url != "https://staging.example.com/rpc/#api-v0-pubsub-sub" {
+		t.Errorf("the operations should link to DocsURL, got %s", url)
+	}
+
+	formatter := new(OpenAPIFormatter)
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+	op := formatter.spec.Paths.MapOfPathItemValues["/api/v0/pubsub/sub"].MapOfOperationValues["post"]
+	if op.MapOfAnything["x-asyncapi-channel"] != "pubsubSub" {
+		t.Errorf("the OpenAPI operation should refer to its channel, got %v", op.MapOfAnything)
+	}
+}
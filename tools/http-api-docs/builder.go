@@ -0,0 +1,133 @@
+package docs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+// This file contains the API to describe endpoints by hand, for projects
+// whose HTTP API is not (only) a go-ipfs-cmds command tree. Command trees can
+// be fed to Endpoints directly.
+
+// argumentTypes are the types of Argument supported by the formatters.
+var argumentTypes = map[string]bool{
+	"string":  true,
+	"file":    true,
+	"bool":    true,
+	"int":     true,
+	"uint":    true,
+	"int64":   true,
+	"uint64":  true,
+	"float64": true,
+	"array":   true,
+}
+
+// NewEndpoint returns an endpoint returning text, without arguments.
+// The name is the full path, like "/api/v0/pin/add".
+func NewEndpoint(name, description string, status cmds.Status) *Endpoint {
+	return &Endpoint{
+		Name:        name,
+		Status:      status,
+		Description: description,
+		Response:    textResponse,
+	}
+}
+
+// NewArgument returns a positional argument. Arguments of type "file" are
+// sent in the request body.
+func NewArgument(name, typ, description string, required bool) *Argument {
+	return &Argument{Name: name, Type: typ, Description: description, Required: required}
+}
+
+// NewOption returns an option, with its default value formatted as with
+// fmt.Sprint, or "" for none. Its status is read from the markers of the
+// description, e.g. "(experimental)".
+func NewOption(name, typ, description, def string) *Argument {
+	return &Argument{Name: name, Type: typ, Description: description, Default: def, Status: optionStatus(description)}
+}
+
+// WithArguments adds positional arguments to the endpoint.
+func (endp *Endpoint) WithArguments(args ...*Argument) *Endpoint {
+	for _, arg := range args {
+		arg.Endpoint = endp.Name
+	}
+	endp.Arguments = append(endp.Arguments, args...)
+	return endp
+}
+
+// WithOptions adds options to the endpoint.
+func (endp *Endpoint) WithOptions(opts ...*Argument) *Endpoint {
+	for _, opt := range opts {
+		opt.Endpoint = endp.Name
+	}
+	endp.Options = append(endp.Options, opts...)
+	return endp
+}
+
+// WithResponse documents the JSON response of the endpoint with a value of
+// the Go type it returns, as cmds.Command.Type does. A nil value means the
+// endpoint returns text.
+func (endp *Endpoint) WithResponse(v interface{}) *Endpoint {
+	endp.Response = buildResponse(v)
+	endp.ResponseType = responseType(v)
+	endp.ResponseFields = fieldPresence(endp.Name, v)
+	return endp
+}
+
+// Validate checks that the endpoint can be rendered by the formatters.
+func (endp *Endpoint) Validate() error {
+	var errs []error
+	if !strings.HasPrefix(endp.Name, "/") {
+		errs = append(errs, fmt.Errorf("name must be an absolute path"))
+	}
+	if endp.Status < cmds.Active || endp.Status > cmds.Removed {
+		errs = append(errs, fmt.Errorf("unknown status %d", endp.Status))
+	}
+	if endp.Response != textResponse && endp.Response != "" && !json.Valid([]byte(endp.Response)) {
+		errs = append(errs, fmt.Errorf("response is neither JSON nor text"))
+	}
+
+	seen := make(map[string]bool)
+	for _, arg := range append(append([]*Argument{}, endp.Arguments...), endp.Options...) {
+		if arg.Name == "" {
+			errs = append(errs, fmt.Errorf("argument without a name"))
+		} else if seen[arg.Name] {
+			errs = append(errs, fmt.Errorf("duplicate argument %s", arg.Name))
+		}
+		seen[arg.Name] = true
+		if !argumentTypes[arg.Type] {
+			errs = append(errs, fmt.Errorf("argument %s: unsupported type %q", arg.Name, arg.Type))
+		}
+	}
+	for _, opt := range endp.Options {
+		if opt.Type == "file" {
+			errs = append(errs, fmt.Errorf("option %s: only arguments can be files", opt.Name))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return &EndpointError{Endpoint: endp.Name, Err: err}
+	}
+	return nil
+}
+
+// ValidateEndpoints validates every endpoint, and that their names are
+// unique.
+func ValidateEndpoints(api []*Endpoint) error {
+	var errs []error
+	seen := make(map[string]bool)
+	for _, endp := range api {
+		if seen[endp.Name] {
+			errs = append(errs, &EndpointError{Endpoint: endp.Name, Err: errors.New("duplicate endpoint")})
+		}
+		seen[endp.Name] = true
+		if err := endp.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
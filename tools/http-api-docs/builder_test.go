@@ -0,0 +1,72 @@
+package docs
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+type builderOutput struct {
+	Greeting string
+}
+
+func TestBuilder(t *testing.T) {
+	api := []*Endpoint{
+		NewEndpoint("/api/v1/hello", "Say hello.", cmds.Active).
+			WithArguments(NewArgument("name", "string", "Who to greet.", true)).
+			WithOptions(NewOption("shout", "bool", "Greet loudly.", "false")).
+			WithResponse(builderOutput{}),
+		NewEndpoint("/api/v1/upload", "Upload a file.", cmds.Experimental).
+			WithArguments(NewArgument("file", "file", "The file.", true)),
+	}
+	if err := ValidateEndpoints(api); err != nil {
+		t.Fatal(err)
+	}
+	if api[0].Response != "{\n  \"Greeting\": \"<string>\"\n}\n" || api[1].Response != textResponse {
+		t.Errorf("unexpected responses %q, %q", api[0].Response, api[1].Response)
+	}
+
+	if doc := generateDocs(t, api, new(MarkdownFormatter)); !strings.Contains(doc, "## /api/v1/hello") {
+		t.Errorf("markdown does not document /api/v1/hello")
+	}
+	formatter := &OpenAPIFormatter{Strict: true}
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewOptionStatus(t *testing.T) {
+	if opt := NewOption("fast", "bool", "Go faster. (experimental)", "false"); opt.Status != cmds.Experimental {
+		t.Errorf("the option should be experimental, got %v", opt.Status)
+	}
+	if opt := NewOption("shout", "bool", "Greet loudly.", "false"); opt.Status != cmds.Active {
+		t.Errorf("the option should be active, got %v", opt.Status)
+	}
+}
+
+func TestValidateEndpoints(t *testing.T) {
+	api := []*Endpoint{
+		NewEndpoint("relative", "", cmds.Active).
+			WithArguments(NewArgument("a", "string", "", false)).
+			WithOptions(NewOption("a", "complex128", "", ""), NewOption("f", "file", "", "")),
+		NewEndpoint("/dup", "", cmds.Active),
+		NewEndpoint("/dup", "", cmds.Active),
+	}
+	err := ValidateEndpoints(api)
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, want := range []string{
+		"relative: name must be an absolute path",
+		"duplicate argument a",
+		`argument a: unsupported type "complex128"`,
+		"option f: only arguments can be files",
+		"/dup: duplicate endpoint",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not contain %q:\n%s", want, err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// Markdown renders the diff as a changelog for the release notes of Kubo,
+// with a sentence per change, e.g. "Option `--progress` default changed to
+// `true`". New endpoints link to the RPC API reference.
+func (diff *APIDiff) Markdown() string {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "### RPC API changes from Kubo %s to %s\n", diff.From, diff.To)
+	if len(diff.Added)+len(diff.Removed)+len(diff.Changed) == 0 {
+		fmt.Fprintln(buf, "\nNo changes.")
+		return buf.String()
+	}
+
+	if len(diff.Added)+len(diff.Removed) > 0 {
+		fmt.Fprintln(buf)
+	}
+	for _, name := range diff.Added {
+		fmt.Fprintf(buf, "- New endpoint [`%s`](https://docs.ipfs.tech/reference/kubo/rpc/#%s)\n", name, endpointAnchor(name))
+	}
+	for _, name := range diff.Removed {
+		fmt.Fprintf(buf, "- Removed endpoint `%s`\n", name)
+	}
+
+	for _, d := range diff.Changed {
+		fmt.Fprintf(buf, "\n#### `%s`\n\n", d.Endpoint)
+		if d.Status != nil {
+			fmt.Fprintf(buf, "- Status changed from %s to %s\n", d.Status.From, d.Status.To)
+		}
+		for _, name := range d.AddedArguments {
+			fmt.Fprintf(buf, "- New argument `%s`\n", name)
+		}
+		for _, name := range d.RemovedArguments {
+			fmt.Fprintf(buf, "- Removed argument `%s`\n", name)
+		}
+		for _, name := range d.AddedOptions {
+			fmt.Fprintf(buf, "- New option `--%s`\n", name)
+		}
+		for _, name := range d.RemovedOptions {
+			fmt.Fprintf(buf, "- Removed option `--%s`\n", name)
+		}
+		for _, name := range sortedChanges(d.ChangedDefaults) {
+			change := d.ChangedDefaults[name]
+			switch {
+			case change.From == "":
+				fmt.Fprintf(buf, "- Option `--%s` now defaults to `%s`\n", name, change.To)
+			case change.To == "":
+				fmt.Fprintf(buf, "- Option `--%s` has no default anymore (was `%s`)\n", name, change.From)
+			default:
+				fmt.Fprintf(buf, "- Option `--%s` default changed from `%s` to `%s`\n", name, change.From, change.To)
+			}
+		}
+		for _, name := range sortedChanges(d.ChangedTypes) {
+			fmt.Fprintf(buf, "- Option `--%s` type changed from %s to %s\n", name, d.ChangedTypes[name].From, d.ChangedTypes[name].To)
+		}
+		for _, field := range d.AddedFields {
+			fmt.Fprintf(buf, "- New response field `%s`\n", field)
+		}
+		for _, field := range d.RemovedFields {
+			fmt.Fprintf(buf, "- Removed response field `%s`\n", field)
+		}
+		for _, field := range sortedChanges(d.ChangedFields) {
+			fmt.Fprintf(buf, "- Response field `%s` type changed from `%s` to `%s`\n", field, d.ChangedFields[field].From, d.ChangedFields[field].To)
+		}
+	}
+	return buf.String()
+}
+
+// sortedChanges returns the names of the changes, sorted.
+func sortedChanges(changes map[string]*Change) []string {
+	names := make([]string, 0, len(changes))
+	for name := range changes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
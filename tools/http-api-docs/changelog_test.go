@@ -0,0 +1,49 @@
+package docs
+
+import (
+	"testing"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+func TestDiffMarkdown(t *testing.T) {
+	from := &EndpointsDump{KuboVersion: "0.1.0", Endpoints: []*Endpoint{
+		{Name: "/api/v0/gone"},
+		{
+			Name:     "/api/v0/changed",
+			Options:  []*Argument{{Name: "old"}, {Name: "progress", Type: "bool", Default: "false"}, {Name: "limit", Type: "int"}},
+			Response: `{"Peers": [{"ID": "<string>", "Latency": "<string>"}]}`,
+		},
+	}}
+	to := &EndpointsDump{KuboVersion: "0.2.0", Endpoints: []*Endpoint{
+		{Name: "/api/v0/files/chmod"},
+		{
+			Name:      "/api/v0/changed",
+			Status:    cmds.Deprecated,
+			Arguments: []*Argument{{Name: "path"}},
+			Options:   []*Argument{{Name: "progress", Type: "bool", Default: "true"}, {Name: "limit", Type: "int", Default: "10"}},
+			Response:  `{"Peers": [{"ID": "<string>", "Latency": "<int64>"}]}`,
+		},
+	}}
+
+	want := "### RPC API changes from Kubo 0.1.0 to 0.2.0\n" +
+		"\n" +
+		"- New endpoint [`/api/v0/files/chmod`](https://docs.ipfs.tech/reference/kubo/rpc/#api-v0-files-chmod)\n" +
+		"- Removed endpoint `/api/v0/gone`\n" +
+		"\n" +
+		"#### `/api/v0/changed`\n" +
+		"\n" +
+		"- Status changed from Active to Deprecated\n" +
+		"- New argument `path`\n" +
+		"- Removed option `--old`\n" +
+		"- Option `--limit` now defaults to `10`\n" +
+		"- Option `--progress` default changed from `false` to `true`\n" +
+		"- Response field `Peers[].Latency` type changed from `<string>` to `<int64>`\n"
+	if got := DiffEndpoints(from, to).Markdown(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := DiffEndpoints(from, from).Markdown(); got != "### RPC API changes from Kubo 0.1.0 to 0.1.0\n\nNo changes.\n" {
+		t.Errorf("unexpected changelog without changes:\n%s", got)
+	}
+}
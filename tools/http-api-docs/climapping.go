@@ -0,0 +1,126 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CLIMapping is a row of the table mapping the ipfs CLI to the RPC API:
+// a command, or one of its flags or positional arguments.
+type CLIMapping struct {
+	// Command is the CLI command, e.g. "ipfs pin add".
+	Command string `json:"command"`
+	// Endpoint is the endpoint of the command, e.g. "/api/v0/pin/add".
+	Endpoint string `json:"endpoint"`
+	// Flag is the CLI flag, e.g. "--recursive", or positional argument,
+	// e.g. "<ipfs-path>". It is empty in the row of the command itself.
+	Flag string `json:"flag,omitempty"`
+	// Aliases are the other CLI names of the flag, e.g. "-r".
+	Aliases []string `json:"aliases,omitempty"`
+	// Parameter is the query parameter taking the value of the flag, named
+	// after it ("recursive", aliases work as well), or "arg" for
+	// positional arguments.
+	Parameter string `json:"parameter,omitempty"`
+	// In is "query", or "body" for the file arguments sent in the
+	// multipart request body.
+	In string `json:"in,omitempty"`
+	// Position is the position of positional arguments, from 1: all of
+	// them are passed as arg parameters, in this order.
+	Position int `json:"position,omitempty"`
+	// Variadic is set for arguments which can be given several times,
+	// repeating the parameter.
+	Variadic bool `json:"variadic,omitempty"`
+}
+
+// CLIMappingFormatter generates a table mapping every CLI command and flag
+// to its endpoint and query parameter, for tools translating shell scripts
+// into RPC API calls.
+type CLIMappingFormatter struct {
+	// CSV selects CSV output, with a header row, instead of JSON.
+	CSV bool
+}
+
+// cliMappingHeader is the header row of the CSV table.
+var cliMappingHeader = []string{"command", "endpoint", "flag", "aliases", "parameter", "in", "position", "variadic"}
+
+// Generate returns the table, with the rows of each endpoint in the order
+// of api.
+func (cf *CLIMappingFormatter) Generate(ctx context.Context, api []*Endpoint) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	rows := CLIMappings(api)
+	if !cf.CSV {
+		b, err := json.MarshalIndent(rows, "", "  ")
+		return string(b), err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(cliMappingHeader)
+	for _, row := range rows {
+		position := ""
+		if row.Position > 0 {
+			position = strconv.Itoa(row.Position)
+		}
+		w.Write([]string{row.Command, row.Endpoint, row.Flag, strings.Join(row.Aliases, " "),
+			row.Parameter, row.In, position, strconv.FormatBool(row.Variadic)})
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// CLIMappings returns the rows of the table mapping the CLI to the
+// endpoints: for each endpoint, the row of the command, then its
+// positional arguments and its options.
+func CLIMappings(api []*Endpoint) []CLIMapping {
+	var rows []CLIMapping
+	for _, endp := range api {
+		command := cliCommand(endp.Name)
+		rows = append(rows, CLIMapping{Command: command, Endpoint: endp.Name})
+		for i, arg := range endp.Arguments {
+			in := "query"
+			if arg.Type == "file" {
+				in = "body"
+			}
+			rows = append(rows, CLIMapping{
+				Command:   command,
+				Endpoint:  endp.Name,
+				Flag:      fmt.Sprintf("<%s>", arg.Name),
+				Parameter: "arg",
+				In:        in,
+				Position:  i + 1,
+				Variadic:  arg.Variadic,
+			})
+		}
+		for _, opt := range endp.Options {
+			row := CLIMapping{
+				Command:   command,
+				Endpoint:  endp.Name,
+				Flag:      cliFlag(opt.Name),
+				Parameter: opt.Name,
+				In:        "query",
+				Variadic:  opt.Type == "array",
+			}
+			for _, alias := range opt.Aliases {
+				row.Aliases = append(row.Aliases, cliFlag(alias))
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// cliFlag returns the CLI flag of an option name: "-r" for one-letter
+// names, "--recursive" otherwise.
+func cliFlag(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
+}
@@ -0,0 +1,51 @@
+package docs
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCLIMappings(t *testing.T) {
+	api := []*Endpoint{
+		{Name: "/api/v0/add", Arguments: []*Argument{{Name: "path", Type: "file", Required: true, Variadic: true}}},
+		{Name: "/api/v0/pin/add", Arguments: []*Argument{{Name: "ipfs-path", Type: "string", Required: true, Variadic: true}},
+			Options: []*Argument{{Name: "recursive", Type: "bool", Aliases: []string{"r"}}}},
+	}
+	out, err := new(CLIMappingFormatter).Generate(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []CLIMapping
+	if err := json.Unmarshal([]byte(out), &rows); err != nil {
+		t.Fatal(err)
+	}
+	want := []CLIMapping{
+		{Command: "ipfs add", Endpoint: "/api/v0/add"},
+		{Command: "ipfs add", Endpoint: "/api/v0/add", Flag: "<path>", Parameter: "arg", In: "body", Position: 1, Variadic: true},
+		{Command: "ipfs pin add", Endpoint: "/api/v0/pin/add"},
+		{Command: "ipfs pin add", Endpoint: "/api/v0/pin/add", Flag: "<ipfs-path>", Parameter: "arg", In: "query", Position: 1, Variadic: true},
+		{Command: "ipfs pin add", Endpoint: "/api/v0/pin/add", Flag: "--recursive", Aliases: []string{"-r"}, Parameter: "recursive", In: "query"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got %+v, want %+v", rows, want)
+	}
+
+	out, err = (&CLIMappingFormatter{CSV: true}).Generate(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 6 || !reflect.DeepEqual(records[0], cliMappingHeader) {
+		t.Fatalf("expected a header and 5 rows, got %q", out)
+	}
+	if got := strings.Join(records[5], ","); got != "ipfs pin add,/api/v0/pin/add,--recursive,-r,recursive,query,,false" {
+		t.Errorf("unexpected row %q", got)
+	}
+}
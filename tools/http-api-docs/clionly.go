@@ -0,0 +1,54 @@
+package docs
+
+import (
+	"fmt"
+	"sort"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	corecmds "github.com/ipfs/kubo/core/commands"
+)
+
+// CLIOnlyCommand is a command of the ipfs CLI which is not available over
+// the RPC API (NoRemote), e.g. because it edits the local repository or
+// starts the daemon. Endpoints leaves them out.
+type CLIOnlyCommand struct {
+	// Name is the name its endpoint would have, e.g. "/api/v0/config/edit".
+	Name string `json:"name"`
+	// Command is the CLI command, e.g. "ipfs config edit".
+	Command     string `json:"command"`
+	Description string `json:"description,omitempty"`
+}
+
+// cliBinaryCommands are the CLI-only commands which the ipfs binary adds to
+// the root command, so that they are not part of corecmds.Root.
+var cliBinaryCommands = []CLIOnlyCommand{
+	{Name: APIPrefix + "/daemon", Description: "Run a network-connected IPFS node."},
+	{Name: APIPrefix + "/init", Description: "Initializes ipfs config file."},
+}
+
+// CLIOnlyCommands returns the commands of the ipfs CLI which are not
+// available over the RPC API, sorted by name.
+func CLIOnlyCommands() []CLIOnlyCommand {
+	commands := cliOnlyCommands(APIPrefix, corecmds.Root)
+	for _, c := range cliBinaryCommands {
+		c.Command = cliCommand(c.Name)
+		commands = append(commands, c)
+	}
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name < commands[j].Name })
+	return commands
+}
+
+// cliOnlyCommands returns the NoRemote commands of a command tree.
+func cliOnlyCommands(name string, cmd *cmds.Command) (commands []CLIOnlyCommand) {
+	if cmd.NoRemote && cmd.Run != nil {
+		commands = append(commands, CLIOnlyCommand{
+			Name:        name,
+			Command:     cliCommand(name),
+			Description: cmd.Helptext.Tagline,
+		})
+	}
+	for n, sub := range cmd.Subcommands {
+		commands = append(commands, cliOnlyCommands(fmt.Sprintf("%s/%s", name, n), sub)...)
+	}
+	return commands
+}
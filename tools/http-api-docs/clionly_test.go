@@ -0,0 +1,36 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCLIOnlyCommands(t *testing.T) {
+	commands := CLIOnlyCommands()
+	names := make(map[string]CLIOnlyCommand)
+	for _, c := range commands {
+		names[c.Name] = c
+	}
+	for _, name := range []string{"/api/v0/config/edit", "/api/v0/daemon", "/api/v0/commands/completion/bash"} {
+		if _, ok := names[name]; !ok {
+			t.Errorf("%s should be CLI-only", name)
+		}
+	}
+	if c := names["/api/v0/config/edit"]; c.Command != "ipfs config edit" || c.Description == "" {
+		t.Errorf("unexpected command %+v", c)
+	}
+	for _, endp := range AllEndpoints() {
+		if _, ok := names[endp.Name]; ok {
+			t.Errorf("%s is CLI-only but has an endpoint", endp.Name)
+		}
+	}
+
+	api, _ := fixtureEndpoints(t)
+	if doc := generateDocs(t, api, new(MarkdownFormatter)); strings.Contains(doc, "CLI-only") {
+		t.Errorf("the appendix should be optional")
+	}
+	doc := generateDocs(t, api, &MarkdownFormatter{CLIOnly: true})
+	if !strings.Contains(doc, "## Appendix: CLI-only commands") || !strings.Contains(doc, "- `ipfs config edit`: Open the config file for editing in $EDITOR.\n") {
+		t.Errorf("missing appendix in:\n%s", doc)
+	}
+}
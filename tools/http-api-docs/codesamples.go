@@ -0,0 +1,155 @@
+package docs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+// CodeSample is an example call of an operation, rendered by Redoc from the
+// x-codeSamples extension.
+type CodeSample struct {
+	Lang   string `json:"lang"`
+	Label  string `json:"label"`
+	Source string `json:"source"`
+}
+
+// codeSamples returns ready-to-run calls of an endpoint, passing its
+// required arguments, its required options and the options of its example
+// call: with curl, and with kubo-rpc-client for Kubo endpoints.
+func codeSamples(endp *Endpoint, example map[string]string) []CodeSample {
+	options := sampleOptions(endp, example)
+	samples := []CodeSample{{Lang: "Shell", Label: "curl", Source: quickstartCurl(endp, nil, options)}}
+	if js := kuboRPCClientSample(endp, options); js != "" {
+		samples = append(samples, CodeSample{Lang: "JavaScript", Label: "kubo-rpc-client", Source: js})
+	}
+	return samples
+}
+
+// sampleOption is an option passed by the code samples.
+type sampleOption struct {
+	arg   *Argument
+	value string
+}
+
+// sampleOptions returns the options of an endpoint passed by its code
+// samples, in their order: the required ones, and the ones set by the
+// example call, by name or alias, with their value in it. The other
+// required options get a placeholder, e.g. <key>.
+func sampleOptions(endp *Endpoint, example map[string]string) []sampleOption {
+	var options []sampleOption
+	for _, opt := range endp.Options {
+		value, ok := example[opt.Name]
+		for _, alias := range opt.Aliases {
+			if !ok {
+				value, ok = example[alias]
+			}
+		}
+		if !ok && !opt.Required {
+			continue
+		}
+		if !ok {
+			value = "<" + opt.Name + ">"
+		}
+		options = append(options, sampleOption{arg: opt, value: value})
+	}
+	return options
+}
+
+// exampleOptions returns the options of the example call of an endpoint:
+// the recorded one, or the one of exampleCalls.
+func (myself *OpenAPIFormatter) exampleOptions(name string) map[string]string {
+	if example, ok := myself.Examples[name]; ok {
+		return example.Options
+	}
+	for _, call := range exampleCalls {
+		if call.Endpoint == name {
+			return call.Options
+		}
+	}
+	return nil
+}
+
+// kuboRPCClientSample returns a call of the endpoint with kubo-rpc-client,
+// whose methods follow the command paths in camelCase, e.g.
+// client.pin.remote.service.ls() for /api/v0/pin/remote/service/ls. The
+// options are passed in the object of the last argument, in camelCase too.
+func kuboRPCClientSample(endp *Endpoint, options []sampleOption) string {
+	path, ok := strings.CutPrefix(endp.Name, APIPrefix+"/")
+	if !ok {
+		return ""
+	}
+	method, ok := kuboRPCClientMethods[path]
+	if !ok {
+		var parts []string
+		for _, part := range strings.Split(path, "/") {
+			parts = append(parts, camelCase(part))
+		}
+		method = strings.Join(parts, ".")
+	}
+
+	var args []string
+	needsContent := false
+	for _, arg := range endp.Arguments {
+		if !arg.Required {
+			continue
+		}
+		if arg.Type == "file" {
+			args = append(args, "content")
+			needsContent = true
+		} else {
+			args = append(args, "'<"+arg.Name+">'")
+		}
+	}
+	if len(options) > 0 {
+		var fields []string
+		for _, opt := range options {
+			fields = append(fields, camelCase(opt.arg.Name)+": "+jsValue(opt.arg, opt.value))
+		}
+		args = append(args, "{ "+strings.Join(fields, ", ")+" }")
+	}
+	call := fmt.Sprintf("client.%s(%s)", method, strings.Join(args, ", "))
+
+	buf := new(strings.Builder)
+	fmt.Fprintln(buf, "import { create } from 'kubo-rpc-client'")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "const client = create({ url: 'http://127.0.0.1:5001/api/v0' })")
+	if needsContent {
+		fmt.Fprintln(buf, "const content = new TextEncoder().encode('hello world')")
+	}
+	if (endp.Streaming && !kuboRPCClientPromises[path]) || endp.Response == textResponse {
+		// These return async iterables.
+		fmt.Fprintf(buf, "for await (const chunk of %s) {\n  console.log(chunk)\n}", call)
+	} else {
+		fmt.Fprintf(buf, "const result = await %s\nconsole.log(result)", call)
+	}
+	return buf.String()
+}
+
+// jsValue returns the JavaScript literal of the value of an option: a
+// boolean or a number for the options of these types, a string otherwise.
+func jsValue(arg *Argument, value string) string {
+	switch argumentKind(arg) {
+	case cmds.Bool:
+		if _, err := strconv.ParseBool(value); err == nil {
+			return value
+		}
+	case cmds.Int, cmds.Uint, cmds.Int64, cmds.Uint64, cmds.Float:
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return value
+		}
+	}
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
+
+func camelCase(s string) string {
+	parts := strings.Split(s, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
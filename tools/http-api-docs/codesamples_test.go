@@ -0,0 +1,68 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCodeSamples(t *testing.T) {
+	endp := &Endpoint{
+		Name: "/api/v0/pin/ls",
+		Arguments: []*Argument{
+			{Name: "ipfs-path", Type: "string", Required: true},
+		},
+		Options:   []*Argument{{Name: "recursive", Type: "bool", Default: "true"}},
+		Response:  `{"Pins": ["<string>"]}`,
+		Streaming: true,
+	}
+	samples := codeSamples(endp, nil)
+	if len(samples) != 2 {
+		t.Fatalf("expected curl and kubo-rpc-client samples, got %v", samples)
+	}
+	if want := `curl -X POST "http://127.0.0.1:5001/api/v0/pin/ls?arg=<ipfs-path>"`; samples[0].Source != want {
+		t.Errorf("got curl sample %q, want %q", samples[0].Source, want)
+	}
+	want := `import { create } from 'kubo-rpc-client'
+
+const client = create({ url: 'http://127.0.0.1:5001/api/v0' })
+for await (const chunk of client.pin.ls('<ipfs-path>')) {
+  console.log(chunk)
+}`
+	if samples[1].Source != want {
+		t.Errorf("got js sample:\n%s\nwant:\n%s", samples[1].Source, want)
+	}
+
+	add := &Endpoint{Name: "/api/v0/swarm/addrs/local", Response: `{"Strings": ["<string>"]}`}
+	if got := kuboRPCClientSample(add, nil); got != `import { create } from 'kubo-rpc-client'
+
+const client = create({ url: 'http://127.0.0.1:5001/api/v0' })
+const result = await client.swarm.localAddrs()
+console.log(result)` {
+		t.Errorf("unexpected sample:\n%s", got)
+	}
+
+	if got := codeSamples(&Endpoint{Name: "/api/v1/hello"}, nil); len(got) != 1 {
+		t.Errorf("only Kubo endpoints have kubo-rpc-client samples")
+	}
+
+	format := &Endpoint{
+		Name:      "/api/v0/cid/format",
+		Arguments: []*Argument{{Name: "cid", Type: "string", Required: true}},
+		Options: []*Argument{
+			{Name: "f", Type: "string"},
+			{Name: "v", Type: "string"},
+			{Name: "b", Type: "string"},
+			{Name: "key", Type: "string", Required: true},
+			{Name: "recursive", Type: "bool", Aliases: []string{"r"}},
+			{Name: "max-depth", Type: "int"},
+		},
+		Response: `{"Formatted": "<string>"}`,
+	}
+	samples = codeSamples(format, map[string]string{"v": "1", "b": "base32", "r": "true", "max-depth": "2"})
+	if want := `curl -X POST "http://127.0.0.1:5001/api/v0/cid/format?arg=<cid>&v=1&b=base32&key=<key>&recursive=true&max-depth=2"`; samples[0].Source != want {
+		t.Errorf("got curl sample %q, want %q", samples[0].Source, want)
+	}
+	if want := `const result = await client.cid.format('<cid>', { v: '1', b: 'base32', key: '<key>', recursive: true, maxDepth: 2 })`; !strings.Contains(samples[1].Source, want) {
+		t.Errorf("got js sample:\n%s\nwant the call %s", samples[1].Source, want)
+	}
+}
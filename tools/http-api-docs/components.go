@@ -0,0 +1,109 @@
+package docs
+
+import (
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// Names of the component schemas of the identifiers used across the IPFS
+// HTTP APIs, shared by the generated specs so that their clients can share
+// the types.
+const (
+	cidSchemaName       = "CIDString"
+	peerIDSchemaName    = "PeerID"
+	multiaddrSchemaName = "Multiaddr"
+	multibaseSchemaName = "MultibaseString"
+	ipfsPathSchemaName  = "IPFSPath"
+	mfsPathSchemaName   = "MFSPath"
+)
+
+// sharedArgumentSchemas are the shared schemas of the arguments and
+// options, by name. Names used for different kinds of values (e.g. "peer",
+// a peer ID or a multiaddr) are left out, and the arguments meaning
+// something else than their name are listed in argumentSchemas.
+var sharedArgumentSchemas = map[string]string{
+	"cid":            cidSchemaName,
+	"obj":            cidSchemaName,
+	"peerID":         peerIDSchemaName,
+	"peerid":         peerIDSchemaName,
+	"peer ID":        peerIDSchemaName,
+	"ID":             peerIDSchemaName,
+	"address":        multiaddrSchemaName,
+	"listen-address": multiaddrSchemaName,
+	"target-address": multiaddrSchemaName,
+	"topic":          multibaseSchemaName,
+	"ipfs-path":      ipfsPathSchemaName,
+	"from-path":      ipfsPathSchemaName,
+	"to-path":        ipfsPathSchemaName,
+	// "path" is only used by the files commands.
+	"path":     mfsPathSchemaName,
+	"dest":     mfsPathSchemaName,
+	"to-files": mfsPathSchemaName,
+}
+
+// sharedArgumentSchema returns the name of the shared schema of the
+// argument or option, from argumentSchemas or else sharedArgumentSchemas,
+// or "" for none.
+func sharedArgumentSchema(arg *Argument) string {
+	if name, ok := argumentSchemas[arg.Endpoint][arg.Name]; ok {
+		return name
+	}
+	return sharedArgumentSchemas[arg.Name]
+}
+
+// sharedSchemas returns the component schemas of the identifiers, by name.
+func sharedSchemas() map[string]openapi3.SchemaOrRef {
+	cid := stringSchema().
+		WithDescription("A CID, as a CIDv0 (base58btc, starting with Qm) or a multibase-encoded CIDv1.").
+		WithPattern("^(Qm[1-9A-HJ-NP-Za-km-z]{44}|[a-zA-Z0-9][a-zA-Z0-9_-]+)$").
+		WithExample("bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi")
+	peerID := stringSchema().
+		WithDescription("A libp2p peer ID, in base58btc or as a CIDv1 of the libp2p-key codec.").
+		WithExample("12D3KooWGzxzKZYveHXtpG6AsrUJBcWxHBFS2HsEoGTxrMLvKXtf")
+	multiaddr := stringSchema().
+		WithDescription("A multiaddr, in its string representation.").
+		WithPattern("^/").
+		WithExample("/ip4/127.0.0.1/tcp/4001")
+	multibase := stringSchema().
+		WithDescription("Data encoded with multibase: a prefix naming the base, e.g. `u` for base64url, followed by the encoded data.").
+		WithExample("uaGVsbG8")
+	ipfsPath := stringSchema().
+		WithDescription("An IPFS path: a CID, optionally prefixed with /ipfs/, or an /ipns/ name, followed by an optional path within the DAG.").
+		WithFormat("ipfs-path").
+		WithPattern("^(/(ipfs|ipns|ipld)/)?[^/]+(/.*)?$").
+		WithExample("/ipfs/bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi/wiki")
+	mfsPath := stringSchema().
+		WithDescription("An absolute path in the Mutable File System (MFS) of the node, see the files commands.").
+		WithFormat("mfs-path").
+		WithPattern("^/").
+		WithExample("/docs/readme.md")
+	return map[string]openapi3.SchemaOrRef{
+		cidSchemaName:       {Schema: cid},
+		ipfsPathSchemaName:  {Schema: ipfsPath},
+		mfsPathSchemaName:   {Schema: mfsPath},
+		peerIDSchemaName:    {Schema: peerID},
+		multiaddrSchemaName: {Schema: multiaddr},
+		multibaseSchemaName: {Schema: multibase},
+	}
+}
+
+// inlineSchema returns the schema of s, resolving the references to the
+// shared schemas, for documents without these components.
+func inlineSchema(s *openapi3.SchemaOrRef) *openapi3.Schema {
+	if s == nil {
+		return nil
+	}
+	if s.Schema != nil {
+		return s.Schema
+	}
+	name := strings.TrimPrefix(s.SchemaReference.Ref, "#/components/schemas/")
+	return sharedSchemas()[name].Schema
+}
+
+// schemaRef returns a reference to the named component schema.
+func schemaRef(name string) *openapi3.SchemaOrRef {
+	return &openapi3.SchemaOrRef{SchemaReference: &openapi3.SchemaReference{
+		Ref: "#/components/schemas/" + name,
+	}}
+}
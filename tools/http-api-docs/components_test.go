@@ -0,0 +1,84 @@
+package docs
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestSharedArgumentSchemas(t *testing.T) {
+	p := genParameterForArgument(nil, NewArgument("cid", "string", "The CID.", true), true)
+	if p.Schema.SchemaReference == nil || p.Schema.SchemaReference.Ref != "#/components/schemas/CIDString" {
+		t.Errorf("cid doesn't reference CIDString: %+v", p.Schema)
+	}
+	p = genParameterForArgument(nil, NewArgument("address", "array", "Multiaddrs.", false), false)
+	if p.Schema.Schema == nil || p.Schema.Schema.Items.SchemaReference.Ref != "#/components/schemas/Multiaddr" {
+		t.Errorf("address items don't reference Multiaddr: %+v", p.Schema)
+	}
+	p = genParameterForArgument(nil, NewArgument("name", "string", "A name.", true), true)
+	if p.Schema.Schema == nil {
+		t.Errorf("name references a shared schema: %+v", p.Schema)
+	}
+	if inlineSchema(genParameterForArgument(nil, NewArgument("topic", "string", "Topic.", true), true).Schema) == nil {
+		t.Error("couldn't inline the schema of topic")
+	}
+}
+
+func TestPathSchemas(t *testing.T) {
+	for name, want := range map[string]string{"ipfs-path": "ipfs-path", "to-path": "ipfs-path", "path": "mfs-path", "to-files": "mfs-path"} {
+		s := inlineSchema(genParameterForArgument(nil, NewArgument(name, "string", "A path.", true), true).Schema)
+		if s == nil || s.Format == nil || *s.Format != want || s.Pattern == nil || s.Example == nil {
+			t.Errorf("%s: got %+v, want format %s with a pattern and an example", name, s, want)
+		}
+	}
+
+	// The block commands take paths to blocks, not only CIDs.
+	block := NewArgument("cid", "string", "The CID of an existing block to get.", true)
+	block.Endpoint = "/api/v0/block/get"
+	if p := genParameterForArgument(nil, block, true); p.Schema.SchemaReference == nil || p.Schema.SchemaReference.Ref != "#/components/schemas/IPFSPath" {
+		t.Errorf("the cid of block/get doesn't reference IPFSPath: %+v", p.Schema)
+	}
+
+	// The ipfs-path of mount is a local mountpoint, e.g. /mnt/ipfs.
+	mountpoint := NewOption("ipfs-path", "string", "The path where IPFS should be mounted.", "")
+	mountpoint.Endpoint = "/api/v0/mount"
+	if s := genParameterForArgument(nil, mountpoint, false).Schema.Schema; s == nil || s.Pattern != nil {
+		t.Errorf("the mountpoint of mount should be unpatterned: %+v", s)
+	}
+
+	for _, c := range []struct {
+		schema string
+		path   string
+		valid  bool
+	}{
+		{ipfsPathSchemaName, "/ipfs/bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi/wiki", true},
+		{ipfsPathSchemaName, "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG", true},
+		{ipfsPathSchemaName, "/ipns/example.com", true},
+		{ipfsPathSchemaName, "/files/readme.md", false},
+		{mfsPathSchemaName, "/docs/readme.md", true},
+		{mfsPathSchemaName, "docs/readme.md", false},
+	} {
+		pattern := regexp.MustCompile(*sharedSchemas()[c.schema].Schema.Pattern)
+		if pattern.MatchString(c.path) != c.valid {
+			t.Errorf("%s: %q should be valid: %v", c.schema, c.path, c.valid)
+		}
+	}
+}
+
+func TestSharedResponseSchemas(t *testing.T) {
+	doc := map[string]any{"ID": "<peer-id>", "Addrs": []any{"<multiaddr-string>"}, "Name": "<string>"}
+	s := genSchemaOrRefForResponse(nil, nil, doc, true)
+	if ref := s.Schema.Properties["ID"].SchemaReference; ref == nil || ref.Ref != "#/components/schemas/PeerID" {
+		t.Errorf("ID doesn't reference PeerID: %+v", s.Schema.Properties["ID"])
+	}
+	if ref := s.Schema.Properties["Addrs"].Schema.Items.SchemaReference; ref == nil || ref.Ref != "#/components/schemas/Multiaddr" {
+		t.Errorf("Addrs don't reference Multiaddr")
+	}
+	if s.Schema.Properties["Name"].Schema == nil {
+		t.Errorf("Name is a reference")
+	}
+
+	inline := genSchemaForResponse(nil, nil, doc)
+	if inline.Properties["ID"].Schema == nil || inline.Properties["Addrs"].Schema.Items.Schema == nil {
+		t.Errorf("genSchemaForResponse returned references: %+v", inline.Properties)
+	}
+}
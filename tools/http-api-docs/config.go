@@ -0,0 +1,134 @@
+package docs
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile is the configuration file read from the current directory
+// when no other one is given.
+const ConfigFile = "http-api-docs.yaml"
+
+// EnvPrefix is the prefix of the environment variables setting the flags
+// of the tools, e.g. IPFS_API_DOCS_KUBO_VERSION for -kubo-version.
+const EnvPrefix = "IPFS_API_DOCS_"
+
+// Configure sets the flags which were not given on the command line from
+// the environment, then from the section of the tool in the configuration
+// file, so that the command line wins over the environment, which wins over
+// the file. Example of configuration file:
+//
+//	http-api-docs:
+//	  formatter: openapi,markdown
+//	  kubo-version: 0.30.0
+//	  overlay: overrides.yaml
+//	  server-url: [http://127.0.0.1:5001, https://rpc.example.com]
+//	  out-dir: generated
+//
+// Values are the ones given on the command line; repeatable flags take a
+// list. The environment variable of a flag is its name, in upper case with
+// underscores, after EnvPrefix; it holds a single value.
+//
+// The file is path, or the one named by the IPFS_API_DOCS_CONFIG variable,
+// or ConfigFile if it exists. The "config" flag, if any, is not
+// configurable.
+func Configure(flags *flag.FlagSet, tool, path string) error {
+	explicit := path != "" || os.Getenv(EnvPrefix+"CONFIG") != ""
+	set := map[string]bool{"config": true}
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	var err error
+	flags.VisitAll(func(f *flag.Flag) {
+		if set[f.Name] || err != nil {
+			return
+		}
+		if value, ok := os.LookupEnv(envName(f.Name)); ok {
+			set[f.Name] = true
+			if e := f.Value.Set(value); e != nil {
+				err = fmt.Errorf("%s: %w", envName(f.Name), e)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	path = configPath(path)
+	config, err := readConfig(path, explicit)
+	if err != nil || config == nil {
+		return err
+	}
+	values := config[tool]
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := flags.Lookup(name)
+		if f == nil || name == "config" {
+			return fmt.Errorf("%s: unknown option %q of %s", path, name, tool)
+		}
+		if set[name] {
+			continue
+		}
+		// The values are set as written, e.g. 0.30 is not the number 0.3.
+		node := values[name]
+		list := []*yaml.Node{&node}
+		if node.Kind == yaml.SequenceNode {
+			list = node.Content
+		}
+		for _, v := range list {
+			if v.Kind != yaml.ScalarNode {
+				return fmt.Errorf("%s: %s: expected a value or a list of values", path, name)
+			}
+			if err := f.Value.Set(v.Value); err != nil {
+				return fmt.Errorf("%s: %s: %w", path, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// envName returns the environment variable setting a flag.
+func envName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// configSections are the options of each tool in a configuration file.
+type configSections map[string]map[string]yaml.Node
+
+// configPath returns the path of the configuration file (see Configure).
+func configPath(path string) string {
+	if path == "" {
+		path = os.Getenv(EnvPrefix + "CONFIG")
+	}
+	if path == "" {
+		path = ConfigFile
+	}
+	return path
+}
+
+// readConfig reads a configuration file. It returns nil when the file
+// doesn't exist, unless it was given explicitly.
+func readConfig(path string, explicit bool) (configSections, error) {
+	var config configSections
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
@@ -0,0 +1,75 @@
+package docs
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// listFlag is a repeatable flag.
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ",") }
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func TestConfigure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+http-api-docs:
+  title: From the file
+  kubo-version: 0.30
+  api-version: from the file
+  security: true
+  server-url: [http://a, http://b]
+http-api-lint:
+  format: text
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("IPFS_API_DOCS_API_VERSION", "from the environment")
+	t.Setenv("IPFS_API_DOCS_TITLE", "from the environment")
+
+	fs := flag.NewFlagSet("http-api-docs", flag.ContinueOnError)
+	title := fs.String("title", "", "")
+	apiVersion := fs.String("api-version", "", "")
+	kuboVersion := fs.String("kubo-version", "", "")
+	security := fs.Bool("security", false, "")
+	var servers listFlag
+	fs.Var(&servers, "server-url", "")
+	if err := fs.Parse([]string{"-title", "From the command line"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Configure(fs, "http-api-docs", path); err != nil {
+		t.Fatal(err)
+	}
+
+	if *title != "From the command line" || *apiVersion != "from the environment" {
+		t.Errorf("the command line should win over the environment, which wins over the file: got %q and %q", *title, *apiVersion)
+	}
+	if *kuboVersion != "0.30" || !*security || servers.String() != "http://a,http://b" {
+		t.Errorf("unexpected values from the file: %q, %v, %q", *kuboVersion, *security, servers.String())
+	}
+}
+
+func TestConfigureErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("http-api-docs:\n  titel: typo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fs := flag.NewFlagSet("http-api-docs", flag.ContinueOnError)
+	fs.String("title", "", "")
+	if err := Configure(fs, "http-api-docs", path); err == nil || !strings.Contains(err.Error(), "titel") {
+		t.Errorf("expected an error for the unknown option, got %v", err)
+	}
+	if err := Configure(fs, "http-api-docs", filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Errorf("expected an error for a missing configuration file given explicitly")
+	}
+}
@@ -0,0 +1,212 @@
+package docs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// Inconsistency is a disagreement between the Markdown reference and the
+// OpenAPI spec generated from the same endpoints.
+type Inconsistency struct {
+	Endpoint string `json:"endpoint"`
+	Message  string `json:"message"`
+}
+
+func (i Inconsistency) String() string {
+	return i.Endpoint + ": " + i.Message
+}
+
+// documentedEndpoint is what the Markdown reference or the OpenAPI spec
+// says about an endpoint.
+type documentedEndpoint struct {
+	deprecated bool
+	// params are the query parameters, by name. The positional arguments
+	// are all "arg".
+	params map[string]documentedParam
+}
+
+type documentedParam struct {
+	// def is the default value as written in the Markdown, "" if none.
+	def      string
+	required bool
+}
+
+// CheckConsistency generates the Markdown reference and the OpenAPI spec of
+// the given endpoints, with the default settings, and returns where they
+// disagree: on the list of endpoints, their deprecation, the names of their
+// parameters and the defaults and requiredness of their options. Both
+// formatters render the same endpoints with logic of their own, so they can
+// drift apart.
+func CheckConsistency(ctx context.Context, api []*Endpoint) ([]Inconsistency, error) {
+	markdown, err := new(MarkdownFormatter).Generate(ctx, api)
+	if err != nil {
+		return nil, err
+	}
+	fromMarkdown := parseMarkdownEndpoints(markdown)
+
+	formatter := new(OpenAPIFormatter)
+	if err := formatter.Build(ctx, api); err != nil {
+		return nil, err
+	}
+	return compareEndpoints(fromMarkdown, specEndpoints(&formatter.spec)), nil
+}
+
+// compareEndpoints returns where the endpoints read from the Markdown and
+// from the spec disagree, sorted by endpoint and parameter.
+func compareEndpoints(fromMarkdown, fromSpec map[string]*documentedEndpoint) []Inconsistency {
+	var found []Inconsistency
+	report := func(endpoint, format string, args ...any) {
+		found = append(found, Inconsistency{Endpoint: endpoint, Message: fmt.Sprintf(format, args...)})
+	}
+	names := make([]string, 0, len(fromMarkdown)+len(fromSpec))
+	for name := range fromMarkdown {
+		names = append(names, name)
+	}
+	for name := range fromSpec {
+		if _, ok := fromMarkdown[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		md, inMarkdown := fromMarkdown[name]
+		spec, inSpec := fromSpec[name]
+		switch {
+		case !inSpec:
+			report(name, "documented in the Markdown, missing from the OpenAPI spec")
+			continue
+		case !inMarkdown:
+			report(name, "documented in the OpenAPI spec, missing from the Markdown")
+			continue
+		}
+		if md.deprecated != spec.deprecated {
+			report(name, "deprecated in the Markdown: %t, in the OpenAPI spec: %t", md.deprecated, spec.deprecated)
+		}
+		params := make([]string, 0, len(md.params))
+		for param := range md.params {
+			params = append(params, param)
+		}
+		for param := range spec.params {
+			if _, ok := md.params[param]; !ok {
+				params = append(params, param)
+			}
+		}
+		sort.Strings(params)
+		for _, param := range params {
+			mdParam, inMarkdown := md.params[param]
+			specParam, inSpec := spec.params[param]
+			switch {
+			case !inSpec:
+				report(name, "parameter %s is documented in the Markdown, missing from the OpenAPI spec", param)
+			case !inMarkdown:
+				report(name, "parameter %s is documented in the OpenAPI spec, missing from the Markdown", param)
+			case param == "arg":
+				// The spec merges the positional arguments into one
+				// parameter.
+			case mdParam.def != specParam.def:
+				report(name, "default of %s in the Markdown: %q, in the OpenAPI spec: %q", param, mdParam.def, specParam.def)
+			case mdParam.required != specParam.required:
+				report(name, "%s is required in the Markdown: %t, in the OpenAPI spec: %t", param, mdParam.required, specParam.required)
+			}
+		}
+	}
+	return found
+}
+
+// markdownParameter matches the documentation of a parameter in the
+// "argument" template. The descriptions can span several lines.
+var markdownParameter = regexp.MustCompile("(?s)^- `([^`]+)` \\[[^\\]]+\\]: .*?(?: Default: `([^`]*)`(?: \\([^)]*\\))?\\.)? Required: (\\*\\*yes\\*\\*|no)\\.$")
+
+// parseMarkdownEndpoints reads the endpoints back from the Markdown
+// reference, by their "## /api/v0/..." headings.
+func parseMarkdownEndpoints(markdown string) map[string]*documentedEndpoint {
+	endpoints := make(map[string]*documentedEndpoint)
+	var current *documentedEndpoint
+	// argument accumulates the lines of the parameter being read.
+	var argument string
+	for _, line := range strings.Split(markdown, "\n") {
+		if heading, ok := strings.CutPrefix(line, "## "); ok {
+			current, argument = nil, ""
+			if strings.HasPrefix(heading, "/") {
+				current = &documentedEndpoint{params: make(map[string]documentedParam)}
+				endpoints[heading] = current
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if strings.HasPrefix(line, "- `") {
+			argument = line
+		} else if argument != "" {
+			argument += "\n" + line
+		}
+		if argument != "" {
+			if m := markdownParameter.FindStringSubmatch(argument); m != nil {
+				current.params[m[1]] = documentedParam{def: m[2], required: m[3] == "**yes**"}
+				argument = ""
+			}
+			continue
+		}
+		if label, ok := strings.CutPrefix(line, "::: warning "); ok {
+			current.deprecated = label == strings.ToUpper(statusLabel(cmds.Deprecated)) || label == strings.ToUpper(statusLabel(cmds.Removed))
+		}
+	}
+	return endpoints
+}
+
+// specEndpoints reads the endpoints from the operations of an OpenAPI
+// spec. The global parameters, which are references, are left out, as the
+// Markdown documents them once in its intro.
+func specEndpoints(spec *openapi3.Spec) map[string]*documentedEndpoint {
+	endpoints := make(map[string]*documentedEndpoint)
+	for path, item := range spec.Paths.MapOfPathItemValues {
+		op, ok := item.MapOfOperationValues["post"]
+		if !ok {
+			continue
+		}
+		endp := &documentedEndpoint{
+			deprecated: op.Deprecated != nil && *op.Deprecated,
+			params:     make(map[string]documentedParam),
+		}
+		for _, p := range op.Parameters {
+			if p.Parameter == nil {
+				continue
+			}
+			param := documentedParam{required: p.Parameter.Required != nil && *p.Parameter.Required}
+			if s := p.Parameter.Schema; s != nil && s.Schema != nil && s.Schema.Default != nil {
+				param.def = specDefault(*s.Schema.Default)
+			}
+			endp.params[p.Parameter.Name] = param
+		}
+		endpoints[path] = endp
+	}
+	return endpoints
+}
+
+// specDefault renders a default value of the spec as the Markdown does.
+func specDefault(v any) string {
+	if list, ok := v.([]any); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+// WriteInconsistencies prints the inconsistencies, one per line.
+func WriteInconsistencies(w io.Writer, found []Inconsistency) {
+	for _, i := range found {
+		fmt.Fprintln(w, i)
+	}
+}
@@ -0,0 +1,79 @@
+package docs
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	fixture, _ := fixtureEndpoints(t)
+	for name, api := range map[string][]*Endpoint{"kubo": AllEndpoints(), "fixture": fixture} {
+		found, err := CheckConsistency(context.Background(), api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, i := range found {
+			t.Errorf("%s: %s", name, i)
+		}
+	}
+}
+
+func TestParseMarkdownEndpoints(t *testing.T) {
+	markdown := "## /api/v0/a\n\n" +
+		"::: warning DEPRECATED\n\n" +
+		"- `arg` [string]: The path. Required: **yes**.\n" +
+		"- `interval` [string]: How long to wait.\n\n    On two lines. Default: `1s` (1 second). Required: no.\n" +
+		"- `quiet` [bool]: Write less. Required: no.\n\n" +
+		"## Response\n\n- `ignored` [string]: Not a parameter. Required: no.\n\n" +
+		"## /api/v0/b\n"
+	got := parseMarkdownEndpoints(markdown)
+	want := map[string]*documentedEndpoint{
+		"/api/v0/a": {deprecated: true, params: map[string]documentedParam{
+			"arg":      {required: true},
+			"interval": {def: "1s"},
+			"quiet":    {},
+		}},
+		"/api/v0/b": {params: map[string]documentedParam{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestCompareEndpoints(t *testing.T) {
+	fromMarkdown := map[string]*documentedEndpoint{
+		"/api/v0/a": {params: map[string]documentedParam{
+			"arg":   {required: true},
+			"depth": {def: "1"},
+			"quiet": {},
+		}},
+		"/api/v0/b": {deprecated: true, params: map[string]documentedParam{}},
+		"/api/v0/c": {params: map[string]documentedParam{}},
+	}
+	fromSpec := map[string]*documentedEndpoint{
+		"/api/v0/a": {params: map[string]documentedParam{
+			"arg":   {},
+			"depth": {def: "2"},
+			"quiet": {required: true},
+			"v":     {},
+		}},
+		"/api/v0/b": {params: map[string]documentedParam{}},
+		"/api/v0/d": {params: map[string]documentedParam{}},
+	}
+	var got []string
+	for _, i := range compareEndpoints(fromMarkdown, fromSpec) {
+		got = append(got, i.String())
+	}
+	want := []string{
+		`/api/v0/a: default of depth in the Markdown: "1", in the OpenAPI spec: "2"`,
+		"/api/v0/a: quiet is required in the Markdown: false, in the OpenAPI spec: true",
+		"/api/v0/a: parameter v is documented in the OpenAPI spec, missing from the Markdown",
+		"/api/v0/b: deprecated in the Markdown: true, in the OpenAPI spec: false",
+		"/api/v0/c: documented in the Markdown, missing from the OpenAPI spec",
+		"/api/v0/d: documented in the OpenAPI spec, missing from the Markdown",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
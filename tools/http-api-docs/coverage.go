@@ -0,0 +1,121 @@
+package docs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// Coverage tells how many endpoints are completely documented: with a
+// description of the endpoint and of all its options, a response schema and
+// a response example.
+type Coverage struct {
+	Endpoints   int `json:"endpoints"`
+	Described   int `json:"described"`
+	WithSchema  int `json:"withSchema"`
+	WithExample int `json:"withExample"`
+	Complete    int `json:"complete"`
+	// Incomplete lists what is missing from each incomplete endpoint.
+	Incomplete map[string][]string `json:"incomplete"`
+}
+
+// Percent is the percentage of complete endpoints, 100 when there are no
+// endpoints.
+func (c *Coverage) Percent() float64 {
+	return percent(c.Complete, c.Endpoints)
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(n) * 100 / float64(total)
+}
+
+// Coverage generates the operations of the given endpoints and measures
+// their documentation coverage. Only JSON responses need an example, and
+// plain text ones need no schema.
+func (myself *OpenAPIFormatter) Coverage(ctx context.Context, api []*Endpoint) (*Coverage, error) {
+	if err := myself.Build(ctx, api); err != nil {
+		return nil, err
+	}
+
+	coverage := &Coverage{Endpoints: len(api), Incomplete: make(map[string][]string)}
+	for _, endp := range api {
+		var missing []string
+		if endp.Description == "" {
+			missing = append(missing, "description")
+		}
+		for _, opt := range endp.Options {
+			if opt.Description == "" {
+				missing = append(missing, "description of option "+opt.Name)
+			}
+		}
+		if len(missing) == 0 {
+			coverage.Described++
+		}
+
+		var resp *openapi3.Response
+		if op, ok := myself.spec.Paths.MapOfPathItemValues[endp.Name].MapOfOperationValues["post"]; ok {
+			resp = op.Responses.MapOfResponseOrRefValues["200"].Response
+		}
+		if missingResponseSchema(resp) == "" {
+			coverage.WithSchema++
+		} else {
+			missing = append(missing, "response schema")
+		}
+		if hasResponseExample(resp) {
+			coverage.WithExample++
+		} else {
+			missing = append(missing, "response example")
+		}
+
+		if len(missing) == 0 {
+			coverage.Complete++
+		} else {
+			coverage.Incomplete[endp.Name] = missing
+		}
+	}
+	return coverage, nil
+}
+
+// hasResponseExample tells whether the JSON media types of a response have
+// an example, like the response-example lint rule.
+func hasResponseExample(resp *openapi3.Response) bool {
+	if resp == nil {
+		return false
+	}
+	for mime, media := range resp.Content {
+		if !strings.HasSuffix(mime, "json") {
+			continue
+		}
+		if media.Example == nil && len(media.Examples) == 0 && (media.Schema == nil || media.Schema.Schema == nil || media.Schema.Schema.Example == nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteCoverage writes a summary of the coverage, followed by what is
+// missing from each incomplete endpoint if verbose is set.
+func WriteCoverage(out io.Writer, c *Coverage, verbose bool) {
+	fmt.Fprintf(out, "described:    %5.1f%% (%d/%d)\n", percent(c.Described, c.Endpoints), c.Described, c.Endpoints)
+	fmt.Fprintf(out, "with schema:  %5.1f%% (%d/%d)\n", percent(c.WithSchema, c.Endpoints), c.WithSchema, c.Endpoints)
+	fmt.Fprintf(out, "with example: %5.1f%% (%d/%d)\n", percent(c.WithExample, c.Endpoints), c.WithExample, c.Endpoints)
+	fmt.Fprintf(out, "complete:     %5.1f%% (%d/%d)\n", c.Percent(), c.Complete, c.Endpoints)
+	if !verbose {
+		return
+	}
+	names := make([]string, 0, len(c.Incomplete))
+	for name := range c.Incomplete {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "%s: no %s\n", name, strings.Join(c.Incomplete[name], ", no "))
+	}
+}
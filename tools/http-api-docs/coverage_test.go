@@ -0,0 +1,46 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	api := []*Endpoint{
+		{Name: "/coverage/v0/json", Description: "JSON.", Response: `{"Name": "<string>"}`},
+		{Name: "/coverage/v0/text", Description: "Text.", Response: textResponse},
+		{Name: "/coverage/v0/stream", Description: "Stream.", Response: textResponse, Streaming: true},
+		{
+			Name:     "/coverage/v0/bare",
+			Options:  []*Argument{{Name: "quiet", Type: "bool"}},
+			Response: `{"Name": "<string>"}`,
+		},
+	}
+	c, err := new(OpenAPIFormatter).Coverage(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Endpoints != 4 || c.Described != 3 || c.WithSchema != 4 || c.WithExample != 3 || c.Complete != 2 {
+		t.Errorf("unexpected coverage %+v", c)
+	}
+	if c.Percent() != 50 {
+		t.Errorf("got %v%%, want 50%%", c.Percent())
+	}
+	want := map[string][]string{
+		"/coverage/v0/bare":   {"description", "description of option quiet"},
+		"/coverage/v0/stream": {"response example"},
+	}
+	if !reflect.DeepEqual(c.Incomplete, want) {
+		t.Errorf("got incomplete endpoints %v, want %v", c.Incomplete, want)
+	}
+
+	buf := new(bytes.Buffer)
+	WriteCoverage(buf, c, true)
+	if !strings.Contains(buf.String(), "complete:      50.0% (2/4)") || !strings.Contains(buf.String(), "/coverage/v0/stream: no response example") {
+		t.Errorf("unexpected summary:\n%s", buf)
+	}
+}
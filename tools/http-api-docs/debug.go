@@ -0,0 +1,169 @@
+package docs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// This file describes the debug endpoints which the daemon serves next to
+// the RPC API, on Addresses.API: the expvar and pprof handlers of the Go
+// runtime, the Prometheus metrics and the profiling knobs of Kubo. They are
+// not commands, so they are modeled by hand, in a document of their own
+// which also gathers the /api/v0/diag commands.
+
+// DebugFormatter generates the OpenAPI spec of the debug endpoints of the
+// daemon and of the diag commands.
+type DebugFormatter struct {
+	// Info overrides the default metadata. The default server is the one
+	// of the RPC API.
+	Info OpenAPIInfo
+	// Metrics is the registry the metric families of
+	// /debug/metrics/prometheus are gathered from, the one of Kubo
+	// (prometheus.DefaultGatherer) when nil.
+	Metrics prometheus.Gatherer
+	// RecordedMetrics are the metric families scraped from a daemon by
+	// RecordExamples (see RecordedMetricFamilies).
+	RecordedMetrics []MetricFamily
+}
+
+// debugPrefix is the prefix of the endpoints of the diag commands included
+// in the debug spec.
+const debugPrefix = APIPrefix + "/diag/"
+
+// pprofProfiles are the runtime profiles served under /debug/pprof/.
+var pprofProfiles = []struct {
+	name, description string
+}{
+	{"allocs", "A sampling of all past memory allocations."},
+	{"block", "Stack traces that led to blocking on synchronization primitives. Empty unless the block profile rate is set (see /debug/pprof-block/)."},
+	{"goroutine", "Stack traces of all current goroutines."},
+	{"heap", "A sampling of the memory allocations of live objects."},
+	{"mutex", "Stack traces of holders of contended mutexes. Empty unless the mutex profile fraction is set (see /debug/pprof-mutex/)."},
+	{"threadcreate", "Stack traces that led to the creation of new OS threads."},
+}
+
+// Generate returns the spec as YAML, with the operations of the diag
+// endpoints of api.
+func (df *DebugFormatter) Generate(ctx context.Context, api []*Endpoint) (string, error) {
+	info := df.Info
+	if info.Title == "" {
+		info.Title = "Kubo debug endpoints"
+	}
+	if info.Description == "" {
+		info.Description = "Runtime statistics and profiles of the Kubo daemon, served on `Addresses.API` next to the RPC API, and the `/api/v0/diag` commands. " +
+			"They are meant for operators and are not part of the stable RPC API."
+	}
+	rpc := &OpenAPIFormatter{Info: info}
+	var diag []*Endpoint
+	for _, endp := range api {
+		if strings.HasPrefix(endp.Name, debugPrefix) {
+			diag = append(diag, endp)
+		}
+	}
+	if err := rpc.Build(ctx, diag); err != nil {
+		return "", err
+	}
+
+	families, err := MetricFamilies(df.Metrics, df.RecordedMetrics)
+	if err != nil {
+		return "", fmt.Errorf("gathering the metrics: %w", err)
+	}
+	ops := append(debugOperations(), debugOperation{http.MethodGet, metricsPath, genMetricsOperation(families)})
+	for _, o := range ops {
+		if err := rpc.spec.AddOperation(o.method, o.path, o.op); err != nil {
+			return "", fmt.Errorf("%s %s: %w", o.method, o.path, err)
+		}
+	}
+	return rpc.SpecYAML()
+}
+
+type debugOperation struct {
+	method, path string
+	op           openapi3.Operation
+}
+
+// debugOperations returns the operations of the debug endpoints.
+func debugOperations() []debugOperation {
+	binary := stringSchema().WithFormat("binary")
+	ops := []debugOperation{
+		{http.MethodGet, "/debug/vars", debugOp("debugVars", "Runtime statistics",
+			"The variables published with expvar: the command line, the memory statistics of the Go runtime and the ones of Kubo.",
+			"application/json", (&openapi3.Schema{}).WithType(openapi3.SchemaTypeObject).
+				WithProperties(map[string]openapi3.SchemaOrRef{
+					"cmdline":  {Schema: (&openapi3.Schema{}).WithType(openapi3.SchemaTypeArray).WithItems(openapi3.SchemaOrRef{Schema: stringSchema()})},
+					"memstats": {Schema: (&openapi3.Schema{}).WithType(openapi3.SchemaTypeObject).WithDescription("runtime.MemStats")},
+				}))},
+		{http.MethodGet, "/debug/stack", debugOp("debugStack", "Goroutine stacks",
+			"The stack traces of all goroutines, as in a panic.", "text/plain", stringSchema())},
+		{http.MethodGet, "/debug/pprof/", debugOp("debugPprofIndex", "Index of the profiles",
+			"An HTML page linking to the available profiles.", "text/html", stringSchema())},
+		{http.MethodGet, "/debug/pprof/cmdline", debugOp("debugPprofCmdline", "Command line",
+			"The command line of the daemon, with its arguments separated by NUL bytes.", "text/plain", stringSchema())},
+		{http.MethodGet, "/debug/pprof/profile", withParams(debugOp("debugPprofProfile", "CPU profile",
+			"A CPU profile in the pprof format, for `go tool pprof`.", "application/octet-stream", binary), secondsParam(30))},
+		{http.MethodGet, "/debug/pprof/trace", withParams(debugOp("debugPprofTrace", "Execution trace",
+			"An execution trace, for `go tool trace`.", "application/octet-stream", binary), secondsParam(1))},
+		{http.MethodGet, "/debug/pprof/symbol", debugOp("debugPprofSymbol", "Symbol lookup",
+			"The number of symbols, or with program counters in the query or body, their function names.", "text/plain", stringSchema())},
+		{http.MethodPost, "/debug/pprof-mutex/", withParams(debugSettingOp("debugSetMutexFraction", "Set the mutex profile fraction",
+			"Calls runtime.SetMutexProfileFraction: on average 1/fraction of the mutex contention events are reported. 0 disables the mutex profile."),
+			specParam{"fraction", openapi3.ParameterInQuery, "Rate of the mutex profile.", true, integerSchema()}.parameter())},
+		{http.MethodPost, "/debug/pprof-block/", withParams(debugSettingOp("debugSetBlockRate", "Set the block profile rate",
+			"Calls runtime.SetBlockProfileRate: one blocking event every rate nanoseconds is sampled. 0 disables the block profile."),
+			specParam{"rate", openapi3.ParameterInQuery, "Rate of the block profile, in nanoseconds.", true, integerSchema()}.parameter())},
+	}
+	for _, p := range pprofProfiles {
+		op := debugOp("debugPprof"+strings.ToUpper(p.name[:1])+p.name[1:], "Profile: "+p.name, p.description,
+			"application/octet-stream", binary)
+		media := op.Responses.MapOfResponseOrRefValues["200"].Response.Content
+		media["text/plain"] = openapi3.MediaType{Schema: &openapi3.SchemaOrRef{Schema: stringSchema()}}
+		ops = append(ops, debugOperation{http.MethodGet, "/debug/pprof/" + p.name, withParams(op,
+			specParam{"debug", openapi3.ParameterInQuery, "0 (the default) returns the profile in the pprof format, 1 and 2 as text.", false, integerSchema()}.parameter(),
+			specParam{"gc", openapi3.ParameterInQuery, "heap: Run a garbage collection before taking the profile when set to a positive number.", false, integerSchema()}.parameter(),
+			specParam{"seconds", openapi3.ParameterInQuery, "Return the difference between the profile after this many seconds and now.", false, integerSchema()}.parameter(),
+		)})
+	}
+	return ops
+}
+
+// debugOp returns the operation of a debug endpoint returning a body of
+// the given content type.
+func debugOp(id, summary, description, mime string, schema *openapi3.Schema) openapi3.Operation {
+	op := openapi3.Operation{ID: &id, Summary: &summary, Description: &description, Tags: []string{"debug"}}
+	op.Responses.WithMapOfResponseOrRefValuesItem("200", openapi3.ResponseOrRef{Response: &openapi3.Response{
+		Description: "Successful response",
+		Content:     map[string]openapi3.MediaType{mime: {Schema: &openapi3.SchemaOrRef{Schema: schema}}},
+	}})
+	return op
+}
+
+// debugSettingOp returns the operation of a debug endpoint changing a
+// setting of the runtime, which has an empty response.
+func debugSettingOp(id, summary, description string) openapi3.Operation {
+	op := openapi3.Operation{ID: &id, Summary: &summary, Description: &description, Tags: []string{"debug"}}
+	op.Responses.WithMapOfResponseOrRefValuesItem("200", openapi3.ResponseOrRef{Response: &openapi3.Response{
+		Description: "The setting was changed.",
+	}})
+	op.Responses.WithMapOfResponseOrRefValuesItem("400", openapi3.ResponseOrRef{Response: &openapi3.Response{
+		Description: "The parameter is missing or not an integer.",
+		Content:     map[string]openapi3.MediaType{"text/plain": {Schema: &openapi3.SchemaOrRef{Schema: stringSchema()}}},
+	}})
+	return op
+}
+
+func withParams(op openapi3.Operation, params ...openapi3.ParameterOrRef) openapi3.Operation {
+	op.Parameters = append(op.Parameters, params...)
+	return op
+}
+
+// secondsParam is the duration parameter of the profiles taken over time.
+func secondsParam(def int) openapi3.ParameterOrRef {
+	param := specParam{"seconds", openapi3.ParameterInQuery, "Duration of the profile, in seconds.", false, integerSchema()}.parameter()
+	param.Parameter.Schema.Schema.WithDefault(def)
+	return param
+}
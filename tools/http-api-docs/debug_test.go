@@ -0,0 +1,46 @@
+package docs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+func TestDebugSpec(t *testing.T) {
+	api := []*Endpoint{
+		{Name: "/api/v0/diag/sys", Response: "This endpoint returns a `text/plain` response body."},
+		{Name: "/api/v0/id"},
+	}
+	out, err := (&DebugFormatter{Info: OpenAPIInfo{Servers: []string{"http://127.0.0.1:5001"}}}).Generate(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec openapi3.Spec
+	if err := spec.UnmarshalYAML([]byte(out)); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Info.Title != "Kubo debug endpoints" || len(spec.Servers) != 1 || spec.Servers[0].URL != "http://127.0.0.1:5001" {
+		t.Errorf("unexpected info or servers: %+v %+v", spec.Info, spec.Servers)
+	}
+	paths := spec.Paths.MapOfPathItemValues
+	if _, ok := paths["/api/v0/diag/sys"]; !ok {
+		t.Errorf("missing the diag commands")
+	}
+	if _, ok := paths["/api/v0/id"]; ok {
+		t.Errorf("only the diag commands should be documented")
+	}
+	heap, ok := paths["/debug/pprof/heap"].MapOfOperationValues["get"]
+	if !ok {
+		t.Fatal("missing the heap profile")
+	}
+	if content := heap.Responses.MapOfResponseOrRefValues["200"].Response.Content; len(content) != 2 {
+		t.Errorf("expected binary and text profiles, got %+v", content)
+	}
+	if _, ok := paths["/debug/vars"].MapOfOperationValues["get"].Responses.MapOfResponseOrRefValues["200"].Response.Content["application/json"]; !ok {
+		t.Errorf("/debug/vars should return JSON")
+	}
+	if _, ok := paths["/debug/pprof-mutex/"].MapOfOperationValues["post"]; !ok {
+		t.Errorf("missing the mutex fraction setting")
+	}
+}
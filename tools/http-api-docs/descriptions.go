@@ -0,0 +1,35 @@
+package docs
+
+import (
+	"bytes"
+	"log"
+	"strings"
+)
+
+// apiDescription introduces the RPC API, in the Markdown reference and the
+// description of the OpenAPI spec.
+const apiDescription = `When a Kubo IPFS node is running as a daemon, it exposes an HTTP RPC API that allows you to control the node and run the same commands you can from the command line.
+
+In many cases, using this RPC API is preferable to embedding IPFS directly in your program — it allows you to maintain peer connections that are longer lived than your app and you can keep a single IPFS node running instead of several if your app can be launched multiple times. In fact, the ` + "`ipfs`" + ` CLI commands use this RPC API when operating in online mode.`
+
+// bodyDescription describes the request body of an endpoint taking the
+// given file argument, without heading, with the default "body-description"
+// template. Formatters other than the MarkdownFormatter use it, so that
+// every output documents the body the same way.
+func bodyDescription(arg *Argument) string {
+	buf := new(bytes.Buffer)
+	if err := markdownTemplates.ExecuteTemplate(buf, "body-description", arg); err != nil {
+		log.Printf("WARN: %s\n", err)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// fileArgument returns the first argument of file type, if any.
+func fileArgument(args []*Argument) *Argument {
+	for _, arg := range args {
+		if arg.Type == "file" {
+			return arg
+		}
+	}
+	return nil
+}
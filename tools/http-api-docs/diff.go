@@ -0,0 +1,237 @@
+package docs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+// APIDiff is the structured difference between the endpoints of two Kubo
+// versions.
+type APIDiff struct {
+	From    string
+	To      string
+	Added   []string        `json:",omitempty"`
+	Removed []string        `json:",omitempty"`
+	Changed []*EndpointDiff `json:",omitempty"`
+}
+
+// EndpointDiff lists the changes of an endpoint present in both versions.
+// Response fields are named by their path in the response, like
+// "Peers[].Addr", and compared by their documented type.
+type EndpointDiff struct {
+	Endpoint         string
+	Status           *Change            `json:",omitempty"`
+	AddedArguments   []string           `json:",omitempty"`
+	RemovedArguments []string           `json:",omitempty"`
+	AddedOptions     []string           `json:",omitempty"`
+	RemovedOptions   []string           `json:",omitempty"`
+	ChangedDefaults  map[string]*Change `json:",omitempty"`
+	ChangedTypes     map[string]*Change `json:",omitempty"`
+	AddedFields      []string           `json:",omitempty"`
+	RemovedFields    []string           `json:",omitempty"`
+	ChangedFields    map[string]*Change `json:",omitempty"`
+}
+
+// Change is a value which changed between two versions.
+type Change struct {
+	From string
+	To   string
+}
+
+func (d *EndpointDiff) empty() bool {
+	return d.Status == nil && len(d.AddedArguments)+len(d.RemovedArguments)+
+		len(d.AddedOptions)+len(d.RemovedOptions)+len(d.ChangedDefaults)+len(d.ChangedTypes)+
+		len(d.AddedFields)+len(d.RemovedFields)+len(d.ChangedFields) == 0
+}
+
+// DiffEndpoints compares two endpoint dumps.
+func DiffEndpoints(from, to *EndpointsDump) *APIDiff {
+	diff := &APIDiff{From: from.KuboVersion, To: to.KuboVersion}
+	old := make(map[string]*Endpoint, len(from.Endpoints))
+	for _, endp := range from.Endpoints {
+		old[endp.Name] = endp
+	}
+	seen := make(map[string]bool, len(to.Endpoints))
+	for _, endp := range to.Endpoints {
+		seen[endp.Name] = true
+		prev, ok := old[endp.Name]
+		if !ok {
+			diff.Added = append(diff.Added, endp.Name)
+			continue
+		}
+		if d := diffEndpoint(prev, endp); !d.empty() {
+			diff.Changed = append(diff.Changed, d)
+		}
+	}
+	for _, endp := range from.Endpoints {
+		if !seen[endp.Name] {
+			diff.Removed = append(diff.Removed, endp.Name)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Endpoint < diff.Changed[j].Endpoint })
+	return diff
+}
+
+func diffEndpoint(from, to *Endpoint) *EndpointDiff {
+	d := &EndpointDiff{Endpoint: to.Name}
+	if from.Status != to.Status {
+		d.Status = &Change{From: statusName(from.Status), To: statusName(to.Status)}
+	}
+
+	d.AddedArguments, d.RemovedArguments, _ = diffArguments(from.Arguments, to.Arguments)
+	var common [][2]*Argument
+	d.AddedOptions, d.RemovedOptions, common = diffArguments(from.Options, to.Options)
+	for _, pair := range common {
+		if pair[0].Default != pair[1].Default {
+			if d.ChangedDefaults == nil {
+				d.ChangedDefaults = make(map[string]*Change)
+			}
+			d.ChangedDefaults[pair[1].Name] = &Change{From: pair[0].Default, To: pair[1].Default}
+		}
+		if pair[0].Type != pair[1].Type {
+			if d.ChangedTypes == nil {
+				d.ChangedTypes = make(map[string]*Change)
+			}
+			d.ChangedTypes[pair[1].Name] = &Change{From: pair[0].Type, To: pair[1].Type}
+		}
+	}
+
+	oldFields, newFields := responseFields(from.Response), responseFields(to.Response)
+	for field, typ := range newFields {
+		prev, ok := oldFields[field]
+		switch {
+		case !ok:
+			d.AddedFields = append(d.AddedFields, field)
+		case prev != typ:
+			if d.ChangedFields == nil {
+				d.ChangedFields = make(map[string]*Change)
+			}
+			d.ChangedFields[field] = &Change{From: prev, To: typ}
+		}
+	}
+	for field := range oldFields {
+		if _, ok := newFields[field]; !ok {
+			d.RemovedFields = append(d.RemovedFields, field)
+		}
+	}
+	sort.Strings(d.AddedFields)
+	sort.Strings(d.RemovedFields)
+	return d
+}
+
+func statusName(status cmds.Status) string {
+	if status == cmds.Active {
+		return "Active"
+	}
+	return statusLabel(status)
+}
+
+// diffArguments returns the names of the added and removed arguments, and
+// the pairs of arguments present in both lists.
+func diffArguments(from, to []*Argument) (added, removed []string, common [][2]*Argument) {
+	old := make(map[string]*Argument, len(from))
+	for _, arg := range from {
+		old[arg.Name] = arg
+	}
+	seen := make(map[string]bool, len(to))
+	for _, arg := range to {
+		seen[arg.Name] = true
+		if prev, ok := old[arg.Name]; ok {
+			common = append(common, [2]*Argument{prev, arg})
+		} else {
+			added = append(added, arg.Name)
+		}
+	}
+	for _, arg := range from {
+		if !seen[arg.Name] {
+			removed = append(removed, arg.Name)
+		}
+	}
+	return added, removed, common
+}
+
+// responseFields flattens a documented JSON response into the types of its
+// fields, indexed by path. Text responses have no fields.
+func responseFields(response string) map[string]string {
+	fields := make(map[string]string)
+	var doc any
+	if err := json.Unmarshal([]byte(response), &doc); err != nil {
+		return fields
+	}
+	var walk func(path string, v any)
+	walk = func(path string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for key, item := range v {
+				if key == "<string>" {
+					key = "<key>"
+				}
+				walk(strings.TrimPrefix(path+"."+key, "."), item)
+			}
+		case []any:
+			for _, item := range v {
+				walk(path+"[]", item)
+			}
+		default:
+			if path != "" {
+				fields[path] = fmt.Sprint(v)
+			}
+		}
+	}
+	walk("", doc)
+	return fields
+}
+
+// String renders the diff as a human-readable report.
+func (diff *APIDiff) String() string {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "Changes from Kubo %s to %s\n", diff.From, diff.To)
+	if len(diff.Added)+len(diff.Removed)+len(diff.Changed) == 0 {
+		fmt.Fprintln(buf, "\nNo changes.")
+	}
+	if len(diff.Added) > 0 {
+		fmt.Fprintln(buf, "\nAdded endpoints:")
+		for _, name := range diff.Added {
+			fmt.Fprintf(buf, "  + %s\n", name)
+		}
+	}
+	if len(diff.Removed) > 0 {
+		fmt.Fprintln(buf, "\nRemoved endpoints:")
+		for _, name := range diff.Removed {
+			fmt.Fprintf(buf, "  - %s\n", name)
+		}
+	}
+	for _, d := range diff.Changed {
+		fmt.Fprintf(buf, "\n%s:\n", d.Endpoint)
+		if d.Status != nil {
+			fmt.Fprintf(buf, "  status: %s -> %s\n", d.Status.From, d.Status.To)
+		}
+		list := func(prefix, what string, names []string) {
+			for _, name := range names {
+				fmt.Fprintf(buf, "  %s %s %s\n", prefix, what, name)
+			}
+		}
+		changes := func(what string, changes map[string]*Change) {
+			for _, name := range sortedChanges(changes) {
+				fmt.Fprintf(buf, "  ~ %s %s: %q -> %q\n", what, name, changes[name].From, changes[name].To)
+			}
+		}
+		list("+", "argument", d.AddedArguments)
+		list("-", "argument", d.RemovedArguments)
+		list("+", "option", d.AddedOptions)
+		list("-", "option", d.RemovedOptions)
+		changes("default of", d.ChangedDefaults)
+		changes("type of", d.ChangedTypes)
+		list("+", "response field", d.AddedFields)
+		list("-", "response field", d.RemovedFields)
+		changes("response field", d.ChangedFields)
+	}
+	return buf.String()
+}
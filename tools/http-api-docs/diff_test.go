@@ -0,0 +1,70 @@
+package docs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+func TestDiffEndpoints(t *testing.T) {
+	from := &EndpointsDump{KuboVersion: "0.1.0", Endpoints: []*Endpoint{
+		{Name: "/api/v0/gone"},
+		{Name: "/api/v0/same", Response: `{"A": "<string>"}`},
+		{
+			Name:     "/api/v0/changed",
+			Options:  []*Argument{{Name: "old"}, {Name: "limit", Type: "int", Default: "10"}},
+			Response: `{"Peers": [{"ID": "<string>", "Latency": "<string>"}]}`,
+		},
+	}}
+	to := &EndpointsDump{KuboVersion: "0.2.0", Endpoints: []*Endpoint{
+		{Name: "/api/v0/new"},
+		{Name: "/api/v0/same", Response: `{"A": "<string>"}`},
+		{
+			Name:     "/api/v0/changed",
+			Status:   cmds.Deprecated,
+			Options:  []*Argument{{Name: "limit", Type: "int", Default: "20"}, {Name: "fresh"}},
+			Response: `{"Peers": [{"ID": "<string>", "Latency": "<int64>", "Addr": "<string>"}]}`,
+		},
+	}}
+
+	diff := DiffEndpoints(from, to)
+	want := `Changes from Kubo 0.1.0 to 0.2.0
+
+Added endpoints:
+  + /api/v0/new
+
+Removed endpoints:
+  - /api/v0/gone
+
+/api/v0/changed:
+  status: Active -> Deprecated
+  + option fresh
+  - option old
+  ~ default of limit: "10" -> "20"
+  + response field Peers[].Addr
+  ~ response field Peers[].Latency: "<string>" -> "<int64>"
+`
+	if got := diff.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDumpRoundTrip(t *testing.T) {
+	api := AllEndpoints()
+	buf := new(bytes.Buffer)
+	if err := WriteEndpoints(buf, api); err != nil {
+		t.Fatal(err)
+	}
+	dump, err := ReadEndpoints(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dump.KuboVersion != IPFSVersion() || len(dump.Endpoints) != len(api) {
+		t.Fatalf("dump does not match the endpoints")
+	}
+	if diff := DiffEndpoints(dump, dump).String(); !strings.HasSuffix(diff, "No changes.\n") {
+		t.Errorf("diff of a dump with itself should be empty, got:\n%s", diff)
+	}
+}
@@ -0,0 +1,62 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// DefaultDocsURL is the URL of the RPC API reference on docs.ipfs.tech,
+// generated with the MarkdownFormatter, whose sections are anchored with
+// endpointAnchor.
+const DefaultDocsURL = "https://docs.ipfs.tech/reference/kubo/rpc/"
+
+// DefaultExternalExampleSize is the size in bytes above which the examples
+// of the OpenAPI spec link to the docs instead of being embedded.
+const DefaultExternalExampleSize = 8192
+
+// docsURL returns the URL of the section of an endpoint in the RPC API
+// reference, at DocsURL.
+func (myself *OpenAPIFormatter) docsURL(name string) string {
+	return endpointDocsURL(myself.DocsURL, name)
+}
+
+// endpointDocsURL returns the URL of the section of an endpoint in the RPC
+// API reference at base, which defaults to DefaultDocsURL.
+func endpointDocsURL(base, name string) string {
+	if base == "" {
+		base = DefaultDocsURL
+	}
+	return base + "#" + endpointAnchor(name)
+}
+
+// externalExample returns an example referencing the section of an endpoint
+// in the RPC API reference with externalValue, if the given example is
+// larger than ExternalExampleSize, so that it doesn't bloat the spec.
+func (myself *OpenAPIFormatter) externalExample(name, summary string, value any) (openapi3.ExampleOrRef, bool) {
+	if myself.ExternalExampleSize <= 0 || exampleSize(value) <= myself.ExternalExampleSize {
+		return openapi3.ExampleOrRef{}, false
+	}
+	description := fmt.Sprintf("The example is too large to be embedded, see the reference of `%s`.", strings.TrimPrefix(name, APIPrefix))
+	url := myself.docsURL(name)
+	example := &openapi3.Example{Description: &description, ExternalValue: &url}
+	if summary != "" {
+		example.Summary = &summary
+	}
+	return openapi3.ExampleOrRef{Example: example}, true
+}
+
+// exampleSize returns the size of an example: the length of a text, or of
+// its JSON encoding.
+func exampleSize(value any) int {
+	if text, ok := value.(string); ok {
+		return len(text)
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
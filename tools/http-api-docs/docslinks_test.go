@@ -0,0 +1,52 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExternalExamples(t *testing.T) {
+	formatter := &OpenAPIFormatter{DocsURL: "https://staging.example.com/rpc/", ExternalExampleSize: 16}
+	if got := formatter.docsURL("/api/v0/pin/add"); got != "https://staging.example.com/rpc/#api-v0-pin-add" {
+		t.Errorf("unexpected docs URL %s", got)
+	}
+
+	small := &RecordedExample{Endpoint: "/api/v0/id", KuboVersion: "0.30.0", Response: map[string]any{"ID": "12D3"}}
+	if example := formatter.recordedExamples(small)["recorded"].Example; example.Value == nil || example.ExternalValue != nil {
+		t.Errorf("small examples should be embedded, got %+v", example)
+	}
+	large := &RecordedExample{Endpoint: "/api/v0/id", KuboVersion: "0.30.0", Response: strings.Repeat("x", 17)}
+	example := formatter.recordedExamples(large)["recorded"].Example
+	if example.Value != nil || example.ExternalValue == nil || *example.ExternalValue != "https://staging.example.com/rpc/#api-v0-id" {
+		t.Errorf("large examples should link to the docs, got %+v", example)
+	}
+
+	if example := new(OpenAPIFormatter).recordedExamples(large)["recorded"].Example; example.Value == nil {
+		t.Errorf("examples should be embedded without ExternalExampleSize")
+	}
+	if got := new(OpenAPIFormatter).docsURL("/api/v0/id"); got != DefaultDocsURL+"#api-v0-id" {
+		t.Errorf("unexpected default docs URL %s", got)
+	}
+}
+
+func TestSpecExternalDocs(t *testing.T) {
+	formatter := &OpenAPIFormatter{DocsURL: "https://staging.example.com/rpc/", KuboVersion: "0.30.0"}
+	formatter.GenerateMetadata()
+	if got := formatter.spec.ExternalDocs.URL; got != "https://staging.example.com/rpc/" {
+		t.Errorf("releases should keep -docs-url as externalDocs, got %s", got)
+	}
+	if got := formatter.spec.Info.MapOfAnything["x-release-notes"]; got != "https://github.com/ipfs/kubo/releases/tag/v0.30.0" {
+		t.Errorf("unexpected release notes %v", got)
+	}
+
+	for version, want := range map[string]string{
+		"0.30.0-rc1":                               "https://github.com/ipfs/kubo/releases/tag/v0.30.0-rc1",
+		"0.30.1-0.20240902163020-3f1c5b4e2a9d":     "",
+		"0.31.0-rc1.0.20240902163020-3f1c5b4e2a9d": "",
+		"0.0.0-fixture":                            "",
+	} {
+		if got := releaseNotesURL(version); got != want {
+			t.Errorf("release notes of %s: got %q, want %q", version, got, want)
+		}
+	}
+}
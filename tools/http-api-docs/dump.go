@@ -0,0 +1,78 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// EndpointsDump is a snapshot of the endpoints of a Kubo version. Dumps of
+// different versions can be compared with DiffEndpoints.
+//
+// Dumps are the intermediate representation of the endpoints for tools
+// which don't embed this module, described by the JSON Schema of IRSchema.
+type EndpointsDump struct {
+	// Schema is IRSchemaURL.
+	Schema string `json:"$schema,omitempty"`
+	// IRVersion is the version of the representation, IRVersion when
+	// written. It is 0 for dumps predating the versioning.
+	IRVersion   int `json:",omitempty"`
+	KuboVersion string
+	Endpoints   []*Endpoint
+}
+
+// WriteEndpoints writes a dump of the given endpoints as JSON.
+func WriteEndpoints(w io.Writer, api []*Endpoint) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(EndpointsDump{Schema: IRSchemaURL, IRVersion: IRVersion, KuboVersion: IPFSVersion(), Endpoints: api})
+}
+
+// ReadEndpoints reads a dump written by WriteEndpoints. Dumps of a newer
+// version of the representation are rejected.
+func ReadEndpoints(r io.Reader) (*EndpointsDump, error) {
+	var dump EndpointsDump
+	if err := json.NewDecoder(r).Decode(&dump); err != nil {
+		return nil, err
+	}
+	if dump.IRVersion > IRVersion {
+		return nil, fmt.Errorf("the dump is of version %d of the representation, only %d and older are supported", dump.IRVersion, IRVersion)
+	}
+	return &dump, nil
+}
+
+// SaveSnapshot writes the endpoints into a snapshot file: a dump, which
+// LoadSnapshot reads back so that later runs skip walking the command tree,
+// e.g. when only the formatters or an overlay changed.
+func SaveSnapshot(path string, api []*Endpoint) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteEndpoints(f, api); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadSnapshot reads a snapshot written by SaveSnapshot, or a dump of
+// http-api-diff. Snapshots of another Kubo version than the one of the
+// commands are loaded with a warning, as they are likely stale.
+func LoadSnapshot(path string) (*EndpointsDump, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dump, err := ReadEndpoints(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if dump.KuboVersion != IPFSVersion() {
+		log.Printf("WARN: %s is a snapshot of Kubo %s, the commands are the ones of %s\n", path, dump.KuboVersion, IPFSVersion())
+	}
+	return dump, nil
+}
@@ -0,0 +1,41 @@
+package docs
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	api := AllEndpoints()
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	if err := SaveSnapshot(path, api); err != nil {
+		t.Fatal(err)
+	}
+	dump, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dump.KuboVersion != IPFSVersion() || len(dump.Endpoints) != len(api) {
+		t.Fatalf("expected the %d endpoints of Kubo %s, got %d of %s", len(api), IPFSVersion(), len(dump.Endpoints), dump.KuboVersion)
+	}
+
+	// Formatters generate the same outputs from a snapshot.
+	for _, f := range []Formatter{new(MarkdownFormatter), new(OpenAPIFormatter), new(TypeScriptFormatter)} {
+		want, err := f.Generate(context.Background(), api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := f.Generate(context.Background(), dump.Endpoints)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%T generated a different output from the snapshot", f)
+		}
+	}
+
+	if _, err := LoadSnapshot(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("expected an error for a missing snapshot")
+	}
+}
@@ -0,0 +1,47 @@
+package docs
+
+import (
+	"sort"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+// encodingMIMETypes maps the values of the encoding parameter to the
+// Content-Type of the responses, as set by the go-ipfs-cmds HTTP handler.
+// Encodings without a MIME type are served as text/plain.
+var encodingMIMETypes = map[string]string{
+	cmds.JSON:     "application/json",
+	cmds.XML:      "application/xml",
+	cmds.Protobuf: "application/protobuf",
+	cmds.Text:     "text/plain",
+}
+
+// responseEncodings returns the (sorted) encodings in which the response of
+// a command can be requested. Values are encoded with the encoders of the
+// command, falling back to the generic JSON and XML ones. Commands returning
+// text (nil Type) write it as is, whatever the encoding.
+func responseEncodings(cmd *cmds.Command) []string {
+	if cmd.Type == nil {
+		return nil
+	}
+	encodings := []string{cmds.JSON, cmds.XML}
+	for enc := range cmd.Encoders {
+		switch enc {
+		case cmds.JSON, cmds.XML, cmds.CLI:
+			// Already listed, or only used by the command line.
+		default:
+			encodings = append(encodings, string(enc))
+		}
+	}
+	sort.Strings(encodings)
+	return encodings
+}
+
+// encodingMIMEType returns the Content-Type of responses in the given
+// encoding.
+func encodingMIMEType(encoding string) string {
+	if mime, ok := encodingMIMETypes[encoding]; ok {
+		return mime
+	}
+	return "text/plain"
+}
@@ -0,0 +1,70 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+func TestResponseEncodings(t *testing.T) {
+	cmd := &cmds.Command{
+		Type: fixtureOutput{},
+		Encoders: cmds.EncoderMap{
+			cmds.Text: cmds.Encoders[cmds.Text],
+			cmds.CLI:  cmds.Encoders[cmds.Text],
+		},
+	}
+	if got := strings.Join(responseEncodings(cmd), " "); got != "json text xml" {
+		t.Errorf("unexpected encodings %q", got)
+	}
+	if got := responseEncodings(&cmds.Command{}); got != nil {
+		t.Errorf("text responses have no encodings, got %v", got)
+	}
+}
+
+func TestAddEncodingMediaTypes(t *testing.T) {
+	resp := &openapi3.Response{
+		Description: "Successful response",
+		Content:     map[string]openapi3.MediaType{mimeNDJSON: {}},
+	}
+	addEncodingMediaTypes(resp, &Endpoint{Encodings: []string{"json", "protobuf", "textnl", "xml"}})
+
+	for _, mime := range []string{"application/protobuf", "text/plain", "application/xml"} {
+		if _, ok := resp.Content[mime]; !ok {
+			t.Errorf("missing media type %s", mime)
+		}
+	}
+	if _, ok := resp.Content["application/json"]; ok {
+		t.Errorf("json is the default and should not be added")
+	}
+	if !strings.Contains(resp.Description, "(`protobuf`, `textnl`, `xml`)") {
+		t.Errorf("unexpected description %q", resp.Description)
+	}
+}
+
+func TestAddCodecMediaTypes(t *testing.T) {
+	resp := &openapi3.Response{Content: map[string]openapi3.MediaType{"text/plain": {}}}
+	addCodecMediaTypes(resp, &Endpoint{Name: "/api/v0/dag/get"})
+	if _, ok := resp.Content["application/cbor"]; !ok {
+		t.Errorf("dag/get should document dag-cbor output")
+	}
+	if !strings.Contains(resp.Description, "`output-codec`") {
+		t.Errorf("unexpected description %q", resp.Description)
+	}
+
+	// The media types follow the enum of the option.
+	resp = &openapi3.Response{Content: map[string]openapi3.MediaType{"text/plain": {}}}
+	opt := &Argument{Name: "output-codec", Type: "string", Enum: []string{"dag-json", "raw"}}
+	addCodecMediaTypes(resp, &Endpoint{Name: "/api/v0/dag/get", Options: []*Argument{opt}})
+	if len(resp.Content) != 3 || resp.Content["application/vnd.ipld.raw"].Schema.Schema.Format == nil {
+		t.Errorf("unexpected media types %v", resp.Content)
+	}
+
+	resp = &openapi3.Response{Content: map[string]openapi3.MediaType{"text/plain": {}}}
+	addCodecMediaTypes(resp, &Endpoint{Name: "/api/v0/block/get"})
+	if _, ok := resp.Content["application/vnd.ipld.raw"]; !ok {
+		t.Errorf("block/get should document raw blocks")
+	}
+}
@@ -1,10 +1,22 @@
 // Package docs can be used to gather go-ipfs commands and automatically
 // generate documentation or tests.
+//
+// AllEndpoints (or Endpoints, for other command trees) extracts the Endpoint
+// and Argument descriptions, which are the input of all the generators:
+// GenerateDocs with a Formatter such as MarkdownFormatter, OpenAPIFormatter
+// and PostmanFormatter. Tools building their own outputs can import the
+// module and implement Formatter, or work on the endpoints directly.
 package docs
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"regexp"
+	"runtime/debug"
+	"slices"
 	"sort"
+	"strings"
 
 	jsondoc "github.com/Stebalien/go-json-doc"
 	cid "github.com/ipfs/go-cid"
@@ -49,8 +61,47 @@ type Endpoint struct {
 	Arguments   []*Argument
 	Options     []*Argument
 	Description string
-	Response    string
-	Group       string
+	// LongDescription is the text of the help after the tagline
+	// (Description), if any.
+	LongDescription string
+	Response        string
+	Group           string
+	// Streaming is set for endpoints which respond with a stream of
+	// newline-delimited JSON objects, each matching Response.
+	Streaming bool
+	// StreamingOption is the option which makes the endpoint respond with
+	// a stream of objects matching Response, for the endpoints which only
+	// stream when it is set. Streaming is not set for them, as their
+	// response is a single object by default.
+	StreamingOption string `json:",omitempty"`
+	// ResponseType is the package-qualified name of the Go type of the
+	// response, e.g. "pin.AddPinOutput", if it is a named type.
+	ResponseType string
+	// ResponseFields tells, by path (e.g. "Peers.Streams"), whether the
+	// fields of the JSON response can be null or left out.
+	ResponseFields map[string]FieldPresence `json:",omitempty"`
+	// Encodings lists the values of the encoding parameter in which the
+	// response can be requested, e.g. "json" or "xml". Empty for endpoints
+	// returning text.
+	Encodings []string
+	// AsyncEffects describes what happens in the background after a call,
+	// and how to observe it.
+	AsyncEffects []AsyncEffect
+	// LongPoll is set for endpoints which hold the connection open for an
+	// unbounded time.
+	LongPoll *LongPoll `json:",omitempty"`
+	// ParentHelp is the help of the parent commands, from the outermost
+	// one, which often documents caveats of all the subcommands (e.g. the
+	// flushing of "files").
+	ParentHelp []ParentHelp `json:",omitempty"`
+}
+
+// ParentHelp is the help of a parent command of an endpoint.
+type ParentHelp struct {
+	// Command is the name the endpoint of the command would have, e.g.
+	// "/api/v0/files".
+	Command string
+	Text    string
 }
 
 // Argument defines an IPFS RPC API endpoint argument.
@@ -61,6 +112,68 @@ type Argument struct {
 	Type        string
 	Required    bool
 	Default     string
+	Group       string
+	// Variadic is set for arguments which can be given several times.
+	Variadic bool
+	// Enum lists the accepted values, for arguments which only accept
+	// some.
+	Enum []string
+	// Kind is the cmds option type of options (cmds.Bool... cmds.Strings),
+	// which Type names. It is not set for positional arguments and
+	// arguments built by hand (see argumentKind).
+	Kind reflect.Kind `json:",omitempty"`
+	// DefaultValue is the default value of options, with its Go type,
+	// while Default is formatted for display.
+	DefaultValue any `json:",omitempty"`
+	// Status tells whether the argument is experimental, deprecated or
+	// removed. cmds options have no status of their own, so NewOption
+	// reads it from the markers in their description (see optionStatus).
+	Status cmds.Status `json:",omitempty"`
+	// Aliases are the other names of options, e.g. "r" for "recursive",
+	// which the RPC API accepts as well.
+	Aliases []string `json:",omitempty"`
+}
+
+// optionStatusMarkers are the markers of the descriptions of options which
+// are not active, e.g. "Hash function to use. (experimental)".
+var optionStatusMarkers = []struct {
+	marker *regexp.Regexp
+	status cmds.Status
+}{
+	{regexp.MustCompile(`^Removed, `), cmds.Removed},
+	{regexp.MustCompile(`(?i)[(\[]deprecated\b|\bDEPRECATED:`), cmds.Deprecated},
+	{regexp.MustCompile(`\(experimental\)`), cmds.Experimental},
+}
+
+// optionStatus returns the status of an option from its description.
+func optionStatus(description string) cmds.Status {
+	for _, m := range optionStatusMarkers {
+		if m.marker.MatchString(description) {
+			return m.status
+		}
+	}
+	return cmds.Active
+}
+
+// typeKinds are the cmds option types named by Argument.Type.
+var typeKinds = map[string]reflect.Kind{
+	"bool":    cmds.Bool,
+	"int":     cmds.Int,
+	"uint":    cmds.Uint,
+	"int64":   cmds.Int64,
+	"uint64":  cmds.Uint64,
+	"float64": cmds.Float,
+	"string":  cmds.String,
+	"array":   cmds.Strings,
+}
+
+// argumentKind returns the cmds option type of an argument, from its Type
+// when Kind is not set. It is cmds.Invalid for files and unknown types.
+func argumentKind(arg *Argument) reflect.Kind {
+	if arg.Kind != cmds.Invalid {
+		return arg.Kind
+	}
+	return typeKinds[arg.Type]
 }
 
 type sorter []*Endpoint
@@ -86,15 +199,86 @@ func InStatus(endpoints []*Endpoint, status cmds.Status) []*Endpoint {
 	return results
 }
 
+// AllStatuses lists every command status, in the order they are documented.
+var AllStatuses = []cmds.Status{cmds.Active, cmds.Experimental, cmds.Deprecated, cmds.Removed}
+
+// ParseStatuses parses a comma-separated list of statuses, like
+// "active,experimental". Statuses listed twice are only returned once.
+func ParseStatuses(list string) ([]cmds.Status, error) {
+	var statuses []cmds.Status
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, status := range AllStatuses {
+			if strings.EqualFold(name, statusName(status)) {
+				if !slices.Contains(statuses, status) {
+					statuses = append(statuses, status)
+				}
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown status %q, expected one of active, experimental, deprecated, removed", name)
+		}
+	}
+	return statuses, nil
+}
+
+// WithStatus returns the endpoints having any of the given statuses.
+func WithStatus(endpoints []*Endpoint, statuses []cmds.Status) []*Endpoint {
+	var results []*Endpoint
+	for _, endpoint := range endpoints {
+		for _, status := range statuses {
+			if endpoint.Status == status {
+				results = append(results, endpoint)
+			}
+		}
+	}
+	return results
+}
+
 func IPFSVersion() string {
 	return config.CurrentVersionNumber
 }
 
+// kuboModule is the path of the Kubo module the commands come from.
+const kuboModule = "github.com/ipfs/kubo"
+
+// KuboVersion returns the version of the Kubo module the commands come
+// from, as recorded in the build info, e.g. "0.30.0". Unlike IPFSVersion,
+// it tells releases and pseudo-versions of a commit apart. It falls back to
+// IPFSVersion without build info or when Kubo is replaced by a local copy.
+func KuboVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return IPFSVersion()
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != kuboModule {
+			continue
+		}
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		if v := strings.TrimPrefix(dep.Version, "v"); v != "" && v != "(devel)" {
+			return v
+		}
+	}
+	return IPFSVersion()
+}
+
 // Endpoints receives a name and a go-ipfs command and returns the endpoints it
 // defines] (sorted). It does this by recursively gathering endpoints defined by
 // subcommands. Thus, calling it with the core command Root generates all
 // the endpoints.
-func Endpoints(name string, cmd *cmds.Command) (endpoints []*Endpoint) {
+func Endpoints(name string, cmd *cmds.Command) []*Endpoint {
+	return commandEndpoints(name, cmd, nil, false)
+}
+
+// commandEndpoints returns the endpoints of a command and its subcommands.
+// The help of the command is passed down to the subcommands, after the help
+// of its parents, if inherit is set: it is not for the root command.
+func commandEndpoints(name string, cmd *cmds.Command, parents []ParentHelp, inherit bool) (endpoints []*Endpoint) {
 	var arguments []*Argument
 	var options []*Argument
 
@@ -110,6 +294,7 @@ func Endpoints(name string, cmd *cmds.Command) (endpoints []*Endpoint) {
 				Name:        arg.Name,
 				Type:        argType,
 				Required:    arg.Required,
+				Variadic:    arg.Variadic,
 				Description: arg.Description,
 			})
 		}
@@ -122,19 +307,20 @@ func Endpoints(name string, cmd *cmds.Command) (endpoints []*Endpoint) {
 				}
 			}
 
-			def := fmt.Sprint(opt.Default())
-			if def == "<nil>" {
-				def = ""
-			}
-			options = append(options, &Argument{
-				Name:        opt.Names()[0],
-				Type:        opt.Type().String(),
-				Description: opt.Description(),
-				Default:     def,
-			})
+			options = append(options, optionArgument(name, opt))
+		}
+
+		options = groupOptions(name, options)
+		for _, arg := range append(append([]*Argument{}, arguments...), options...) {
+			arg.Enum = enumValues(name, arg)
 		}
 
 		res := buildResponse(cmd.Type)
+		var streaming bool
+		var streamingOption string
+		if s := streamingEndpoints[name]; s != nil {
+			streaming, streamingOption = s.Option == "", s.Option
+		}
 
 		endpoints = []*Endpoint{
 			{
@@ -144,26 +330,93 @@ func Endpoints(name string, cmd *cmds.Command) (endpoints []*Endpoint) {
 				Arguments:   arguments,
 				Options:     options,
 				Response:    res,
+				Streaming:   streaming,
+
+				LongDescription: strings.TrimSpace(cmd.Helptext.ShortDescription),
+				AsyncEffects:    asyncEffectsPerEndpoint[name],
+				LongPoll:        longPollEndpoints[name],
+				StreamingOption: streamingOption,
+
+				ResponseType:   responseType(cmd.Type),
+				ResponseFields: fieldPresence(name, cmd.Type),
+				Encodings:      responseEncodings(cmd),
+				ParentHelp:     parents,
 			},
 		}
 	}
 
-	for n, cmd := range cmd.Subcommands {
+	if help := commandHelp(cmd); inherit && help != "" {
+		parents = append(parents[:len(parents):len(parents)], ParentHelp{Command: name, Text: help})
+	}
+	for n, sub := range cmd.Subcommands {
 		endpoints = append(endpoints,
-			Endpoints(fmt.Sprintf("%s/%s", name, n), cmd)...)
+			commandEndpoints(fmt.Sprintf("%s/%s", name, n), sub, parents, true)...)
 	}
 	sort.Sort(sorter(endpoints))
 	return endpoints
 }
 
+// commandHelp returns the description of a command in its help.
+func commandHelp(cmd *cmds.Command) string {
+	if help := strings.TrimSpace(cmd.Helptext.LongDescription); help != "" {
+		return help
+	}
+	return strings.TrimSpace(cmd.Helptext.ShortDescription)
+}
+
+// cliCommand returns the CLI command matching an endpoint, like
+// "ipfs pin add" for "/api/v0/pin/add", or "" for endpoints which are not
+// under APIPrefix.
+func cliCommand(name string) string {
+	path, ok := strings.CutPrefix(name, APIPrefix+"/")
+	if !ok {
+		return ""
+	}
+	return "ipfs " + strings.ReplaceAll(path, "/", " ")
+}
+
+// optionArgument returns the Argument of an option of the command of an
+// endpoint.
+func optionArgument(endpoint string, opt cmds.Option) *Argument {
+	var aliases []string
+	if names := opt.Names(); len(names) > 1 {
+		aliases = names[1:]
+	}
+	def := fmt.Sprint(opt.Default())
+	if def == "<nil>" {
+		def = ""
+	}
+	arg := NewOption(opt.Names()[0], opt.Type().String(), opt.Description(), def)
+	arg.Endpoint = endpoint
+	arg.Kind = opt.Type()
+	arg.DefaultValue = opt.Default()
+	arg.Aliases = aliases
+	return arg
+}
+
+// textResponse is the Response of endpoints returning text.
+const textResponse = "This endpoint returns a `text/plain` response body."
+
 func buildResponse(res interface{}) string {
 	// Commands with a nil type return text. This is a bad thing.
 	if res == nil {
-		return "This endpoint returns a `text/plain` response body."
+		return textResponse
 	}
 	desc, err := JsondocGlossary.Describe(res)
 	if err != nil {
 		panic(err)
 	}
-	return desc
+	// JsondocGlossary nests the embedded structs, which the daemon doesn't.
+	var doc any
+	if json.Unmarshal([]byte(desc), &doc) != nil || !promoteEmbedded(reflect.TypeOf(res), doc) {
+		return desc
+	}
+	var buf strings.Builder
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		panic(err)
+	}
+	return buf.String()
 }
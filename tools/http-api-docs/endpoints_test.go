@@ -1,7 +1,64 @@
 package docs
 
-import "testing"
+import (
+	"testing"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
 
 func TestEndpoints(t *testing.T) {
 	AllEndpoints()
 }
+
+func TestKuboVersion(t *testing.T) {
+	// The tests are built with the Kubo release required by go.mod.
+	if v := KuboVersion(); v != IPFSVersion() {
+		t.Errorf("KuboVersion() = %q, want %q", v, IPFSVersion())
+	}
+}
+
+func TestParseStatuses(t *testing.T) {
+	statuses, err := ParseStatuses("active, Removed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[0] != cmds.Active || statuses[1] != cmds.Removed {
+		t.Errorf("unexpected statuses %v", statuses)
+	}
+	if statuses, err := ParseStatuses("active,active"); err != nil || len(statuses) != 1 {
+		t.Errorf("duplicate statuses should be returned once, got %v, %v", statuses, err)
+	}
+	if _, err := ParseStatuses("active,stable"); err == nil {
+		t.Errorf("expected an error for an unknown status")
+	}
+
+	api := []*Endpoint{{Name: "a", Status: cmds.Active}, {Name: "r", Status: cmds.Removed}, {Name: "e", Status: cmds.Experimental}}
+	if got := WithStatus(api, []cmds.Status{cmds.Experimental}); len(got) != 1 || got[0].Name != "e" {
+		t.Errorf("unexpected endpoints %v", got)
+	}
+}
+
+func TestCLICommand(t *testing.T) {
+	if got := cliCommand("/api/v0/pin/remote/add"); got != "ipfs pin remote add" {
+		t.Errorf("got %q", got)
+	}
+	if got := cliCommand("/api/v1/hello"); got != "" {
+		t.Errorf("endpoints outside of %s have no CLI command, got %q", APIPrefix, got)
+	}
+}
+
+func TestOptionStatus(t *testing.T) {
+	for description, want := range map[string]cmds.Status{
+		"Inline small blocks into CIDs. (experimental)":                    cmds.Experimental,
+		"Use legacy format for returned CID (DEPRECATED)":                  cmds.Deprecated,
+		"Add default bootstrap nodes. (Deprecated, use 'default' instead)": cmds.Deprecated,
+		"[DEPRECATED] Path to the configuration file to use.":              cmds.Deprecated,
+		"Run the command locally. DEPRECATED: use --offline.":              cmds.Deprecated,
+		"Removed, use 'ipfs routing' instead.":                             cmds.Removed,
+		"Enable experimental streaming of directory entries.":              cmds.Active,
+	} {
+		if got := optionStatus(description); got != want {
+			t.Errorf("optionStatus(%q) = %v, want %v", description, got, want)
+		}
+	}
+}
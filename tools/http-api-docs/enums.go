@@ -0,0 +1,56 @@
+package docs
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Patterns listing the values accepted by an argument in its description,
+// e.g. `Can be "direct", "indirect", or "all".`, `type of the key: rsa,
+// ed25519.` or `The format, pem or der.`
+var (
+	quotedEnum = regexp.MustCompile(`(?:[Cc]an be|[Oo]ne of|[Ee]ither):?\s+("[^"]+"(?:(?:,\s*or\s+|,\s*|\s+or\s+)"[^"]+")*)`)
+	quotedItem = regexp.MustCompile(`"([^"]+)"`)
+	colonEnum  = regexp.MustCompile(`:\s*([a-z0-9-]+(?:,\s*[a-z0-9-]+)+)\.`)
+	orEnum     = regexp.MustCompile(`,\s*([a-z0-9-]+(?:,\s*[a-z0-9-]+)*,?\s+or\s+[a-z0-9-]+)\.`)
+	enumSep    = regexp.MustCompile(`,\s*or\s+|,\s*|\s+or\s+`)
+)
+
+// enumValues returns the values accepted by an argument or option of an
+// endpoint, from optionEnums or else parsed from its description. It returns
+// nil for arguments accepting any value.
+func enumValues(endpoint string, arg *Argument) []string {
+	if values, ok := optionEnums[endpoint][arg.Name]; ok {
+		return values
+	}
+	if codec, ok := codecOptions[endpoint]; ok && codec.Option == arg.Name {
+		return codec.names()
+	}
+	if arg.Type != "string" {
+		return nil
+	}
+
+	var values []string
+	if m := quotedEnum.FindStringSubmatch(arg.Description); m != nil {
+		for _, item := range quotedItem.FindAllStringSubmatch(m[1], -1) {
+			values = append(values, item[1])
+		}
+	} else if m := colonEnum.FindStringSubmatch(arg.Description); m != nil {
+		values = enumSep.Split(m[1], -1)
+	} else if m := orEnum.FindStringSubmatch(arg.Description); m != nil {
+		values = enumSep.Split(m[1], -1)
+	}
+
+	// A default which is not listed means the description was not
+	// listing values after all.
+	if arg.Default != "" && len(values) > 0 {
+		found := false
+		for _, v := range values {
+			found = found || strings.TrimSpace(v) == arg.Default
+		}
+		if !found {
+			return nil
+		}
+	}
+	return values
+}
@@ -0,0 +1,44 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnumValues(t *testing.T) {
+	for _, c := range []struct {
+		description string
+		def         string
+		want        string
+	}{
+		{`The type of pinned keys to list. Can be "direct", "indirect", "recursive", or "all". Default: all.`, "all", "direct|indirect|recursive|all"},
+		{`type of the key to create: rsa, ed25519. Default: ed25519.`, "ed25519", "rsa|ed25519"},
+		{`The format of the private key to import, libp2p-protobuf-cleartext or pem-pkcs8-cleartext.`, "", "libp2p-protobuf-cleartext|pem-pkcs8-cleartext"},
+		{"The log level.\n\t\t\tOne of: debug, info, warn, error, dpanic, panic, fatal.", "", "debug|info|warn|error|dpanic|panic|fatal"},
+		{`Mode to apply to node (numeric notation)`, "", ""},
+		// The default is not listed, so these are not the accepted values.
+		{`Ignore these: foo, bar.`, "baz", ""},
+	} {
+		arg := &Argument{Name: "opt", Type: "string", Description: c.description, Default: c.def}
+		if got := strings.Join(enumValues("/api/v0/test", arg), "|"); got != c.want {
+			t.Errorf("%q: got %q, want %q", c.description, got, c.want)
+		}
+	}
+
+	cidVersion := &Argument{Name: "cid-version", Type: "int"}
+	if got := enumValues("/api/v0/add", cidVersion); len(got) != 2 {
+		t.Errorf("cid-version of add should have 2 values, got %v", got)
+	}
+	outputCodec := &Argument{Name: "output-codec", Type: "string", Default: "dag-json"}
+	if got := strings.Join(enumValues("/api/v0/dag/get", outputCodec), "|"); got != "cbor|dag-cbor|dag-json|dag-pb|json|raw" {
+		t.Errorf("output-codec of dag/get should list the codecs, got %v", got)
+	}
+}
+
+func TestEnumParameter(t *testing.T) {
+	arg := &Argument{Name: "cid-version", Type: "int", Enum: []string{"0", "1"}}
+	p := genParameterForArgument(nil, arg, false)
+	if enum := p.Schema.Schema.Enum; len(enum) != 2 || enum[1] != int64(1) {
+		t.Errorf("unexpected enum %v", enum)
+	}
+}
@@ -0,0 +1,64 @@
+package docs_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	docs "github.com/ipfs/ipfs-docs/tools/http-api-docs"
+)
+
+// Endpoints of any go-ipfs-cmds command tree can be extracted and formatted.
+func ExampleEndpoints() {
+	root := &cmds.Command{
+		Subcommands: map[string]*cmds.Command{
+			"echo": {
+				Helptext:  cmds.HelpText{Tagline: "Echo the argument."},
+				Arguments: []cmds.Argument{cmds.StringArg("text", true, false, "Text to echo.")},
+				Run:       func(*cmds.Request, cmds.ResponseEmitter, cmds.Environment) error { return nil },
+			},
+		},
+	}
+
+	for _, endp := range docs.Endpoints("/api/v0", root) {
+		var args []string
+		for _, arg := range endp.Arguments {
+			args = append(args, arg.Name+" ["+arg.Type+"]")
+		}
+		fmt.Printf("%s: %s (%s)\n", endp.Name, endp.Description, strings.Join(args, ", "))
+	}
+	// Output: /api/v0/echo: Echo the argument. (text [string])
+}
+
+// endpointList is a formatter listing the endpoints, one per line.
+type endpointList struct{}
+
+func (endpointList) Generate(ctx context.Context, api []*docs.Endpoint) (string, error) {
+	var lines []string
+	for _, endp := range api {
+		lines = append(lines, endp.Name+": "+endp.Description)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Formatters outside of this package implement Formatter to run on the
+// endpoints like the built-in ones.
+func ExampleFormatter() {
+	root := &cmds.Command{
+		Subcommands: map[string]*cmds.Command{
+			"echo": {
+				Helptext: cmds.HelpText{Tagline: "Echo the argument."},
+				Run:      func(*cmds.Request, cmds.ResponseEmitter, cmds.Environment) error { return nil },
+			},
+		},
+	}
+
+	var formatter docs.Formatter = endpointList{}
+	out, err := formatter.Generate(context.Background(), docs.Endpoints("/api/v0", root))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(out)
+	// Output: /api/v0/echo: Echo the argument.
+}
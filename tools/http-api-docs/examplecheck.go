@@ -0,0 +1,89 @@
+package docs
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// exampleValue replaces the placeholders of a documented response (e.g.
+// "<int64>") with values matching the schema generated for it: the example
+// of the schema if it has one (e.g. for times, see timeSchema), or the one
+// of the placeholder type. Placeholder keys of maps lose their brackets, as
+// in mockValue.
+func exampleValue(types TypeMap, x any, schema *openapi3.SchemaOrRef) any {
+	var s *openapi3.Schema
+	if schema != nil {
+		s = schema.Schema
+	}
+	switch v := x.(type) {
+	case string:
+		if s != nil && s.Example != nil {
+			return *s.Example
+		}
+		if t, ok := types.lookup(v); ok && t.Example != nil {
+			return t.Example
+		}
+		return v
+	case []any:
+		var items *openapi3.SchemaOrRef
+		if s != nil {
+			items = s.Items
+		}
+		values := make([]any, len(v))
+		for i, item := range v {
+			values[i] = exampleValue(types, item, items)
+		}
+		return values
+	case map[string]any:
+		obj := make(map[string]any, len(v))
+		for k, item := range v {
+			var field *openapi3.SchemaOrRef
+			if s != nil {
+				if prop, ok := s.Properties[k]; ok {
+					field = &prop
+				} else if s.AdditionalProperties != nil {
+					field = s.AdditionalProperties.SchemaOrRef
+				}
+			}
+			if strings.HasPrefix(k, "<") && strings.HasSuffix(k, ">") {
+				k = strings.Trim(k, "<>")
+			}
+			obj[k] = exampleValue(types, item, field)
+		}
+		return obj
+	default:
+		return v
+	}
+}
+
+// checkExample warns about the parts of an example contradicting the schema
+// of the response, e.g. a string where the schema has an integer. Fields
+// missing from the example are fine, as responses omit empty ones.
+func checkExample(w *warnings, name string, schema *openapi3.SchemaOrRef, example any) {
+	if schema == nil || schema.Schema == nil {
+		return
+	}
+	// validateValue expects decoded JSON, e.g. float64 for the numbers.
+	b, err := json.Marshal(example)
+	if err != nil {
+		w.warnf("Couldn't encode the %s example: %s", name, err)
+		return
+	}
+	var decoded any
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		w.warnf("Couldn't decode the %s example: %s", name, err)
+		return
+	}
+	for _, m := range dedupMismatches(validateValue("", schema.Schema, decoded)) {
+		if m.Message == missingFieldMessage {
+			continue
+		}
+		if m.Path == "" {
+			w.warnf("The %s example contradicts the schema: %s", name, m.Message)
+		} else {
+			w.warnf("The %s example contradicts the schema at %s: %s", name, m.Path, m.Message)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package docs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExampleValue(t *testing.T) {
+	doc := map[string]any{
+		"Size":  "<int64>",
+		"Seen":  "<timestamp>",
+		"Peers": map[string]any{"<string>": "<peer-id>"},
+		"Name":  "<unknown>",
+	}
+	schema := new(OpenAPIFormatter).applyTimeFormats("/api/v0/test", "", doc, genSchemaOrRefForResponse(nil, nil, doc, true))
+	want := map[string]any{
+		"Size":  1,
+		"Seen":  "2024-01-02T15:04:05Z",
+		"Peers": map[string]any{"string": placeholderTypes["<peer-id>"].Example},
+		"Name":  "<unknown>",
+	}
+	if got := exampleValue(nil, doc, schema); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, expected %v", got, want)
+	}
+
+	w := &warnings{endpoint: "/api/v0/test"}
+	checkExample(w, "documented", schema, exampleValue(nil, doc, schema))
+	if len(w.list) != 0 {
+		t.Errorf("unexpected warnings %v", w.list)
+	}
+
+	// Missing fields are fine, other differences are not.
+	checkExample(w, "recorded", schema, map[string]any{"Size": "big", "Extra": true})
+	if len(w.list) != 2 {
+		t.Errorf("expected warnings for Size and Extra, got %v", w.list)
+	}
+}
@@ -0,0 +1,443 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	crypto "github.com/libp2p/go-libp2p/core/crypto"
+	peer "github.com/libp2p/go-libp2p/core/peer"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// RecordedExample is the response of a daemon to an example call, recorded
+// by RecordExamples. The OpenAPI formatter embeds it instead of the
+// documented pseudo-JSON, with its <string> placeholders.
+type RecordedExample struct {
+	Endpoint    string
+	KuboVersion string
+	Arguments   []string          `json:",omitempty"`
+	Options     map[string]string `json:",omitempty"`
+	// Response is the decoded JSON response (its first value for streaming
+	// endpoints), or the text of text/plain responses.
+	Response any
+}
+
+// ExampleCall is a call made by RecordExamples. Arguments equal to
+// ExampleFixtureArg are replaced by the CID of the fixture.
+type ExampleCall struct {
+	Endpoint  string
+	Arguments []string
+	Options   map[string]string
+}
+
+// ExampleFixture is the file added to the daemon before recording, so that
+// the examples of the endpoints taking a CID are reproducible.
+const ExampleFixture = "Hello from the IPFS RPC API docs!\n"
+
+// ExampleFixtureArg stands for the CID of ExampleFixture in the arguments of
+// ExampleCall.
+const ExampleFixtureArg = "<fixture>"
+
+// Values replacing the identity of the recording daemon in the examples.
+const (
+	examplePeerID   = "12D3KooWD3eckifWpRn9wQpMG9R9hX3sD158z7EqHWmweQAJU5SA"
+	exampleRepoPath = "/home/ipfs/.ipfs"
+	// exampleMaxItems is the number of items of arrays kept, e.g. of the
+	// peers of swarm/peers.
+	exampleMaxItems = 3
+	// exampleMaxText is the length of text responses kept.
+	exampleMaxText = 4096
+)
+
+// RecordExamples adds ExampleFixture to the RPC API at baseURL (e.g.
+// "http://127.0.0.1:5001"), makes the example calls of the given endpoints
+// (see exampleCalls) and returns their sanitized responses: the peer ID,
+// public key, IP addresses and repo path of the daemon are replaced by
+// documentation values, and long arrays are truncated. The metric families
+// of the daemon are recorded too, as the example of /debug/metrics/prometheus
+// (see RecordedMetricFamilies). The daemon should be a throwaway one, as the
+// fixture stays pinned. Failed calls are skipped with a warning;
+// RecordExamples returns an error only when the daemon can't be reached or
+// the context is done.
+func RecordExamples(ctx context.Context, client *http.Client, baseURL string, api []*Endpoint) ([]*RecordedExample, error) {
+	r := &recorder{ctx: ctx, client: client, baseURL: strings.TrimSuffix(baseURL, "/")}
+	byName := make(map[string]bool, len(api))
+	for _, endp := range api {
+		byName[endp.Name] = true
+	}
+
+	var version struct{ Version string }
+	if err := r.callJSON(ExampleCall{Endpoint: APIPrefix + "/version"}, &version); err != nil {
+		return nil, err
+	}
+	var id struct{ ID, PublicKey string }
+	if err := r.callJSON(ExampleCall{Endpoint: APIPrefix + "/id"}, &id); err != nil {
+		return nil, err
+	}
+	sanitizer, err := newExampleSanitizer(id.ID, id.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	add := ExampleCall{Endpoint: APIPrefix + "/add", Options: map[string]string{"pin": "true"}}
+	added, err := r.call(add, strings.NewReader(ExampleFixture))
+	if err != nil {
+		return nil, err
+	}
+	result, _ := added.(map[string]any)
+	fixture, _ := result["Hash"].(string)
+	if fixture == "" {
+		return nil, fmt.Errorf("no Hash in the response of add: %v", added)
+	}
+
+	var examples []*RecordedExample
+	record := func(call ExampleCall, response any) {
+		examples = append(examples, &RecordedExample{
+			Endpoint:    call.Endpoint,
+			KuboVersion: version.Version,
+			Arguments:   call.Arguments,
+			Options:     call.Options,
+			Response:    sanitizer.sanitize("", response),
+		})
+	}
+	if byName[add.Endpoint] {
+		record(add, added)
+	}
+	for _, call := range exampleCalls {
+		if !byName[call.Endpoint] {
+			continue
+		}
+		args := make([]string, len(call.Arguments))
+		for i, arg := range call.Arguments {
+			if arg == ExampleFixtureArg {
+				arg = fixture
+			}
+			args[i] = arg
+		}
+		call.Arguments = args
+		response, err := r.call(call, nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				return examples, ctx.Err()
+			}
+			if _, ok := err.(*exampleCallError); !ok {
+				return examples, err
+			}
+			log.Printf("WARN: Skipping the example of %s: %s\n", call.Endpoint, err)
+			continue
+		}
+		record(call, response)
+	}
+
+	// The metrics are scraped last, so that the vectors of the calls made
+	// have metrics.
+	families, err := r.metrics()
+	if err != nil {
+		if ctx.Err() != nil {
+			return examples, ctx.Err()
+		}
+		if _, ok := err.(*exampleCallError); !ok {
+			return examples, err
+		}
+		log.Printf("WARN: Skipping the metric families: %s\n", err)
+		return examples, nil
+	}
+	examples = append(examples, &RecordedExample{Endpoint: metricsPath, KuboVersion: version.Version, Response: families})
+	return examples, nil
+}
+
+// RecordedMetricFamilies returns the metric families scraped by
+// RecordExamples, if any.
+func RecordedMetricFamilies(examples map[string]*RecordedExample) ([]MetricFamily, error) {
+	example, ok := examples[metricsPath]
+	if !ok {
+		return nil, nil
+	}
+	b, err := json.Marshal(example.Response)
+	if err != nil {
+		return nil, err
+	}
+	var families []MetricFamily
+	if err := json.Unmarshal(b, &families); err != nil {
+		return nil, fmt.Errorf("the recorded metric families: %w", err)
+	}
+	return families, nil
+}
+
+// recorder makes the example calls.
+type recorder struct {
+	ctx     context.Context
+	client  *http.Client
+	baseURL string
+}
+
+// exampleCallError is the error for a call answered with an error.
+type exampleCallError struct {
+	status string
+	body   string
+}
+
+func (e *exampleCallError) Error() string {
+	return fmt.Sprintf("unexpected status %s: %s", e.status, e.body)
+}
+
+func (r *recorder) callJSON(call ExampleCall, v any) error {
+	response, err := r.call(call, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", call.Endpoint, err)
+	}
+	b, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// call makes a call, with file as the body if not nil, and returns the
+// first JSON value of the response, or its text.
+func (r *recorder) call(call ExampleCall, file io.Reader) (any, error) {
+	query := url.Values{}
+	for _, arg := range call.Arguments {
+		query.Add("arg", arg)
+	}
+	for name, value := range call.Options {
+		query.Set(name, value)
+	}
+	body := new(bytes.Buffer)
+	contentType := ""
+	if file != nil {
+		mw := multipart.NewWriter(body)
+		part, err := mw.CreateFormFile("file", "fixture.txt")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(part, file); err != nil {
+			return nil, err
+		}
+		if err := mw.Close(); err != nil {
+			return nil, err
+		}
+		contentType = mw.FormDataContentType()
+	}
+
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodPost, r.baseURL+call.Endpoint+"?"+query.Encode(), body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, exampleMaxText))
+		return nil, &exampleCallError{status: resp.Status, body: strings.TrimSpace(string(b))}
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == "application/json" {
+		var value any
+		if err := json.NewDecoder(resp.Body).Decode(&value); err != nil {
+			return nil, &exampleCallError{status: resp.Status, body: "invalid JSON: " + err.Error()}
+		}
+		return value, nil
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, exampleMaxText))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// metrics returns the metric families of the daemon.
+func (r *recorder) metrics() ([]MetricFamily, error) {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.baseURL+metricsPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, exampleMaxText))
+		return nil, &exampleCallError{status: resp.Status, body: strings.TrimSpace(string(b))}
+	}
+	families, err := ParseMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, &exampleCallError{status: resp.Status, body: "invalid metrics: " + err.Error()}
+	}
+	return families, nil
+}
+
+var (
+	exampleIPv4 = regexp.MustCompile(`/ip4/([0-9.]+)`)
+	exampleIPv6 = regexp.MustCompile(`/ip6/([0-9a-fA-F:]+)`)
+)
+
+// exampleSanitizer replaces the identity of the recording daemon in the
+// examples.
+type exampleSanitizer struct {
+	replacer *strings.Replacer
+	// ips maps the addresses of the daemon to documentation addresses
+	// (RFC 5737 and 3849), so that distinct addresses stay distinct.
+	ips map[string]string
+	// counts is the number of documentation addresses of each format.
+	counts map[string]int
+}
+
+func newExampleSanitizer(peerID, publicKey string) (*exampleSanitizer, error) {
+	id, err := peer.Decode(examplePeerID)
+	if err != nil {
+		return nil, err
+	}
+	key, err := id.ExtractPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	b, err := crypto.MarshalPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	pairs := []string{peerID, examplePeerID}
+	if publicKey != "" {
+		pairs = append(pairs, publicKey, base64.StdEncoding.EncodeToString(b))
+	}
+	return &exampleSanitizer{replacer: strings.NewReplacer(pairs...), ips: make(map[string]string), counts: make(map[string]int)}, nil
+}
+
+// sanitize returns a sanitized copy of the value of the given field.
+func (s *exampleSanitizer) sanitize(field string, value any) any {
+	switch v := value.(type) {
+	case string:
+		if field == "RepoPath" {
+			return exampleRepoPath
+		}
+		return s.sanitizeString(v)
+	case []any:
+		if len(v) > exampleMaxItems {
+			v = v[:exampleMaxItems]
+		}
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = s.sanitize(field, item)
+		}
+		return items
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		// Sorted, for the documentation addresses to be reproducible.
+		sort.Strings(keys)
+		fields := make(map[string]any, len(v))
+		for _, key := range keys {
+			fields[s.sanitizeString(key)] = s.sanitize(key, v[key])
+		}
+		return fields
+	default:
+		return value
+	}
+}
+
+func (s *exampleSanitizer) sanitizeString(v string) string {
+	v = s.replacer.Replace(v)
+	v = exampleIPv4.ReplaceAllStringFunc(v, func(m string) string {
+		return "/ip4/" + s.ip(strings.TrimPrefix(m, "/ip4/"), "127.0.0.1", "192.0.2.%d")
+	})
+	return exampleIPv6.ReplaceAllStringFunc(v, func(m string) string {
+		return "/ip6/" + s.ip(strings.TrimPrefix(m, "/ip6/"), "::1", "2001:db8::%d")
+	})
+}
+
+// ip returns the documentation address of an address, keeping loopback.
+func (s *exampleSanitizer) ip(addr, loopback, format string) string {
+	if addr == loopback {
+		return addr
+	}
+	if doc, ok := s.ips[addr]; ok {
+		return doc
+	}
+	s.counts[format]++
+	s.ips[addr] = fmt.Sprintf(format, s.counts[format])
+	return s.ips[addr]
+}
+
+// exampleFile returns the path of the file of the example of an endpoint.
+func exampleFile(dir, endpoint string) string {
+	return filepath.Join(dir, endpointAnchor(endpoint)+".json")
+}
+
+// WriteExamples writes each example into dir, as
+// <endpoint anchor>.json (e.g. api-v0-version.json).
+func WriteExamples(dir string, examples []*RecordedExample) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, example := range examples {
+		b, err := json.MarshalIndent(example, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(exampleFile(dir, example.Endpoint), append(b, '\n'), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadExamples reads the examples written by WriteExamples, by endpoint
+// name. A missing directory has no examples.
+func LoadExamples(dir string) (map[string]*RecordedExample, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	examples := make(map[string]*RecordedExample, len(paths))
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		example := new(RecordedExample)
+		if err := json.Unmarshal(b, example); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		examples[example.Endpoint] = example
+	}
+	return examples, nil
+}
+
+// recordedExamples returns the examples of a media type from a recorded
+// example, linking to the docs if it is too large (see externalExample).
+func (myself *OpenAPIFormatter) recordedExamples(example *RecordedExample) map[string]openapi3.ExampleOrRef {
+	summary := "Response of Kubo " + example.KuboVersion
+	if cmd := cliCommand(example.Endpoint); cmd != "" {
+		summary = fmt.Sprintf("Response of `%s` in Kubo %s", strings.Join(append([]string{cmd}, example.Arguments...), " "), example.KuboVersion)
+	}
+	if external, ok := myself.externalExample(example.Endpoint, summary, example.Response); ok {
+		return map[string]openapi3.ExampleOrRef{"recorded": external}
+	}
+	value := example.Response
+	return map[string]openapi3.ExampleOrRef{
+		"recorded": {Example: &openapi3.Example{Summary: &summary, Value: &value}},
+	}
+}
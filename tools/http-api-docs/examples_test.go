@@ -0,0 +1,156 @@
+package docs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRecordExamples(t *testing.T) {
+	const daemonID = "12D3KooWDaemon"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v0/version":
+			fmt.Fprint(w, `{"Version": "0.30.0"}`)
+		case "/api/v0/id":
+			fmt.Fprintf(w, `{"ID": %q, "PublicKey": "daemon-key", "Addresses": ["/ip4/10.1.2.3/tcp/4001/p2p/%s", "/ip4/127.0.0.1/tcp/4001", "/ip6/fe80::1/tcp/4001", "/ip4/10.1.2.3/udp/4001/quic-v1"]}`, daemonID, daemonID)
+		case "/api/v0/add":
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			b, _ := io.ReadAll(file)
+			if string(b) != ExampleFixture {
+				http.Error(w, "unexpected fixture", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"Name": "fixture.txt", "Hash": "QmFixture", "Size": "42"}`)
+		case "/api/v0/cat":
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, r.URL.Query().Get("arg"))
+		case "/api/v0/repo/stat":
+			fmt.Fprint(w, `{"RepoPath": "/home/alice/.ipfs", "NumObjects": 1}`)
+		case "/api/v0/swarm/peers":
+			fmt.Fprint(w, `{"Peers": [{"Peer": "a"}, {"Peer": "b"}, {"Peer": "c"}, {"Peer": "d"}]}`)
+		case metricsPath:
+			w.Header().Set("Content-Type", mimePrometheus)
+			fmt.Fprint(w, "# HELP ipfs_http_gw_requests_total Gateway requests.\n# TYPE ipfs_http_gw_requests_total counter\nipfs_http_gw_requests_total{code=\"200\"} 1\n")
+		default:
+			http.Error(w, "not available", http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	var api []*Endpoint
+	for _, name := range []string{"add", "cat", "id", "key/list", "repo/stat", "swarm/peers"} {
+		api = append(api, &Endpoint{Name: APIPrefix + "/" + name, Response: textResponse})
+	}
+	examples, err := RecordExamples(context.Background(), ts.Client(), ts.URL, api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]any)
+	for _, example := range examples {
+		if example.KuboVersion != "0.30.0" {
+			t.Errorf("%s: version %q", example.Endpoint, example.KuboVersion)
+		}
+		got[strings.TrimPrefix(example.Endpoint, APIPrefix+"/")] = example.Response
+	}
+	if families, ok := got[metricsPath].([]MetricFamily); !ok {
+		t.Error("the metric families are not recorded")
+	} else if len(families) != 1 || families[0].Name != "ipfs_http_gw_requests_total" || !slices.Equal(families[0].Labels, []string{"code"}) {
+		t.Errorf("unexpected metric families %+v", families)
+	}
+	delete(got, metricsPath)
+	want := map[string]any{
+		"add": map[string]any{"Name": "fixture.txt", "Hash": "QmFixture", "Size": "42"},
+		"cat": "QmFixture",
+		"id": map[string]any{
+			"ID":        examplePeerID,
+			"PublicKey": got["id"].(map[string]any)["PublicKey"],
+			"Addresses": []any{"/ip4/192.0.2.1/tcp/4001/p2p/" + examplePeerID, "/ip4/127.0.0.1/tcp/4001", "/ip6/2001:db8::1/tcp/4001"},
+		},
+		"repo/stat":   map[string]any{"RepoPath": exampleRepoPath, "NumObjects": float64(1)},
+		"swarm/peers": map[string]any{"Peers": []any{map[string]any{"Peer": "a"}, map[string]any{"Peer": "b"}, map[string]any{"Peer": "c"}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got["id"].(map[string]any)["PublicKey"] == "daemon-key" {
+		t.Error("the public key of the daemon is not sanitized")
+	}
+}
+
+func TestRecordedMetricFamilies(t *testing.T) {
+	dir := t.TempDir()
+	recorded := []*RecordedExample{{
+		Endpoint:    metricsPath,
+		KuboVersion: "0.30.0",
+		Response:    []MetricFamily{{Name: "ipfs_http_gw_requests_total", Type: "counter", Help: "Gateway requests.", Labels: []string{"code"}}},
+	}}
+	if err := WriteExamples(dir, recorded); err != nil {
+		t.Fatal(err)
+	}
+	examples, err := LoadExamples(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	families, err := RecordedMetricFamilies(examples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(families, recorded[0].Response) {
+		t.Errorf("got %+v, want %+v", families, recorded[0].Response)
+	}
+}
+
+func TestLoadExamples(t *testing.T) {
+	dir := t.TempDir()
+	recorded := []*RecordedExample{
+		{Endpoint: "/fixture/v0/json", KuboVersion: "0.30.0", Response: map[string]any{"Name": "recorded"}},
+	}
+	if err := WriteExamples(dir, recorded); err != nil {
+		t.Fatal(err)
+	}
+	examples, err := LoadExamples(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(examples) != 1 || examples["/fixture/v0/json"] == nil {
+		t.Fatalf("unexpected examples %v", examples)
+	}
+
+	api, _ := fixtureEndpoints(t)
+	formatter := OpenAPIFormatter{Examples: examples}
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := formatter.spec.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Responses map[string]struct {
+				Content map[string]json.RawMessage
+			}
+		}
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		t.Fatal(err)
+	}
+	media := string(doc.Paths["/fixture/v0/json"]["post"].Responses["200"].Content["application/json"])
+	if !strings.Contains(media, `"recorded"`) || strings.Contains(media, `"example"`) {
+		t.Errorf("the recorded example is not embedded: %s", media)
+	}
+}
@@ -0,0 +1,60 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ExecFormatterPrefix is the prefix of the formatters of http-api-docs which
+// are external executables, e.g. "exec:./my-formatter".
+const ExecFormatterPrefix = "exec:"
+
+// ExecFormatter runs an external executable as a formatter, so that
+// downstream projects can generate their own formats (e.g. Docusaurus MDX)
+// without forking. The executable receives a dump of the endpoints (see
+// WriteEndpoints and IRSchema) on its standard input and writes the
+// document on its standard output. It fails by exiting with a non-zero
+// status.
+type ExecFormatter struct {
+	// Command is the path of the executable.
+	Command string
+	// Args are the arguments passed to it.
+	Args []string
+	// Stderr receives the standard error of the executable, e.g. its
+	// warnings. Defaults to os.Stderr.
+	Stderr io.Writer
+}
+
+// NewExecFormatter returns the ExecFormatter of a command line, e.g.
+// "./my-formatter --flavor=mdx", with or without ExecFormatterPrefix.
+func NewExecFormatter(commandLine string) (*ExecFormatter, error) {
+	fields := strings.Fields(strings.TrimPrefix(commandLine, ExecFormatterPrefix))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no command in formatter %q", commandLine)
+	}
+	return &ExecFormatter{Command: fields[0], Args: fields[1:]}, nil
+}
+
+// Generate runs the executable on the endpoints and returns its output.
+func (ef *ExecFormatter) Generate(ctx context.Context, api []*Endpoint) (string, error) {
+	var stdin, stdout bytes.Buffer
+	if err := WriteEndpoints(&stdin, api); err != nil {
+		return "", err
+	}
+	cmd := exec.CommandContext(ctx, ef.Command, ef.Args...)
+	cmd.Stdin = &stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = ef.Stderr
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s: %w", ef.Command, err)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,54 @@
+package docs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestExecFormatterProcess is the external formatter run by
+// TestExecFormatter: it prints the endpoints it reads on stdin.
+func TestExecFormatterProcess(t *testing.T) {
+	if os.Getenv("EXEC_FORMATTER_PROCESS") == "" {
+		t.Skip("run by TestExecFormatter")
+	}
+	dump, err := ReadEndpoints(os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if os.Args[len(os.Args)-1] == "fail" {
+		os.Exit(2)
+	}
+	for _, endp := range dump.Endpoints {
+		fmt.Println(endp.Name)
+	}
+	os.Exit(0)
+}
+
+func TestExecFormatter(t *testing.T) {
+	t.Setenv("EXEC_FORMATTER_PROCESS", "1")
+	api := []*Endpoint{{Name: "/api/v0/a"}, {Name: "/api/v0/b"}}
+
+	f, err := NewExecFormatter(ExecFormatterPrefix + os.Args[0] + " -test.run=^TestExecFormatterProcess$")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := f.Generate(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "/api/v0/a\n/api/v0/b\n" {
+		t.Errorf("unexpected output %q", out)
+	}
+
+	f.Args = append(f.Args, "--", "fail")
+	if _, err := f.Generate(context.Background(), api); err == nil {
+		t.Errorf("expected an error when the formatter fails")
+	}
+
+	if _, err := NewExecFormatter(ExecFormatterPrefix); err == nil {
+		t.Errorf("expected an error without command")
+	}
+}
@@ -0,0 +1,251 @@
+package docs
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// This file contains a synthetic command tree, independent of Kubo, which
+// covers every argument type, status, option type and body shape supported
+// by the generator. It is used to test both formatters.
+
+const fixturePrefix = "/fixture/v0"
+
+type fixtureOutput struct {
+	Name   string
+	Size   int64
+	Tags   []string
+	Counts map[string]int
+}
+
+func fixtureRun(*cmds.Request, cmds.ResponseEmitter, cmds.Environment) error { return nil }
+
+var fixtureRoot = &cmds.Command{
+	Subcommands: map[string]*cmds.Command{
+		// Every option type, with and without defaults, and a text body.
+		"options": {
+			Status:   cmds.Active,
+			Helptext: cmds.HelpText{Tagline: "Command with all option types."},
+			Options: []cmds.Option{
+				cmds.BoolOption("bool", "A bool option.").WithDefault(true),
+				cmds.IntOption("int", "An int option.").WithDefault(-1),
+				cmds.UintOption("uint", "An uint option."),
+				cmds.Int64Option("int64", "An int64 option."),
+				cmds.Uint64Option("uint64", "An uint64 option."),
+				cmds.FloatOption("float", "A float option."),
+				cmds.StringOption("string", "A string option.").WithDefault("value"),
+				cmds.StringsOption("strings", "A strings option."),
+			},
+			Run: fixtureRun,
+		},
+		// A single argument and a JSON body.
+		"json": {
+			Status:   cmds.Active,
+			Helptext: cmds.HelpText{Tagline: "Command returning JSON."},
+			Arguments: []cmds.Argument{
+				cmds.StringArg("key", true, false, "A required argument."),
+			},
+			Type: fixtureOutput{},
+			Run:  fixtureRun,
+		},
+		// A streamed JSON body.
+		"stream": {
+			Status:   cmds.Active,
+			Helptext: cmds.HelpText{Tagline: "Command streaming JSON."},
+			Options: []cmds.Option{
+				cmds.BoolOption("legacy", "Old output format. (DEPRECATED)"),
+			},
+			Type: fixtureOutput{},
+			Run:  fixtureRun,
+		},
+		// A file argument, which becomes the request body.
+		"upload": {
+			Status:   cmds.Experimental,
+			Helptext: cmds.HelpText{Tagline: "Command taking a file."},
+			Arguments: []cmds.Argument{
+				cmds.FileArg("path", true, true, "The file to upload."),
+			},
+			Type: fixtureOutput{},
+			Run:  fixtureRun,
+		},
+		// Several positional arguments.
+		"multi": {
+			Status:   cmds.Deprecated,
+			Helptext: cmds.HelpText{Tagline: "Command with several arguments."},
+			Arguments: []cmds.Argument{
+				cmds.StringArg("from", true, false, "First argument."),
+				cmds.StringArg("to", false, false, "Second argument."),
+			},
+			Run: fixtureRun,
+		},
+		"removed": {
+			Status:   cmds.Removed,
+			Helptext: cmds.HelpText{Tagline: "Removed command."},
+			Run:      fixtureRun,
+		},
+		// Commands which are not endpoints.
+		"local": {
+			NoRemote: true,
+			Run:      fixtureRun,
+		},
+		"parent": {
+			Subcommands: map[string]*cmds.Command{
+				"child": {
+					Helptext: cmds.HelpText{Tagline: "Subcommand of a command without Run."},
+					Run:      fixtureRun,
+				},
+			},
+		},
+	},
+}
+
+// fixtureEndpoints returns the endpoints of the fixture command tree, indexed
+// by their name without prefix. The stream endpoint is marked streaming here,
+// as streamingEndpoints does for the Kubo ones, so that the overrides aren't
+// changed by the tests.
+func fixtureEndpoints(t *testing.T) ([]*Endpoint, map[string]*Endpoint) {
+	t.Helper()
+	api := Endpoints(fixturePrefix, fixtureRoot)
+	byName := make(map[string]*Endpoint)
+	for _, endp := range api {
+		byName[strings.TrimPrefix(endp.Name, fixturePrefix+"/")] = endp
+	}
+	byName["stream"].Streaming = true
+	return api, byName
+}
+
+func TestFixtureEndpoints(t *testing.T) {
+	api, byName := fixtureEndpoints(t)
+
+	var names []string
+	for _, endp := range api {
+		names = append(names, strings.TrimPrefix(endp.Name, fixturePrefix+"/"))
+	}
+	if got, want := strings.Join(names, " "), "json multi options parent/child removed stream upload"; got != want {
+		t.Fatalf("got endpoints %q, want %q", got, want)
+	}
+
+	var types []string
+	for _, opt := range byName["options"].Options {
+		types = append(types, opt.Name+":"+opt.Type+"="+opt.Default)
+	}
+	want := "bool:bool=true int:int=-1 uint:uint= int64:int64= uint64:uint64= float:float64= string:string=value strings:array="
+	if got := strings.Join(types, " "); got != want {
+		t.Errorf("got options %q, want %q", got, want)
+	}
+
+	if arg := byName["upload"].Arguments[0]; arg.Type != "file" || !arg.Required {
+		t.Errorf("upload should take a required file argument, got %+v", arg)
+	}
+	if byName["removed"].Status != cmds.Removed || byName["upload"].Status != cmds.Experimental || byName["multi"].Status != cmds.Deprecated {
+		t.Errorf("statuses were not extracted")
+	}
+	if !byName["stream"].Streaming || byName["json"].Streaming {
+		t.Errorf("only stream should be streaming")
+	}
+	if byName["json"].ResponseType != "http-api-docs.fixtureOutput" {
+		t.Errorf("unexpected response type %q", byName["json"].ResponseType)
+	}
+}
+
+func TestFixtureMarkdown(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	doc := generateDocs(t, api, new(MarkdownFormatter))
+
+	for _, want := range []string{
+		"## Experimental RPC commands",
+		"## Deprecated RPC commands",
+		"## Removed RPC commands",
+		"## " + fixturePrefix + "/options",
+		"This endpoint returns a `text/plain` response body.",
+		"stream newline-delimited JSON objects",
+		"### Request Body",
+		"\"Counts\": {",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("markdown does not contain %q", want)
+		}
+	}
+}
+
+func TestFixtureOpenAPI(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	formatter := new(OpenAPIFormatter)
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+	paths := formatter.spec.Paths.MapOfPathItemValues
+	op := func(name string) *openapi3.Operation {
+		op, ok := paths[fixturePrefix+"/"+name].MapOfOperationValues["post"]
+		if !ok {
+			t.Fatalf("missing operation for %s", name)
+		}
+		return &op
+	}
+
+	schemas := make(map[string]*openapi3.Schema)
+	for _, p := range op("options").Parameters {
+		if p.Parameter == nil {
+			// A global parameter.
+			continue
+		}
+		schemas[p.Parameter.Name] = p.Parameter.Schema.Schema
+	}
+	for name, want := range map[string]struct {
+		typ    openapi3.SchemaType
+		format string
+	}{
+		"bool":    {openapi3.SchemaTypeBoolean, ""},
+		"int":     {openapi3.SchemaTypeInteger, ""},
+		"uint":    {openapi3.SchemaTypeInteger, ""},
+		"int64":   {openapi3.SchemaTypeInteger, "int64"},
+		"uint64":  {openapi3.SchemaTypeInteger, "int64"},
+		"float":   {openapi3.SchemaTypeNumber, "double"},
+		"string":  {openapi3.SchemaTypeString, ""},
+		"strings": {openapi3.SchemaTypeArray, ""},
+	} {
+		s := schemas[name]
+		var format string
+		if s.Format != nil {
+			format = *s.Format
+		}
+		if *s.Type != want.typ || format != want.format {
+			t.Errorf("option %s: got type %q (%q), want %q (%q)", name, *s.Type, format, want.typ, want.format)
+		}
+	}
+	if s := schemas["uint64"]; s.Minimum == nil || *s.Minimum != 0 {
+		t.Errorf("uint64 should have a minimum of 0")
+	}
+	if s := schemas["strings"]; *s.Items.Schema.Type != openapi3.SchemaTypeString {
+		t.Errorf("strings should be an array of strings")
+	}
+	if _, ok := op("options").Responses.MapOfResponseOrRefValues["200"].Response.Content["text/plain"]; !ok {
+		t.Errorf("options should respond with text/plain")
+	}
+
+	if _, ok := op("stream").Responses.MapOfResponseOrRefValues["200"].Response.Content[mimeNDJSON]; !ok {
+		t.Errorf("stream should respond with %s", mimeNDJSON)
+	}
+	if _, ok := op("json").Responses.MapOfResponseOrRefValues["200"].Response.Content["application/json"]; !ok {
+		t.Errorf("json should respond with application/json")
+	}
+	if rb := op("upload").RequestBody; rb == nil || rb.RequestBody.Content["multipart/form-data"].Schema == nil {
+		t.Errorf("upload should take a multipart/form-data body")
+	}
+	var multi []*openapi3.Parameter
+	for _, p := range op("multi").Parameters {
+		if p.Parameter != nil {
+			multi = append(multi, p.Parameter)
+		}
+	}
+	if len(multi) != 1 || multi[0].Schema.Schema.Type == nil || *multi[0].Schema.Schema.Type != openapi3.SchemaTypeArray {
+		t.Errorf("multi should take its arguments as an array")
+	}
+	if _, ok := paths[fixturePrefix+"/removed"]; !ok {
+		t.Errorf("removed endpoints should be part of the spec")
+	}
+}
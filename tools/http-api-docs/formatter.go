@@ -2,28 +2,55 @@ package docs
 
 import (
 	"bytes"
+	"context"
 
 	cmds "github.com/ipfs/go-ipfs-cmds"
 )
 
-// Formatter allows to implement generation of docs in different formats.
+// Formatter generates a document from the endpoints, e.g. the Markdown
+// reference (MarkdownFormatter) or the OpenAPI spec (OpenAPIFormatter).
+// Formatters outside of this package can be run on the endpoints returned
+// by AllEndpoints just like the built-in ones.
 type Formatter interface {
+	Generate(ctx context.Context, api []*Endpoint) (string, error)
+}
+
+// BlockFormatter generates the blocks of a document made of a section per
+// endpoint, which GenerateDocs puts together.
+type BlockFormatter interface {
 	GenerateIntro() string
 	GenerateStatusIntro(status cmds.Status) string
 	GenerateIndex(endp []*Endpoint) string
 	GenerateEndpointBlock(endp *Endpoint) string
 	GenerateArgumentsBlock(args []*Argument, opts []*Argument) string
 	GenerateBodyBlock(args []*Argument) string
-	GenerateResponseBlock(response string) string
+	GenerateResponseBlock(endp *Endpoint) string
 	GenerateExampleBlock(endp *Endpoint) string
+	GenerateResponseTypeIndex(endps []*Endpoint) string
+	GenerateCLIOnlyIndex(commands []CLIOnlyCommand) string
+	// Err returns the first error met while generating the blocks, if
+	// any.
+	Err() error
 }
 
 // GenerateDocs uses a formatter to generate documentation for every endpoint
-func GenerateDocs(api []*Endpoint, formatter Formatter) string {
+func GenerateDocs(api []*Endpoint, formatter BlockFormatter) (string, error) {
 	buf := new(bytes.Buffer)
 	buf.WriteString(formatter.GenerateIntro())
+	buf.WriteString(formatter.GenerateIndex(api))
+	generateEndpoints(buf, api, formatter)
+	buf.WriteString(formatter.GenerateResponseTypeIndex(api))
+	buf.WriteString(formatter.GenerateCLIOnlyIndex(CLIOnlyCommands()))
+	if err := formatter.Err(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
 
-	for _, status := range []cmds.Status{cmds.Active, cmds.Experimental, cmds.Deprecated, cmds.Removed} {
+// generateEndpoints writes the documentation of the given endpoints, grouped
+// by status.
+func generateEndpoints(buf *bytes.Buffer, api []*Endpoint, formatter BlockFormatter) {
+	for _, status := range AllStatuses {
 		endpoints := InStatus(api, status)
 		if len(endpoints) == 0 {
 			continue
@@ -33,9 +60,8 @@ func GenerateDocs(api []*Endpoint, formatter Formatter) string {
 			buf.WriteString(formatter.GenerateEndpointBlock(endp))
 			buf.WriteString(formatter.GenerateArgumentsBlock(endp.Arguments, endp.Options))
 			buf.WriteString(formatter.GenerateBodyBlock(endp.Arguments))
-			buf.WriteString(formatter.GenerateResponseBlock(endp.Response))
+			buf.WriteString(formatter.GenerateResponseBlock(endp))
 			buf.WriteString(formatter.GenerateExampleBlock(endp))
 		}
 	}
-	return buf.String()
 }
@@ -0,0 +1,254 @@
+package docs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// This file describes the IPFS HTTP Gateway
+// (https://specs.ipfs.tech/http-gateways/path-gateway/ and
+// https://specs.ipfs.tech/http-gateways/trustless-gateway/). Unlike the RPC
+// API, it is not made of commands, so its operations are modeled by hand.
+
+// GatewayFormatter generates the OpenAPI spec of the path gateway, served
+// by Kubo on Addresses.Gateway.
+type GatewayFormatter struct {
+	// Info overrides the default metadata. The default server is
+	// http://127.0.0.1:8080.
+	Info OpenAPIInfo
+}
+
+// gatewayFormats are the response formats which can be requested with the
+// format parameter or the matching Accept header.
+var gatewayFormats = []struct {
+	format      string
+	mime        string
+	description string
+}{
+	{"raw", "application/vnd.ipld.raw", "The raw block of the CID, without deserialization."},
+	{"car", "application/vnd.ipld.car", "A CAR archive of the blocks of the content path (see dag-scope and entity-bytes)."},
+	{"tar", "application/x-tar", "A TAR archive of the UnixFS file or directory."},
+	{"dag-json", "application/vnd.ipld.dag-json", "The DAG-JSON representation of the block."},
+	{"dag-cbor", "application/vnd.ipld.dag-cbor", "The DAG-CBOR representation of the block."},
+	{"json", "application/json", "The block as JSON, for blocks of the json codec."},
+	{"cbor", "application/cbor", "The block as CBOR, for blocks of the cbor codec."},
+	{"ipns-record", "application/vnd.ipfs.ipns-record", "The signed IPNS record of the name (/ipns only)."},
+}
+
+// gatewayHeaders are the headers of successful responses.
+var gatewayHeaders = []struct {
+	name        string
+	description string
+}{
+	{"Etag", "Identifies the response, for If-None-Match. Derived from the CID and the format."},
+	{"Cache-Control", "`public, max-age=29030400, immutable` for /ipfs, and the TTL of the name for /ipns."},
+	{"Last-Modified", "The modification time of the UnixFS file, if known."},
+	{"Content-Disposition", "`inline` or `attachment` (with download=true), with the file name (filename parameter)."},
+	{"X-Ipfs-Path", "The requested content path."},
+	{"X-Ipfs-Roots", "The CIDs of the path segments, from the root to the requested content."},
+	{"X-Content-Type-Options", "`nosniff` for formats other than deserialized content."},
+}
+
+// gatewayErrors are the error responses of the gateway, with a text/plain
+// body.
+var gatewayErrors = []struct {
+	status      int
+	description string
+}{
+	{http.StatusBadRequest, "Invalid content path, CID or parameters."},
+	{http.StatusNotFound, "The content path does not exist."},
+	{http.StatusGone, "The content is blocked by a denylist."},
+	{http.StatusPreconditionFailed, "`Cache-Control: only-if-cached` was sent, and the content is not in the local store."},
+	{http.StatusRequestedRangeNotSatisfiable, "The Range can't be satisfied."},
+	{http.StatusTooManyRequests, "Rate limited. See Retry-After."},
+	{http.StatusUnavailableForLegalReasons, "The content is blocked for legal reasons."},
+	{http.StatusInternalServerError, "The gateway failed."},
+	{http.StatusBadGateway, "The content could not be retrieved from the network."},
+	{http.StatusGatewayTimeout, "Retrieving the content timed out."},
+}
+
+// specParam is a parameter of a hand-modeled operation.
+type specParam struct {
+	name        string
+	in          openapi3.ParameterIn
+	description string
+	required    bool
+	schema      *openapi3.Schema
+}
+
+func (p specParam) parameter() openapi3.ParameterOrRef {
+	param := &openapi3.Parameter{
+		Name:        p.name,
+		In:          p.in,
+		Description: &p.description,
+		Schema:      &openapi3.SchemaOrRef{Schema: p.schema},
+	}
+	if p.required || p.in == openapi3.ParameterInPath {
+		param.Required = ptr(true)
+	}
+	return openapi3.ParameterOrRef{Parameter: param}
+}
+
+// stringSchema returns the schema of a string, limited to the given values
+// if any.
+func stringSchema(enum ...string) *openapi3.Schema {
+	schema := (&openapi3.Schema{}).WithType(openapi3.SchemaTypeString)
+	for _, v := range enum {
+		schema.Enum = append(schema.Enum, v)
+	}
+	return schema
+}
+
+// gatewayParams are the parameters of all the operations, besides the path
+// parameters.
+func gatewayParams() []specParam {
+	var formats []string
+	formatDescription := "Response format, taking precedence over Accept. Deserialized content is returned when not set.\n"
+	for _, f := range gatewayFormats {
+		formats = append(formats, f.format)
+		formatDescription += fmt.Sprintf("\n- `%s` (%s): %s", f.format, f.mime, f.description)
+	}
+	return []specParam{
+		{"format", openapi3.ParameterInQuery, formatDescription, false, stringSchema(formats...)},
+		{"filename", openapi3.ParameterInQuery, "File name for the Content-Disposition header.", false, stringSchema()},
+		{"download", openapi3.ParameterInQuery, "With `true`, the response is sent as an attachment.", false, stringSchema("true")},
+		{"dag-scope", openapi3.ParameterInQuery, "Blocks to include in a CAR response: the `block` of the path, the `entity` (whole file, or directory without its children) or `all` (the whole DAG, default).", false, stringSchema("block", "entity", "all")},
+		{"entity-bytes", openapi3.ParameterInQuery, "Byte range of the file to include in a CAR response, as `from:to` (inclusive, `*` for the end, negative offsets from the end).", false, stringSchema()},
+		{"car-version", openapi3.ParameterInQuery, "Version of the CAR response.", false, stringSchema("1")},
+		{"car-order", openapi3.ParameterInQuery, "Order of the blocks in a CAR response: depth-first (`dfs`) or unknown (`unk`).", false, stringSchema("dfs", "unk")},
+		{"car-dups", openapi3.ParameterInQuery, "Whether duplicate blocks are sent (`y`) or not (`n`) in a CAR response.", false, stringSchema("y", "n")},
+		{"Accept", openapi3.ParameterInHeader, "Response format, as the media types of the format parameter. CAR parameters can be passed as in `application/vnd.ipld.car; version=1; order=dfs; dups=n`.", false, stringSchema()},
+		{"Range", openapi3.ParameterInHeader, "Byte range of a deserialized file, e.g. `bytes=0-1023`.", false, stringSchema()},
+		{"If-None-Match", openapi3.ParameterInHeader, "Etag of a cached response. The gateway answers 304 if it still matches.", false, stringSchema()},
+		{"Cache-Control", openapi3.ParameterInHeader, "With `only-if-cached`, only local content is returned (412 otherwise).", false, stringSchema()},
+	}
+}
+
+// Generate returns the spec as YAML. The gateway is not made of commands,
+// so the endpoints are ignored.
+func (gf *GatewayFormatter) Generate(ctx context.Context, _ []*Endpoint) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	spec, err := gf.genSpec()
+	if err != nil {
+		return "", err
+	}
+	out, err := spec.MarshalYAML()
+	return string(out), err
+}
+
+func (gf *GatewayFormatter) genSpec() (*openapi3.Spec, error) {
+	info := gf.Info
+	if info.Title == "" {
+		info.Title = "IPFS HTTP Gateway"
+	}
+	if info.Version == "" {
+		info.Version = IPFSVersion()
+	}
+	if info.Description == "" {
+		info.Description = "The path gateway of Kubo, serving content-addressed data over HTTP, deserialized or in verifiable formats (raw blocks and CAR archives), as specified by https://specs.ipfs.tech/http-gateways/."
+	}
+	if len(info.Servers) == 0 {
+		info.Servers = []string{"http://127.0.0.1:8080"}
+	}
+
+	spec := &openapi3.Spec{Openapi: "3.0.0"}
+	spec.Info.WithTitle(info.Title).WithVersion(info.Version).WithDescription(info.Description)
+	for _, url := range info.Servers {
+		spec.Servers = append(spec.Servers, openapi3.Server{URL: url})
+	}
+	spec.WithExternalDocs(openapi3.ExternalDocumentation{URL: "https://specs.ipfs.tech/http-gateways/path-gateway/"})
+
+	roots := []struct {
+		namespace, param, description string
+	}{
+		{"ipfs", "cid", "CID of the root of the content."},
+		{"ipns", "name", "IPNS name: a libp2p key (CIDv1 in base36, or a peer ID) or a DNSLink domain."},
+	}
+	for _, root := range roots {
+		for _, withPath := range []bool{false, true} {
+			path := fmt.Sprintf("/%s/{%s}", root.namespace, root.param)
+			params := []specParam{{root.param, openapi3.ParameterInPath, root.description, true, stringSchema()}}
+			if withPath {
+				path += "/{path}"
+				params = append(params, specParam{"path", openapi3.ParameterInPath, "Path inside the root, which may contain slashes.", true, stringSchema()})
+			}
+			params = append(params, gatewayParams()...)
+			for _, method := range []string{http.MethodGet, http.MethodHead} {
+				op := genGatewayOperation(method, root.namespace, withPath, params)
+				if err := spec.AddOperation(method, path, op); err != nil {
+					return nil, fmt.Errorf("%s %s: %w", method, path, err)
+				}
+			}
+		}
+	}
+	return spec, nil
+}
+
+func genGatewayOperation(method, namespace string, withPath bool, params []specParam) openapi3.Operation {
+	id := map[string]string{http.MethodGet: "get", http.MethodHead: "head"}[method] + exportName(namespace)
+	summary := fmt.Sprintf("Retrieve /%s content", namespace)
+	if withPath {
+		id += "Path"
+		summary += " at a path"
+	}
+	if method == http.MethodHead {
+		summary = fmt.Sprintf("Headers of the /%s content", namespace)
+		if withPath {
+			summary += " at a path"
+		}
+	}
+	op := openapi3.Operation{ID: &id, Summary: &summary}
+	for _, p := range params {
+		op.Parameters = append(op.Parameters, p.parameter())
+	}
+
+	headers := make(map[string]openapi3.HeaderOrRef)
+	for _, h := range gatewayHeaders {
+		description := h.description
+		headers[h.name] = openapi3.HeaderOrRef{Header: &openapi3.Header{
+			Description: &description,
+			Schema:      &openapi3.SchemaOrRef{Schema: stringSchema()},
+		}}
+	}
+	ok := openapi3.Response{Description: "The content, in the requested format.", Headers: headers}
+	partial := openapi3.Response{
+		Description: "The requested Range of a deserialized file.",
+		Headers: map[string]openapi3.HeaderOrRef{"Content-Range": {Header: &openapi3.Header{
+			Schema: &openapi3.SchemaOrRef{Schema: stringSchema()},
+		}}},
+	}
+	if method == http.MethodGet {
+		binary := openapi3.MediaType{Schema: &openapi3.SchemaOrRef{Schema: stringSchema().WithFormat("binary")}}
+		ok.Content = map[string]openapi3.MediaType{"*/*": binary}
+		for _, f := range gatewayFormats {
+			ok.Content[f.mime] = binary
+		}
+		partial.Content = map[string]openapi3.MediaType{"*/*": binary}
+	}
+
+	op.Responses.WithMapOfResponseOrRefValuesItem("200", openapi3.ResponseOrRef{Response: &ok})
+	op.Responses.WithMapOfResponseOrRefValuesItem("206", openapi3.ResponseOrRef{Response: &partial})
+	op.Responses.WithMapOfResponseOrRefValuesItem("301", openapi3.ResponseOrRef{Response: &openapi3.Response{
+		Description: "Redirect of a directory to the path with a trailing slash.",
+		Headers: map[string]openapi3.HeaderOrRef{"Location": {Header: &openapi3.Header{
+			Schema: &openapi3.SchemaOrRef{Schema: stringSchema()},
+		}}},
+	}})
+	op.Responses.WithMapOfResponseOrRefValuesItem("304", openapi3.ResponseOrRef{Response: &openapi3.Response{
+		Description: "The Etag of If-None-Match still matches.",
+	}})
+	for _, e := range gatewayErrors {
+		resp := openapi3.Response{Description: e.description}
+		if method == http.MethodGet {
+			resp.Content = map[string]openapi3.MediaType{"text/plain": {Schema: &openapi3.SchemaOrRef{Schema: stringSchema()}}}
+		}
+		op.Responses.WithMapOfResponseOrRefValuesItem(strconv.Itoa(e.status), openapi3.ResponseOrRef{Response: &resp})
+	}
+	return op
+}
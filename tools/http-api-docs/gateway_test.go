@@ -0,0 +1,72 @@
+package docs
+
+import (
+	"context"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGatewayFormatter(t *testing.T) {
+	out, err := new(GatewayFormatter).Generate(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type operation struct {
+		OperationID string `yaml:"operationId"`
+		Parameters  []struct {
+			Name string
+			In   string
+		}
+		Responses map[string]struct {
+			Content map[string]any
+		}
+	}
+	var spec struct {
+		Info struct {
+			Title string
+		}
+		Servers []struct {
+			URL string
+		}
+		Paths map[string]map[string]operation
+	}
+	if err := yaml.Unmarshal([]byte(out), &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Info.Title != "IPFS HTTP Gateway" || len(spec.Servers) != 1 || spec.Servers[0].URL != "http://127.0.0.1:8080" {
+		t.Errorf("unexpected info or servers: %+v %+v", spec.Info, spec.Servers)
+	}
+	for _, path := range []string{"/ipfs/{cid}", "/ipfs/{cid}/{path}", "/ipns/{name}", "/ipns/{name}/{path}"} {
+		item, ok := spec.Paths[path]
+		if !ok {
+			t.Errorf("missing %s", path)
+			continue
+		}
+		if _, ok := item["head"]; !ok {
+			t.Errorf("missing HEAD %s", path)
+		}
+		get := item["get"]
+		params := make(map[string]string)
+		for _, p := range get.Parameters {
+			params[p.Name] = p.In
+		}
+		for name, in := range map[string]string{"format": "query", "Accept": "header", "Range": "header"} {
+			if params[name] != in {
+				t.Errorf("%s: %s is in %q, want %q", path, name, params[name], in)
+			}
+		}
+		for _, mime := range []string{"application/vnd.ipld.car", "application/vnd.ipld.raw"} {
+			if _, ok := get.Responses["200"].Content[mime]; !ok {
+				t.Errorf("%s: 200 has no %s", path, mime)
+			}
+		}
+		if _, ok := get.Responses["206"]; !ok {
+			t.Errorf("%s: no 206 response", path)
+		}
+	}
+	if id := spec.Paths["/ipns/{name}/{path}"]["get"].OperationID; id != "getIpnsPath" {
+		t.Errorf("unexpected operation ID %q", id)
+	}
+}
@@ -0,0 +1,70 @@
+package docs
+
+import (
+	"slices"
+
+	corecmds "github.com/ipfs/kubo/core/commands"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// GlobalParameters returns the options of the root command, like timeout or
+// encoding, which every endpoint accepts. Their Endpoint is empty.
+func GlobalParameters() []*Argument {
+	var params []*Argument
+	for _, opt := range corecmds.Root.Options {
+		if cliRootOptions[opt.Names()[0]] {
+			continue
+		}
+		arg := optionArgument("", opt)
+		if patch, ok := globalParameterPatches[arg.Name]; ok {
+			if patch.Description != "" {
+				arg.Description = patch.Description
+			}
+			if patch.Default != "" {
+				arg.Default, arg.DefaultValue = patch.Default, patch.DefaultValue
+			}
+		}
+		params = append(params, arg)
+	}
+	return params
+}
+
+// globalParameterRef returns the reference to the component of a global
+// parameter.
+func globalParameterRef(name string) openapi3.ParameterOrRef {
+	return openapi3.ParameterOrRef{ParameterReference: &openapi3.ParameterReference{Ref: "#/components/parameters/" + name}}
+}
+
+// genGlobalParameters adds the global parameters to the components of the
+// spec, except the ones of SkipGlobalParameters, and returns their names.
+func (myself *OpenAPIFormatter) genGlobalParameters() []string {
+	w := &warnings{endpoint: "global parameters"}
+	var names []string
+	for _, arg := range GlobalParameters() {
+		if slices.Contains(myself.SkipGlobalParameters, arg.Name) {
+			continue
+		}
+		p := genParameterForArgument(w, arg, false)
+		myself.setProvenance(p.Schema.Schema, ProvenanceOption)
+		myself.spec.ComponentsEns().ParametersEns().WithMapOfParameterOrRefValuesItem(arg.Name, p.ToParameterOrRef())
+		names = append(names, arg.Name)
+	}
+	myself.Warnings = append(myself.Warnings, w.list...)
+	return names
+}
+
+// globalParameterRefs returns the references to the global parameters which
+// apply to an endpoint: not encoding when it returns text, which is written
+// as is, nor the ones it defines as options.
+func (myself *OpenAPIFormatter) globalParameterRefs(endp *Endpoint) []openapi3.ParameterOrRef {
+	var refs []openapi3.ParameterOrRef
+	for _, name := range myself.globalParameters {
+		if name == "encoding" && endp.Response == textResponse {
+			continue
+		}
+		if !slices.ContainsFunc(endp.Options, func(opt *Argument) bool { return opt.Name == name }) {
+			refs = append(refs, globalParameterRef(name))
+		}
+	}
+	return refs
+}
@@ -0,0 +1,59 @@
+package docs
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestGlobalParameters(t *testing.T) {
+	var names []string
+	for _, p := range GlobalParameters() {
+		names = append(names, p.Name)
+		if p.Name == "encoding" && p.Default != "json" {
+			t.Errorf("the default encoding of the RPC API is json, got %q", p.Default)
+		}
+	}
+	for _, name := range []string{"timeout", "encoding", "stream-channels", "offline"} {
+		if !slices.Contains(names, name) {
+			t.Errorf("missing global parameter %s in %v", name, names)
+		}
+	}
+	if slices.Contains(names, "repo-dir") || slices.Contains(names, "api") {
+		t.Errorf("CLI options should be left out, got %v", names)
+	}
+}
+
+func TestGlobalParameterRefs(t *testing.T) {
+	api := []*Endpoint{
+		{Name: "/api/v0/cat", Response: textResponse},
+		{Name: "/api/v0/id", Response: `{"ID": "<string>"}`, Options: []*Argument{{Name: "timeout", Type: "string"}}},
+	}
+	formatter := &OpenAPIFormatter{SkipGlobalParameters: []string{"stream-channels"}}
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+	components := formatter.spec.Components.Parameters.MapOfParameterOrRefValues
+	if _, ok := components["timeout"]; !ok {
+		t.Errorf("missing the timeout component")
+	}
+	if _, ok := components["stream-channels"]; ok {
+		t.Errorf("stream-channels should be skipped")
+	}
+
+	refs := func(name string) []string {
+		var refs []string
+		for _, p := range formatter.spec.Paths.MapOfPathItemValues[name].MapOfOperationValues["post"].Parameters {
+			if p.ParameterReference != nil {
+				refs = append(refs, p.ParameterReference.Ref)
+			}
+		}
+		return refs
+	}
+	if cat := refs("/api/v0/cat"); slices.Contains(cat, "#/components/parameters/encoding") || !slices.Contains(cat, "#/components/parameters/timeout") {
+		t.Errorf("cat returns text and should not reference encoding: %v", cat)
+	}
+	if id := refs("/api/v0/id"); slices.Contains(id, "#/components/parameters/timeout") || !slices.Contains(id, "#/components/parameters/encoding") {
+		t.Errorf("id defines its own timeout: %v", id)
+	}
+}
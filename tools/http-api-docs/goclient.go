@@ -0,0 +1,430 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// GoClientFormatter generates a typed Go client for the endpoints, as the
+// source of a single file. Each endpoint becomes a method of Client, taking
+// the positional arguments as parameters and the options as a struct, and
+// returning the response decoded into a generated type, a Stream of them
+// for streaming endpoints, or the body for endpoints returning text.
+type GoClientFormatter struct {
+	// Package is the name of the generated package. Defaults to "rpc".
+	Package string
+	// TypeMap overrides the types of placeholders (see LoadTypeMap).
+	TypeMap TypeMap
+}
+
+// goOptionTypes are the Go types of the option fields, by Argument type.
+// Fields are pointers so that unset options are not sent.
+var goOptionTypes = map[string]string{
+	"string":  "*string",
+	"bool":    "*bool",
+	"int":     "*int",
+	"uint":    "*uint",
+	"int64":   "*int64",
+	"uint64":  "*uint64",
+	"float64": "*float64",
+	"array":   "[]string",
+}
+
+// goQueryFormats format the value v of an option field for the query.
+var goQueryFormats = map[string]string{
+	"*string":  "*%s",
+	"*bool":    "strconv.FormatBool(*%s)",
+	"*int":     "strconv.Itoa(*%s)",
+	"*uint":    "strconv.FormatUint(uint64(*%s), 10)",
+	"*int64":   "strconv.FormatInt(*%s, 10)",
+	"*uint64":  "strconv.FormatUint(*%s, 10)",
+	"*float64": "strconv.FormatFloat(*%s, 'g', -1, 64)",
+}
+
+// Generate returns the source of the client.
+func (gf *GoClientFormatter) Generate(ctx context.Context, api []*Endpoint) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	pkg := gf.Package
+	if pkg == "" {
+		pkg = "rpc"
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, goClientHeader, IPFSVersion(), pkg)
+	methods := make(map[string]string)
+	for _, endp := range api {
+		method := pascalName(endp.Name)
+		if other, ok := methods[method]; ok {
+			return "", fmt.Errorf("%s and %s both map to method %s", other, endp.Name, method)
+		}
+		methods[method] = endp.Name
+		if err := genGoMethod(buf, gf.TypeMap, method, endp); err != nil {
+			return "", &EndpointError{Endpoint: endp.Name, Err: err}
+		}
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("formatting the client: %w", err)
+	}
+	return string(src), nil
+}
+
+// pascalName returns the operation ID of an endpoint in PascalCase, e.g.
+// PinAdd for /api/v0/pin/add, to name the generated methods and types.
+func pascalName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(strings.TrimPrefix(name, APIPrefix), "/") {
+		if part != "" {
+			b.WriteString(goIdent(part))
+		}
+	}
+	return b.String()
+}
+
+// goIdent turns a name like "cid-version" into an exported identifier,
+// like CidVersion.
+func goIdent(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	ident := b.String()
+	if ident == "" || unicode.IsDigit([]rune(ident)[0]) {
+		ident = "X" + ident
+	}
+	return ident
+}
+
+// goParamName returns the name of the parameter of an argument, which
+// must not shadow the other parameters or a keyword.
+func goParamName(name string) string {
+	param := goIdent(name)
+	param = strings.ToLower(param[:1]) + param[1:]
+	switch {
+	case token.IsKeyword(param), param == "ctx", param == "opts", param == "files", param == "query":
+		param += "Arg"
+	}
+	return param
+}
+
+func genGoMethod(buf *bytes.Buffer, types TypeMap, method string, endp *Endpoint) error {
+	// Options.
+	optsType := method + "Options"
+	fmt.Fprintf(buf, "\n// %s are the options of %s.\ntype %s struct {\n", optsType, method, optsType)
+	fields := make(map[string]bool)
+	var setQuery []string
+	for _, opt := range endp.Options {
+		typ, ok := goOptionTypes[opt.Type]
+		if !ok {
+			return fmt.Errorf("unsupported type %s for option %s", opt.Type, opt.Name)
+		}
+		field := goIdent(opt.Name)
+		for fields[field] {
+			field += "_"
+		}
+		fields[field] = true
+		if desc := goComment(opt.Description); desc != "" {
+			fmt.Fprintf(buf, "\t// %s\n", desc)
+		}
+		fmt.Fprintf(buf, "\t%s %s\n", field, typ)
+		if typ == "[]string" {
+			setQuery = append(setQuery, fmt.Sprintf("for _, v := range opts.%s {\nquery.Add(%q, v)\n}", field, opt.Name))
+		} else {
+			setQuery = append(setQuery, fmt.Sprintf("if opts.%s != nil {\nquery.Set(%q, %s)\n}", field, opt.Name, fmt.Sprintf(goQueryFormats[typ], "opts."+field)))
+		}
+	}
+	fmt.Fprintf(buf, "}\n")
+
+	// Response type.
+	resultType := ""
+	switch {
+	case endp.Response == "" || endp.Response == textResponse:
+	default:
+		var doc any
+		if err := json.Unmarshal([]byte(endp.Response), &doc); err != nil {
+			return fmt.Errorf("parsing the response: %w", err)
+		}
+		resultType = method + "Response"
+		fmt.Fprintf(buf, "\n// %s is the response of %s.\ntype %s %s\n", resultType, method, resultType, goType(types, doc))
+	}
+
+	// Parameters.
+	params := []string{"ctx context.Context"}
+	var addArgs []string
+	hasFiles := false
+	for _, arg := range endp.Arguments {
+		if arg.Type == "file" {
+			hasFiles = true
+			continue
+		}
+		param := goParamName(arg.Name)
+		switch {
+		case arg.Variadic:
+			params = append(params, param+" []string")
+			addArgs = append(addArgs, fmt.Sprintf("for _, v := range %s {\nquery.Add(\"arg\", v)\n}", param))
+		case arg.Required:
+			params = append(params, param+" string")
+			addArgs = append(addArgs, fmt.Sprintf("query.Add(\"arg\", %s)", param))
+		default:
+			params = append(params, param+" string")
+			addArgs = append(addArgs, fmt.Sprintf("if %s != \"\" {\nquery.Add(\"arg\", %s)\n}", param, param))
+		}
+	}
+	filesArg := "nil"
+	if hasFiles {
+		params = append(params, "files []File")
+		filesArg = "files"
+	}
+	params = append(params, "opts *"+optsType)
+
+	returns := "(io.ReadCloser, error)"
+	switch {
+	case resultType != "" && endp.Streaming:
+		returns = fmt.Sprintf("(*Stream[%s], error)", resultType)
+	case resultType != "":
+		returns = fmt.Sprintf("(*%s, error)", resultType)
+	}
+
+	fmt.Fprintf(buf, "\n// %s calls %s", method, endp.Name)
+	if desc := goComment(endp.Description); desc != "" {
+		fmt.Fprintf(buf, ": %s", strings.ToLower(desc[:1])+desc[1:])
+	}
+	fmt.Fprintf(buf, "\nfunc (c *Client) %s(%s) %s {\n", method, strings.Join(params, ", "), returns)
+	fmt.Fprintf(buf, "query := url.Values{}\n")
+	for _, s := range addArgs {
+		fmt.Fprintln(buf, s)
+	}
+	if len(setQuery) > 0 {
+		fmt.Fprintf(buf, "if opts != nil {\n%s\n}\n", strings.Join(setQuery, "\n"))
+	}
+	fmt.Fprintf(buf, "resp, err := c.call(ctx, %q, query, %s)\nif err != nil {\nreturn nil, err\n}\n", endp.Name, filesArg)
+	switch {
+	case resultType != "" && endp.Streaming:
+		fmt.Fprintf(buf, "return newStream[%s](resp), nil\n", resultType)
+	case resultType != "":
+		fmt.Fprintf(buf, "return decodeResponse[%s](resp)\n", resultType)
+	default:
+		fmt.Fprintf(buf, "return resp.Body, nil\n")
+	}
+	fmt.Fprintf(buf, "}\n")
+	return nil
+}
+
+// goType returns the Go type of a documented response.
+func goType(types TypeMap, x any) string {
+	switch v := x.(type) {
+	case string:
+		if t, ok := types.lookup(v); ok {
+			return t.Go
+		}
+	case []any:
+		if len(v) == 1 {
+			return "[]" + goType(types, v[0])
+		}
+		return "[]json.RawMessage"
+	case map[string]any:
+		if len(v) == 1 {
+			for k, item := range v {
+				if k == "<string>" {
+					return "map[string]" + goType(types, item)
+				}
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fields := make(map[string]bool)
+		var b strings.Builder
+		b.WriteString("struct {\n")
+		for _, k := range keys {
+			field := goIdent(k)
+			for fields[field] {
+				field += "_"
+			}
+			fields[field] = true
+			fmt.Fprintf(&b, "%s %s `json:%q`\n", field, goType(types, v[k]), k)
+		}
+		b.WriteString("}")
+		return b.String()
+	}
+	return "json.RawMessage"
+}
+
+// goComment returns a description on a single line, without the "Default"
+// part documented by the options already.
+func goComment(desc string) string {
+	return strings.Join(strings.Fields(fixDesc.ReplaceAllString(desc, "")), " ")
+}
+
+// goClientHeader is the start of the client, with the helpers used by the
+// methods.
+const goClientHeader = `// Code generated by http-api-docs from kubo v%s. DO NOT EDIT.
+
+// Package %s is a client of the Kubo RPC API.
+package %[2]s
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// strconv is only used by endpoints with options.
+var _ = strconv.Itoa
+
+// Client calls the RPC API at BaseURL, e.g. http://127.0.0.1:5001.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a Client using http.DefaultClient.
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: baseURL, HTTPClient: http.DefaultClient}
+}
+
+// File is a file sent in the multipart body of endpoints taking files.
+// Name is its path, e.g. "dir/file.txt", sent query-escaped as Kubo expects.
+type File struct {
+	Name   string
+	Reader io.Reader
+}
+
+// Error is an error returned by the RPC API.
+type Error struct {
+	Message string
+	Code    int
+	Type    string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (c *Client) call(ctx context.Context, path string, query url.Values, files []File) (*http.Response, error) {
+	var body io.Reader
+	contentType := ""
+	if files != nil {
+		pr, pw := io.Pipe()
+		mw := multipart.NewWriter(pw)
+		go func() {
+			pw.CloseWithError(writeFiles(mw, files))
+		}()
+		body = pr
+		contentType = mw.FormDataContentType()
+	}
+
+	u := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(resp.Body)
+		apiErr := new(Error)
+		if json.Unmarshal(msg, apiErr) != nil || apiErr.Message == "" {
+			apiErr = &Error{Message: fmt.Sprintf("%%s: %%s", resp.Status, strings.TrimSpace(string(msg)))}
+		}
+		return nil, apiErr
+	}
+	return resp, nil
+}
+
+func writeFiles(mw *multipart.Writer, files []File) error {
+	for _, f := range files {
+		part, err := mw.CreateFormFile("file", url.QueryEscape(f.Name))
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, f.Reader); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+func decodeResponse[T any](resp *http.Response) (*T, error) {
+	defer resp.Body.Close()
+	v := new(T)
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Stream reads the newline-delimited JSON values of streaming endpoints.
+type Stream[T any] struct {
+	resp *http.Response
+	dec  *json.Decoder
+}
+
+func newStream[T any](resp *http.Response) *Stream[T] {
+	return &Stream[T]{resp: resp, dec: json.NewDecoder(bufio.NewReader(resp.Body))}
+}
+
+// Next returns the next value, or io.EOF at the end of the stream. Errors
+// happening after the response started are returned at the end, from the
+// X-Stream-Error trailer.
+func (s *Stream[T]) Next() (*T, error) {
+	v := new(T)
+	err := s.dec.Decode(v)
+	if errors.Is(err, io.EOF) {
+		if msg := s.resp.Trailer.Get("X-Stream-Error"); msg != "" {
+			return nil, &Error{Message: msg, Type: "error"}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Close closes the response.
+func (s *Stream[T]) Close() error {
+	return s.resp.Body.Close()
+}
+`
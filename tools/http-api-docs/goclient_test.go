@@ -0,0 +1,74 @@
+package docs
+
+import (
+	"context"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+// typeCheck fails the test when src doesn't compile.
+func typeCheck(t *testing.T, src string) *types.Package {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "client.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("rpc", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkg
+}
+
+func TestGoClientFixture(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	src, err := (&GoClientFormatter{Package: "fixture"}).Generate(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := typeCheck(t, src)
+	if pkg.Name() != "fixture" {
+		t.Errorf("unexpected package %s", pkg.Name())
+	}
+
+	for _, want := range []string{
+		"func (c *Client) FixtureV0Json(ctx context.Context, key string, opts *FixtureV0JsonOptions) (*FixtureV0JsonResponse, error)",
+		"func (c *Client) FixtureV0Stream(ctx context.Context, opts *FixtureV0StreamOptions) (*Stream[FixtureV0StreamResponse], error)",
+		"func (c *Client) FixtureV0Upload(ctx context.Context, files []File, opts *FixtureV0UploadOptions) (*FixtureV0UploadResponse, error)",
+		"func (c *Client) FixtureV0Multi(ctx context.Context, from string, to string, opts *FixtureV0MultiOptions) (io.ReadCloser, error)",
+		"Counts map[string]int `json:\"Counts\"`",
+		"query.Set(\"uint64\", strconv.FormatUint(*opts.Uint64, 10))",
+		// The file names are unescaped with url.QueryUnescape by boxo/files.
+		"mw.CreateFormFile(\"file\", url.QueryEscape(f.Name))",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("client does not contain %q", want)
+		}
+	}
+}
+
+func TestGoClientKubo(t *testing.T) {
+	src, err := new(GoClientFormatter).Generate(context.Background(), AllEndpoints())
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeCheck(t, src)
+}
+
+func TestGoIdent(t *testing.T) {
+	for in, want := range map[string]string{"cid-version": "CidVersion", "/": "X", "2fa": "X2fa", "ID": "ID"} {
+		if got := goIdent(in); got != want {
+			t.Errorf("goIdent(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := goParamName("type"); got != "typeArg" {
+		t.Errorf("keywords should not be used as parameters, got %s", got)
+	}
+}
@@ -0,0 +1,63 @@
+package docs
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The golden tests render the fixture command tree (see fixture_test.go)
+// with each formatter and compare the result with testdata/. After an
+// intended change of the output, regenerate them with:
+//
+//	go test -run Golden -update
+var update = flag.Bool("update", false, "Update the golden files in testdata/ instead of comparing with them.")
+
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%s (run the test with -update to create it)", err)
+	}
+	if got != string(want) {
+		gotLines, wantLines := strings.Split(got, "\n"), strings.Split(string(want), "\n")
+		for i := 0; i < len(gotLines) && i < len(wantLines); i++ {
+			if gotLines[i] != wantLines[i] {
+				t.Fatalf("%s differs at line %d:\n got: %s\nwant: %s\n(run the test with -update if the change is intended)", path, i+1, gotLines[i], wantLines[i])
+			}
+		}
+		t.Fatalf("%s differs: got %d lines, want %d (run the test with -update if the change is intended)", path, len(gotLines), len(wantLines))
+	}
+}
+
+func TestGoldenMarkdown(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "0")
+	api, _ := fixtureEndpoints(t)
+	formatter := new(MarkdownFormatter)
+	// The intro is static text, and mentions the Kubo version.
+	doc := strings.TrimPrefix(generateDocs(t, api, formatter), formatter.GenerateIntro())
+	checkGolden(t, "fixture.md", doc)
+}
+
+func TestGoldenOpenAPI(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	formatter := OpenAPIFormatter{Info: OpenAPIInfo{Description: "Fixture command tree."}, KuboVersion: "0.0.0-fixture"}
+	spec, err := formatter.Generate(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "fixture.yaml", spec)
+}
@@ -0,0 +1,510 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// GoServerFormatter generates the server side of the endpoints in Go, as
+// the source of a single file: a Handler interface with a method per
+// endpoint, taking the decoded request, and NewServer, which serves a
+// Handler as the RPC API. It is meant for test doubles, proxies and
+// compatibility shims in front of Kubo. Unlike the mock server, the
+// requests and responses are typed.
+type GoServerFormatter struct {
+	// Package is the name of the generated package. Defaults to "rpc".
+	Package string
+	// TypeMap overrides the types of placeholders (see LoadTypeMap).
+	TypeMap TypeMap
+}
+
+// goOptionParsers are the functions of the generated code parsing the
+// option fields, by Go type (see goOptionTypes).
+var goOptionParsers = map[string]string{
+	"*string":  "parseString",
+	"*bool":    "parseBool",
+	"*int":     "parseInt",
+	"*uint":    "parseUint",
+	"*int64":   "parseInt64",
+	"*uint64":  "parseUint64",
+	"*float64": "parseFloat64",
+}
+
+// Generate returns the source of the server.
+func (gf *GoServerFormatter) Generate(ctx context.Context, api []*Endpoint) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	pkg := gf.Package
+	if pkg == "" {
+		pkg = "rpc"
+	}
+
+	var types, iface, unimplemented, glue, routes bytes.Buffer
+	methods := make(map[string]string)
+	for _, endp := range api {
+		method := pascalName(endp.Name)
+		if other, ok := methods[method]; ok {
+			return "", fmt.Errorf("%s and %s both map to method %s", other, endp.Name, method)
+		}
+		methods[method] = endp.Name
+		s := &goServerEndpoint{endp: endp, method: method, types: gf.TypeMap}
+		if err := s.genTypes(&types); err != nil {
+			return "", &EndpointError{Endpoint: endp.Name, Err: err}
+		}
+		s.genMethod(&iface, &unimplemented)
+		s.genGlue(&glue)
+		fmt.Fprintf(&routes, "mux.HandleFunc(%q, s.serve%s)\n", endp.Name, method)
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, goServerHeader, IPFSVersion(), pkg)
+	fmt.Fprintf(buf, "\n// Handler implements the endpoints of the RPC API.\ntype Handler interface {\n%s}\n", iface.String())
+	fmt.Fprintf(buf, "\n// UnimplementedHandler returns ErrNotImplemented from all the endpoints.\n"+
+		"// Embed it to implement only some of them.\ntype UnimplementedHandler struct{}\n%s", unimplemented.String())
+	buf.Write(types.Bytes())
+	fmt.Fprintf(buf, "\n// NewServer returns an http.Handler serving h as the RPC API.\nfunc NewServer(h Handler) http.Handler {\n"+
+		"s := &server{h: h}\nmux := http.NewServeMux()\n%sreturn postOnly(mux)\n}\n", routes.String())
+	buf.Write(glue.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("formatting the server: %w", err)
+	}
+	return string(src), nil
+}
+
+// goServerEndpoint generates the code of an endpoint.
+type goServerEndpoint struct {
+	endp   *Endpoint
+	method string
+	// response is the name of the response type, empty for endpoints
+	// returning text.
+	response string
+	// fields are the fields of the request, by argument or option name.
+	fields map[string]string
+	files  bool
+	// types overrides the types of placeholders.
+	types TypeMap
+}
+
+// genTypes writes the request and response types.
+func (s *goServerEndpoint) genTypes(buf *bytes.Buffer) error {
+	s.fields = make(map[string]string)
+	used := map[string]bool{"Files": true}
+	field := func(name string) string {
+		f := goIdent(name)
+		for used[f] {
+			f += "_"
+		}
+		used[f] = true
+		s.fields[name] = f
+		return f
+	}
+
+	request := s.method + "Request"
+	fmt.Fprintf(buf, "\n// %s is the request of %s.\ntype %s struct {\n", request, s.method, request)
+	for _, arg := range s.endp.Arguments {
+		if arg.Type == "file" {
+			s.files = true
+			continue
+		}
+		if desc := goComment(arg.Description); desc != "" {
+			fmt.Fprintf(buf, "// %s\n", desc)
+		}
+		typ := "string"
+		if arg.Variadic {
+			typ = "[]string"
+		}
+		fmt.Fprintf(buf, "%s %s\n", field(arg.Name), typ)
+	}
+	for _, opt := range s.endp.Options {
+		typ, ok := goOptionTypes[opt.Type]
+		if !ok {
+			return fmt.Errorf("unsupported type %s for option %s", opt.Type, opt.Name)
+		}
+		if desc := goComment(opt.Description); desc != "" {
+			fmt.Fprintf(buf, "// %s\n", desc)
+		}
+		fmt.Fprintf(buf, "%s %s\n", field(opt.Name), typ)
+	}
+	if s.files {
+		fmt.Fprintf(buf, "// Files are the files of the multipart body.\nFiles []File\n")
+	}
+	fmt.Fprintf(buf, "}\n")
+
+	if s.endp.Response == "" || s.endp.Response == textResponse {
+		return nil
+	}
+	var doc any
+	if err := json.Unmarshal([]byte(s.endp.Response), &doc); err != nil {
+		return fmt.Errorf("parsing the response: %w", err)
+	}
+	s.response = s.method + "Response"
+	fmt.Fprintf(buf, "\n// %s is the response of %s.\ntype %s %s\n", s.response, s.method, s.response, goType(s.types, doc))
+	return nil
+}
+
+// genMethod writes the method of the Handler interface and the one of
+// UnimplementedHandler.
+func (s *goServerEndpoint) genMethod(iface, unimplemented *bytes.Buffer) {
+	params := fmt.Sprintf("ctx context.Context, req *%sRequest", s.method)
+	returns, zero := "(io.ReadCloser, error)", "nil, ErrNotImplemented"
+	switch {
+	case s.response != "" && s.endp.Streaming:
+		params += fmt.Sprintf(", emit func(*%s) error", s.response)
+		returns, zero = "error", "ErrNotImplemented"
+	case s.response != "":
+		returns = fmt.Sprintf("(*%s, error)", s.response)
+	}
+
+	fmt.Fprintf(iface, "// %s handles %s", s.method, s.endp.Name)
+	if desc := goComment(s.endp.Description); desc != "" {
+		fmt.Fprintf(iface, ": %s", strings.ToLower(desc[:1])+desc[1:])
+	}
+	if s.response != "" && s.endp.Streaming {
+		fmt.Fprintf(iface, "\n// The values emitted are streamed to the client.")
+	}
+	fmt.Fprintf(iface, "\n%s(%s) %s\n", s.method, params, returns)
+	fmt.Fprintf(unimplemented, "\nfunc (UnimplementedHandler) %s(%s) %s {\nreturn %s\n}\n", s.method, params, returns, zero)
+}
+
+// genGlue writes the decoding of the request and the serving of the
+// response.
+func (s *goServerEndpoint) genGlue(buf *bytes.Buffer) {
+	request := s.method + "Request"
+	fmt.Fprintf(buf, "\nfunc decode%s(r *http.Request) (*%s, error) {\nq := r.URL.Query()\nreq := new(%s)\n", request, request, request)
+
+	var specs []string
+	var assign []string
+	for _, arg := range s.endp.Arguments {
+		if arg.Type == "file" {
+			continue
+		}
+		specs = append(specs, fmt.Sprintf("{%q, %t, %t}", arg.Name, arg.Required, arg.Variadic))
+		value := fmt.Sprintf("args[%d]", len(assign))
+		if !arg.Variadic {
+			value = fmt.Sprintf("firstArg(%s)", value)
+		}
+		assign = append(assign, fmt.Sprintf("req.%s = %s", s.fields[arg.Name], value))
+	}
+	fmt.Fprintf(buf, "args, err := assignArgs(q[\"arg\"], []argSpec{%s})\nif err != nil {\nreturn nil, err\n}\n", strings.Join(specs, ", "))
+	if len(assign) == 0 {
+		fmt.Fprintf(buf, "_ = args\n")
+	}
+	for _, a := range assign {
+		fmt.Fprintln(buf, a)
+	}
+
+	for _, opt := range s.endp.Options {
+		names := fmt.Sprintf("%q", opt.Name)
+		for _, alias := range opt.Aliases {
+			names += fmt.Sprintf(", %q", alias)
+		}
+		typ := goOptionTypes[opt.Type]
+		if typ == "[]string" {
+			fmt.Fprintf(buf, "req.%s = queryValues(q, %s)\n", s.fields[opt.Name], names)
+			continue
+		}
+		fmt.Fprintf(buf, "if req.%s, err = parseOption(q, %s, %s); err != nil {\nreturn nil, err\n}\n", s.fields[opt.Name], goOptionParsers[typ], names)
+	}
+	if s.files {
+		fmt.Fprintf(buf, "if req.Files, err = readFiles(r); err != nil {\nreturn nil, err\n}\n")
+	}
+	fmt.Fprintf(buf, "return req, nil\n}\n")
+
+	fmt.Fprintf(buf, "\nfunc (s *server) serve%s(w http.ResponseWriter, r *http.Request) {\nreq, err := decode%s(r)\nif err != nil {\n"+
+		"writeError(w, &Error{Message: err.Error(), Code: ErrClient, Type: \"error\"})\nreturn\n}\n", s.method, request)
+	switch {
+	case s.response != "" && s.endp.Streaming:
+		fmt.Fprintf(buf, "serveStream(w, func(emit func(*%s) error) error {\nreturn s.h.%s(r.Context(), req, emit)\n})\n", s.response, s.method)
+	case s.response != "":
+		fmt.Fprintf(buf, "serveJSON(w, func() (*%s, error) {\nreturn s.h.%s(r.Context(), req)\n})\n", s.response, s.method)
+	default:
+		fmt.Fprintf(buf, "serveText(w, func() (io.ReadCloser, error) {\nreturn s.h.%s(r.Context(), req)\n})\n", s.method)
+	}
+	fmt.Fprintf(buf, "}\n")
+}
+
+// goServerHeader is the start of the server, with the helpers used by the
+// endpoints.
+const goServerHeader = `// Code generated by http-api-docs from kubo v%s. DO NOT EDIT.
+
+// Package %s serves the Kubo RPC API from a Handler.
+package %[2]s
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Some helpers are only used by endpoints with options or files.
+var (
+	_ = bytes.NewReader
+	_ = mime.ParseMediaType
+	_ = strconv.Itoa
+	_ = strings.ToLower
+)
+
+// File is a file of the multipart body of endpoints taking files, read
+// into memory. Name is its path, e.g. "dir/file.txt". Directories have the
+// application/x-directory ContentType.
+type File struct {
+	Name        string
+	ContentType string
+	Reader      io.Reader
+}
+
+// Error is an error returned by the RPC API. Handlers may return one to
+// choose the Code: ErrClient errors are sent with the 400 status, the other
+// ones with 500.
+type Error struct {
+	Message string
+	Code    int
+	Type    string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// The codes of Error.
+const (
+	ErrNormal = 0
+	ErrClient = 1
+)
+
+// ErrNotImplemented is returned by the endpoints of UnimplementedHandler,
+// and sent with the 501 status.
+var ErrNotImplemented = errors.New("not implemented")
+
+type server struct {
+	h Handler
+}
+
+// postOnly rejects the requests which are not POST, like the daemon.
+func postOnly(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "405 - Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	apiErr := new(Error)
+	switch {
+	case errors.Is(err, ErrNotImplemented):
+		status = http.StatusNotImplemented
+		apiErr = &Error{Message: err.Error(), Type: "error"}
+	case errors.As(err, &apiErr):
+		if apiErr.Code == ErrClient {
+			status = http.StatusBadRequest
+		}
+		if apiErr.Type == "" {
+			e := *apiErr
+			e.Type = "error"
+			apiErr = &e
+		}
+	default:
+		apiErr = &Error{Message: err.Error(), Type: "error"}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiErr)
+}
+
+func serveJSON[T any](w http.ResponseWriter, call func() (*T, error)) {
+	v, err := call()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// serveStream writes the emitted values as newline-delimited JSON. Errors
+// happening after the first value are sent in the X-Stream-Error trailer.
+func serveStream[T any](w http.ResponseWriter, call func(emit func(*T) error) error) {
+	started := false
+	enc := json.NewEncoder(w)
+	err := call(func(v *T) error {
+		if !started {
+			started = true
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Chunked-Output", "1")
+			w.Header().Set("Trailer", "X-Stream-Error")
+		}
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		return nil
+	})
+	switch {
+	case err != nil && !started:
+		writeError(w, err)
+	case err != nil:
+		w.Header().Set("X-Stream-Error", err.Error())
+	}
+}
+
+func serveText(w http.ResponseWriter, call func() (io.ReadCloser, error)) {
+	body, err := call()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer body.Close()
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("X-Stream-Output", "1")
+	io.Copy(w, body)
+}
+
+// argSpec describes a positional argument of an endpoint.
+type argSpec struct {
+	name               string
+	required, variadic bool
+}
+
+// assignArgs splits the values of the arg parameters among the positional
+// arguments, like the daemon: optional arguments only get a value when
+// there are enough values for the required ones after them.
+func assignArgs(values []string, specs []argSpec) ([][]string, error) {
+	required := 0
+	for _, spec := range specs {
+		if spec.required {
+			required++
+		}
+	}
+	args := make([][]string, len(specs))
+	for i, spec := range specs {
+		if spec.required {
+			required--
+		} else if len(values) <= required {
+			continue
+		}
+		switch {
+		case len(values) == 0 && spec.required:
+			return nil, fmt.Errorf("argument %%q is required", spec.name)
+		case len(values) == 0:
+		case spec.variadic:
+			args[i], values = values, nil
+		default:
+			args[i], values = values[:1], values[1:]
+		}
+	}
+	if len(values) > 0 {
+		return nil, fmt.Errorf("expected %%d arguments, got %%d", len(specs), len(specs)+len(values))
+	}
+	return args, nil
+}
+
+func firstArg(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// parseOption parses the option given under one of its names, if any.
+func parseOption[T any](q url.Values, parse func(string) (T, error), names ...string) (*T, error) {
+	for _, name := range names {
+		values, ok := q[name]
+		if !ok {
+			continue
+		}
+		if len(values) > 1 {
+			return nil, fmt.Errorf("expected option %%s to have only a single value, received %%v", names[0], values)
+		}
+		v, err := parse(values[0])
+		if err != nil {
+			return nil, fmt.Errorf("option %%s: %%w", names[0], err)
+		}
+		return &v, nil
+	}
+	return nil, nil
+}
+
+// queryValues returns the values of an array option, given under any of its
+// names.
+func queryValues(q url.Values, names ...string) []string {
+	var values []string
+	for _, name := range names {
+		values = append(values, q[name]...)
+	}
+	return values
+}
+
+func parseString(v string) (string, error) { return v, nil }
+
+// parseBool accepts flags given without value, like the daemon.
+func parseBool(v string) (bool, error) {
+	if v == "" {
+		return true, nil
+	}
+	return strconv.ParseBool(strings.ToLower(v))
+}
+
+func parseInt(v string) (int, error) {
+	i, err := strconv.ParseInt(v, 0, 32)
+	return int(i), err
+}
+
+func parseUint(v string) (uint, error) {
+	i, err := strconv.ParseUint(v, 0, 32)
+	return uint(i), err
+}
+
+func parseInt64(v string) (int64, error)     { return strconv.ParseInt(v, 0, 64) }
+func parseUint64(v string) (uint64, error)   { return strconv.ParseUint(v, 0, 64) }
+func parseFloat64(v string) (float64, error) { return strconv.ParseFloat(v, 64) }
+
+// readFiles reads the files of the multipart body.
+func readFiles(r *http.Request) ([]File, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	var files []File
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		// part.FileName would strip the directories.
+		_, params, _ := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+		name, err := url.PathUnescape(params["filename"])
+		if err != nil {
+			name = params["filename"]
+		}
+		files = append(files, File{Name: name, ContentType: part.Header.Get("Content-Type"), Reader: bytes.NewReader(data)})
+	}
+}
+`
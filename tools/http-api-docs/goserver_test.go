@@ -0,0 +1,38 @@
+package docs
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGoServerFixture(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	src, err := (&GoServerFormatter{Package: "fixture"}).Generate(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := typeCheck(t, src)
+	if pkg.Name() != "fixture" {
+		t.Errorf("unexpected package %s", pkg.Name())
+	}
+	for _, want := range []string{
+		"FixtureV0Json(ctx context.Context, req *FixtureV0JsonRequest) (*FixtureV0JsonResponse, error)",
+		"FixtureV0Stream(ctx context.Context, req *FixtureV0StreamRequest, emit func(*FixtureV0StreamResponse) error) error",
+		"FixtureV0Multi(ctx context.Context, req *FixtureV0MultiRequest) (io.ReadCloser, error)",
+		"if req.Files, err = readFiles(r); err != nil {",
+		"mux.HandleFunc(\"/fixture/v0/json\", s.serveFixtureV0Json)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("server does not contain %q", want)
+		}
+	}
+}
+
+func TestGoServerKubo(t *testing.T) {
+	src, err := new(GoServerFormatter).Generate(context.Background(), AllEndpoints())
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeCheck(t, src)
+}
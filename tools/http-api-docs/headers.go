@@ -0,0 +1,124 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// streamHeaders are the headers of streamed responses, registered in
+// components/headers. go-ipfs-cmds sets X-Chunked-Output on responses
+// emitting a stream of values and X-Stream-Output on those copying a reader.
+// Errors happening once the response started are sent in the X-Stream-Error
+// trailer.
+var streamHeaders = []struct {
+	name        string
+	description string
+	chunked     bool // only set on streams of values
+	reader      bool // only set on streams copied from a reader
+}{
+	{"Trailer", "Always `X-Stream-Error`: errors happening after the response started are reported in this trailer.", false, false},
+	{"X-Stream-Error", "Trailer. Set to the error message when the command failed after the response started. The status code is 200 anyway, so clients must check it after reading the body.", false, false},
+	{"Transfer-Encoding", "`chunked`: the response is written while the command runs.", false, false},
+	{"X-Chunked-Output", "`1`: the body is a stream of values, sent as they are emitted.", true, false},
+	{"X-Stream-Output", "`1`: the body is a stream of bytes.", false, true},
+}
+
+// endpointHeaders are the headers set by some endpoints only (see
+// responseHeaders), registered in components/headers.
+var endpointHeaders = []struct {
+	name        string
+	description string
+	schema      func() *openapi3.Schema
+}{
+	{"X-Content-Length", "Size of the body in bytes, known before it is streamed. The response is chunked, so it has no Content-Length.",
+		func() *openapi3.Schema { return integerSchema().WithFormat("int64") }},
+}
+
+// genHeaderComponents returns the headers of the successful responses
+// referenced by the operations.
+func genHeaderComponents() openapi3.ComponentsHeaders {
+	str := openapi3.SchemaTypeString
+	headers := openapi3.ComponentsHeaders{}
+	for _, h := range streamHeaders {
+		description := h.description
+		headers.WithMapOfHeaderOrRefValuesItem(h.name, openapi3.HeaderOrRef{Header: &openapi3.Header{
+			Description: &description,
+			Schema:      &openapi3.SchemaOrRef{Schema: &openapi3.Schema{Type: &str}},
+		}})
+	}
+	for _, h := range endpointHeaders {
+		description := h.description
+		headers.WithMapOfHeaderOrRefValuesItem(h.name, openapi3.HeaderOrRef{Header: &openapi3.Header{
+			Description: &description,
+			Schema:      &openapi3.SchemaOrRef{Schema: h.schema()},
+		}})
+	}
+	return headers
+}
+
+// addResponseHeaders documents the headers of the successful response of an
+// endpoint: the headers of streams and the ones listed in responseHeaders.
+// OpenAPI ignores a Content-Type header, so its value is told in the
+// description of the response.
+func addResponseHeaders(resp *openapi3.Response, endp *Endpoint) {
+	resp.Description += ". Content-Type: " + contentTypeDescription(endp)
+	addStreamHeaders(resp, endp)
+	for _, name := range responseHeaders[endp.Name] {
+		if resp.Headers == nil {
+			resp.Headers = make(map[string]openapi3.HeaderOrRef)
+		}
+		resp.Headers[name] = openapi3.HeaderOrRef{HeaderReference: &openapi3.HeaderReference{
+			Ref: "#/components/headers/" + name,
+		}}
+	}
+}
+
+// contentTypeDescription tells which Content-Type go-ipfs-cmds sets on the
+// successful response of an endpoint: text/plain when copying a reader,
+// whatever its format, or the media type of the encoding.
+func contentTypeDescription(endp *Endpoint) string {
+	if endp.Response == textResponse && !endp.Streaming {
+		if mime, ok := binaryResponses[endp.Name]; ok {
+			return fmt.Sprintf("Always `text/plain`, although the body is in the %s format.", mime)
+		}
+		return "Always `text/plain`, whatever the format of the body."
+	}
+	description := "`application/json`"
+	var others []string
+	for _, enc := range endp.Encodings {
+		if mime := encodingMIMEType(enc); mime != "application/json" {
+			others = append(others, fmt.Sprintf("`%s` with the `%s` encoding", mime, enc))
+		}
+	}
+	if len(others) > 0 {
+		description += ", or " + strings.Join(others, ", ")
+	}
+	description += "."
+	if endp.Streaming {
+		description += " Streams of values keep it, although the body is newline-delimited JSON."
+	}
+	return description
+}
+
+// addStreamHeaders documents the headers of the successful response of
+// streaming endpoints and endpoints returning text, which are streamed from
+// a reader.
+func addStreamHeaders(resp *openapi3.Response, endp *Endpoint) {
+	reader := endp.Response == textResponse && !endp.Streaming
+	if !endp.Streaming && !reader {
+		return
+	}
+	for _, h := range streamHeaders {
+		if (h.chunked && reader) || (h.reader && !reader) {
+			continue
+		}
+		if resp.Headers == nil {
+			resp.Headers = make(map[string]openapi3.HeaderOrRef)
+		}
+		resp.Headers[h.name] = openapi3.HeaderOrRef{HeaderReference: &openapi3.HeaderReference{
+			Ref: "#/components/headers/" + h.name,
+		}}
+	}
+}
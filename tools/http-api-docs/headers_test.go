@@ -0,0 +1,63 @@
+package docs
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+func TestAddStreamHeaders(t *testing.T) {
+	headers := func(endp *Endpoint) string {
+		resp := &openapi3.Response{}
+		addStreamHeaders(resp, endp)
+		var names []string
+		for name, h := range resp.Headers {
+			if h.HeaderReference.Ref != "#/components/headers/"+name {
+				t.Errorf("unexpected reference %s", h.HeaderReference.Ref)
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return strings.Join(names, " ")
+	}
+
+	if got := headers(&Endpoint{Response: `{"Ref": "<string>"}`, Streaming: true}); got != "Trailer Transfer-Encoding X-Chunked-Output X-Stream-Error" {
+		t.Errorf("unexpected headers for a stream of values: %s", got)
+	}
+	if got := headers(&Endpoint{Response: textResponse}); got != "Trailer Transfer-Encoding X-Stream-Error X-Stream-Output" {
+		t.Errorf("unexpected headers for a text stream: %s", got)
+	}
+	if got := headers(&Endpoint{Response: `{"ID": "<string>"}`}); got != "" {
+		t.Errorf("single values have no stream headers, got %s", got)
+	}
+}
+
+func TestAddResponseHeaders(t *testing.T) {
+	resp := &openapi3.Response{Description: "Successful response"}
+	addResponseHeaders(resp, &Endpoint{Name: "/api/v0/cat", Response: textResponse})
+	if h, ok := resp.Headers["X-Content-Length"]; !ok || h.HeaderReference.Ref != "#/components/headers/X-Content-Length" {
+		t.Errorf("cat should document X-Content-Length, got %v", resp.Headers)
+	}
+	// OpenAPI ignores Content-Type headers.
+	if _, ok := resp.Headers["Content-Type"]; ok || !strings.Contains(resp.Description, "Content-Type: Always `text/plain`") {
+		t.Errorf("unexpected Content-Type of cat: %q, %v", resp.Description, resp.Headers)
+	}
+
+	resp = &openapi3.Response{Description: "Successful response"}
+	addResponseHeaders(resp, &Endpoint{Name: "/api/v0/id", Response: `{"ID": "<string>"}`, Encodings: []string{"json", "text"}})
+	if _, ok := resp.Headers["X-Content-Length"]; ok {
+		t.Errorf("id should not document X-Content-Length")
+	}
+	if got := resp.Description; got != "Successful response. Content-Type: `application/json`, or `text/plain` with the `text` encoding." {
+		t.Errorf("unexpected description of id: %s", got)
+	}
+}
+
+func TestHeaderComponents(t *testing.T) {
+	h := genHeaderComponents().MapOfHeaderOrRefValues["X-Content-Length"].Header
+	if s := h.Schema.Schema; *s.Type != openapi3.SchemaTypeInteger || s.Format == nil || *s.Format != "int64" {
+		t.Errorf("X-Content-Length should be an int64, got %+v", s)
+	}
+}
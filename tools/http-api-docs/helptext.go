@@ -0,0 +1,179 @@
+package docs
+
+import (
+	"strings"
+	"unicode"
+)
+
+// helptextHeading is the level of the headings of the sections of the
+// helptext (e.g. "EXAMPLES:"), below the headings of the Markdown endpoint
+// blocks.
+const helptextHeading = "####"
+
+// helptextMarkdown converts the helptext of a command to CommonMark, for
+// the descriptions of the Markdown and OpenAPI formatters. Helptext is
+// plain text for a terminal, where indentation marks code and quotes, which
+// CommonMark would run into the surrounding paragraph:
+//
+//   - indented blocks of commands and output, and runs of "$ " commands
+//     and "# " comments, indented or not, become code blocks,
+//   - indented prose (e.g. under "Note:") becomes a paragraph,
+//   - lines like "EXAMPLES:" or "Example:" become headings,
+//   - "<" is escaped, so that placeholders like <path> aren't taken for
+//     HTML.
+func helptextMarkdown(text string) string {
+	var blocks []string
+	for _, chunk := range helptextChunks(text) {
+		for _, run := range indentationRuns(chunk) {
+			switch {
+			case run.indented && isHelptextCode(run.lines):
+				blocks = append(blocks, "```\n"+strings.Join(dedent(run.lines), "\n")+"\n```")
+			case run.indented:
+				blocks = append(blocks, helptextParagraph(dedent(run.lines)))
+			case len(run.lines) == 1 && isHelptextHeading(run.lines[0]):
+				heading := strings.TrimSuffix(strings.TrimSpace(run.lines[0]), ":")
+				blocks = append(blocks, helptextHeading+" "+heading)
+			default:
+				for _, part := range commandRuns(run.lines) {
+					if part.indented {
+						blocks = append(blocks, "```\n"+strings.Join(part.lines, "\n")+"\n```")
+					} else {
+						blocks = append(blocks, helptextParagraph(part.lines))
+					}
+				}
+			}
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// commandRuns splits lines which are not indented into runs of shell
+// commands and comments, like "$ ipfs pin add <CID>", and of prose. The
+// runs of commands are marked as indented, to be rendered as code.
+func commandRuns(lines []string) []indentationRun {
+	var runs []indentationRun
+	for _, line := range lines {
+		command := isCommandLine(line)
+		if len(runs) == 0 || runs[len(runs)-1].indented != command {
+			runs = append(runs, indentationRun{indented: command})
+		}
+		runs[len(runs)-1].lines = append(runs[len(runs)-1].lines, line)
+	}
+	return runs
+}
+
+// isCommandLine tells whether a line is a shell command ("$ ...") or
+// comment ("# ...") of an example.
+func isCommandLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "$ ") || strings.HasPrefix(line, "# ") || line == "#"
+}
+
+// helptextChunks splits the helptext into chunks separated by blank lines,
+// with tabs expanded.
+func helptextChunks(text string) [][]string {
+	var chunks [][]string
+	var chunk []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(strings.ReplaceAll(line, "\t", "    "), " \r")
+		if line == "" {
+			if len(chunk) > 0 {
+				chunks = append(chunks, chunk)
+			}
+			chunk = nil
+			continue
+		}
+		chunk = append(chunk, line)
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+type indentationRun struct {
+	indented bool
+	lines    []string
+}
+
+// indentationRuns splits a chunk into runs of indented and not indented
+// lines, e.g. a heading and the indented block under it.
+func indentationRuns(chunk []string) []indentationRun {
+	var runs []indentationRun
+	for _, line := range chunk {
+		indented := line[0] == ' '
+		if len(runs) == 0 || runs[len(runs)-1].indented != indented {
+			runs = append(runs, indentationRun{indented: indented})
+		}
+		runs[len(runs)-1].lines = append(runs[len(runs)-1].lines, line)
+	}
+	return runs
+}
+
+// isHelptextCode tells whether an indented block is code (commands, their
+// output or formats) rather than prose.
+func isHelptextCode(lines []string) bool {
+	if indentation(lines[0]) >= 4 {
+		return true
+	}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		for _, prefix := range []string{"$", "#", "<", "/", "ipfs "} {
+			if strings.HasPrefix(line, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isHelptextHeading tells whether a line on its own is the title of a
+// section: all in capitals, or a word ending with a colon.
+func isHelptextHeading(line string) bool {
+	line = strings.TrimSpace(line)
+	title := strings.TrimSuffix(line, ":")
+	if title == "" {
+		return false
+	}
+	if strings.HasSuffix(line, ":") && len(strings.Fields(title)) == 1 {
+		return true
+	}
+	letters := 0
+	for _, r := range title {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters >= 2
+}
+
+// helptextParagraph joins lines into a paragraph, escaping "<".
+func helptextParagraph(lines []string) string {
+	escaped := make([]string, len(lines))
+	for i, line := range lines {
+		escaped[i] = strings.ReplaceAll(line, "<", `\<`)
+	}
+	return strings.Join(escaped, "\n")
+}
+
+// dedent removes the indentation shared by the lines.
+func dedent(lines []string) []string {
+	shared := -1
+	for _, line := range lines {
+		if n := indentation(line); shared < 0 || n < shared {
+			shared = n
+		}
+	}
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = line[shared:]
+	}
+	return out
+}
+
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
@@ -0,0 +1,67 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHelptextMarkdown(t *testing.T) {
+	for _, tc := range []struct {
+		name, text, want string
+	}{
+		{
+			name: "paragraphs",
+			text: "Prints out information about <peer>.\nSecond line.\n\nAnother paragraph.",
+			want: "Prints out information about \\<peer>.\nSecond line.\n\nAnother paragraph.",
+		},
+		{
+			name: "tab-indented block",
+			text: "It outputs:\n\n\tKey  - the CID\n\tSize - the size",
+			want: "It outputs:\n\n```\nKey  - the CID\nSize - the size\n```",
+		},
+		{
+			name: "example under a heading",
+			text: "EXAMPLE:\n\n    ipfs id -f=\"<addrs>\\n\"\n\nExample:\n  ipfs p2p forward /x/a /ip4/127.0.0.1/tcp/1 /p2p/QmPeer\n    - Forward connections",
+			want: "#### EXAMPLE\n\n```\nipfs id -f=\"<addrs>\\n\"\n```\n\n#### Example\n\n```\nipfs p2p forward /x/a /ip4/127.0.0.1/tcp/1 /p2p/QmPeer\n  - Forward connections\n```",
+		},
+		{
+			name: "indented prose",
+			text: "TOPIC ENCODING\n\n  Topic names are binary data too.\n  They are encoded.",
+			want: "#### TOPIC ENCODING\n\nTopic names are binary data too.\nThey are encoded.",
+		},
+		{
+			name: "list",
+			text: "Use one of:\n- a\n- b",
+			want: "Use one of:\n- a\n- b",
+		},
+	} {
+		if got := helptextMarkdown(tc.text); got != tc.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestHelptextMarkdownCommands(t *testing.T) {
+	var help string
+	for _, endp := range AllEndpoints() {
+		if endp.Name == "/api/v0/files/cp" {
+			help = endp.LongDescription
+		}
+	}
+	got := helptextMarkdown(help)
+	for _, want := range []string{
+		"IPFS Content Identifier and then \"ipfs files cp\" to copy it into MFS:\n\n" +
+			"```\n$ ipfs add --quieter --pin=false <your file>\n# ...\n# ... outputs the root CID at the end\n$ ipfs files cp /ipfs/<CID> /your/desired/mfs/path\n```\n\n",
+		"```\n$ ipfs files cp /ipfs/<CID> /your/desired/mfs/path\n$ ipfs pin add <CID>\n```",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("the commands of files/cp should be code blocks, got\n%s", got)
+		}
+	}
+
+	text := "Run:\n$ ipfs files ls /\n$ ipfs files rm /a\nThen check."
+	want := "Run:\n\n```\n$ ipfs files ls /\n$ ipfs files rm /a\n```\n\nThen check."
+	if got := helptextMarkdown(text); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
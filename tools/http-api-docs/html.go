@@ -0,0 +1,144 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HTMLFormatter generates a static site presenting an OpenAPI spec with
+// Redoc or Swagger UI. The spec is embedded in the page, which only loads
+// the UI itself from a CDN, so the site can be published as is.
+type HTMLFormatter struct {
+	// Title is the title of the page. Defaults to "Kubo RPC API".
+	Title string
+	// UI is the renderer: "redoc" (the default) or "swagger-ui".
+	UI string
+}
+
+const (
+	redocScript     = "https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"
+	swaggerUIAssets = "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.17.14/"
+)
+
+var htmlTemplates = map[string]*template.Template{
+	"redoc": template.Must(template.New("redoc").Parse(`<!DOCTYPE html>
+<html>
+  <head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>{{.Title}}</title>
+  </head>
+  <body>
+    <div id="redoc"></div>
+    <script src="` + redocScript + `"></script>
+    <script>
+      Redoc.init({{.Spec}}, {}, document.getElementById("redoc"));
+    </script>
+  </body>
+</html>
+`)),
+	"swagger-ui": template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html>
+  <head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>{{.Title}}</title>
+    <link rel="stylesheet" href="` + swaggerUIAssets + `swagger-ui.css">
+  </head>
+  <body>
+    <div id="swagger-ui"></div>
+    <script src="` + swaggerUIAssets + `swagger-ui-bundle.js"></script>
+    <script>
+      SwaggerUIBundle({spec: {{.Spec}}, dom_id: "#swagger-ui"});
+    </script>
+  </body>
+</html>
+`)),
+}
+
+// GenerateHTML returns a page rendering the given spec (YAML or JSON).
+func (hf *HTMLFormatter) GenerateHTML(spec string) (string, error) {
+	ui := hf.UI
+	if ui == "" {
+		ui = "redoc"
+	}
+	tmpl, ok := htmlTemplates[ui]
+	if !ok {
+		return "", fmt.Errorf("unknown UI %q, expected redoc or swagger-ui", ui)
+	}
+	title := hf.Title
+	if title == "" {
+		title = "Kubo RPC API"
+	}
+
+	var doc interface{}
+	if err := yaml.Unmarshal([]byte(spec), &doc); err != nil {
+		return "", fmt.Errorf("parsing spec: %w", err)
+	}
+	// json.Marshal escapes <, > and &, so the spec can't close the
+	// script element.
+	specJSON, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+
+	buf := new(bytes.Buffer)
+	err = tmpl.Execute(buf, struct {
+		Title string
+		Spec  template.JS
+	}{title, template.JS(specJSON)})
+	return buf.String(), err
+}
+
+// WriteSite writes the page (index.html) and the spec (openapi.yaml) into
+// dir, creating it if needed.
+func (hf *HTMLFormatter) WriteSite(dir string, spec string) error {
+	page, err := hf.GenerateHTML(spec)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "openapi.yaml"), []byte(spec), 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "index.html"), []byte(page), 0o644)
+}
+
+// Handler serves the page at "/" and the spec at "/openapi.yaml". The spec
+// is generated again by each request, so the page always reflects the
+// current generator.
+func (hf *HTMLFormatter) Handler(generate func(ctx context.Context) (string, error)) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		spec, err := generate(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		fmt.Fprint(w, spec)
+	})
+	mux.HandleFunc("/{$}", func(w http.ResponseWriter, r *http.Request) {
+		spec, err := generate(r.Context())
+		if err == nil {
+			spec, err = hf.GenerateHTML(spec)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, spec)
+	})
+	return mux
+}
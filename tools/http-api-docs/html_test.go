@@ -0,0 +1,86 @@
+package docs
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const htmlTestSpec = `openapi: 3.0.3
+info:
+  title: Test
+  description: "</script><script>alert(1)</script>"
+`
+
+func TestGenerateHTML(t *testing.T) {
+	page, err := new(HTMLFormatter).GenerateHTML(htmlTestSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(page, "Redoc.init({") || !strings.Contains(page, "<title>Kubo RPC API</title>") {
+		t.Errorf("unexpected page:\n%s", page)
+	}
+	if strings.Contains(page, "alert(1)</script>") {
+		t.Errorf("the spec should be escaped:\n%s", page)
+	}
+
+	page, err = (&HTMLFormatter{UI: "swagger-ui", Title: "Test"}).GenerateHTML(htmlTestSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(page, `SwaggerUIBundle({spec: {"info":`) {
+		t.Errorf("unexpected page:\n%s", page)
+	}
+
+	if _, err := (&HTMLFormatter{UI: "rapidoc"}).GenerateHTML(htmlTestSpec); err == nil {
+		t.Errorf("expected an error for an unknown UI")
+	}
+}
+
+func TestWriteSite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "site")
+	if err := new(HTMLFormatter).WriteSite(dir, htmlTestSpec); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"index.html", "openapi.yaml"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestHTMLHandler(t *testing.T) {
+	calls := 0
+	generate := func(ctx context.Context) (string, error) {
+		calls++
+		return htmlTestSpec, nil
+	}
+	server := httptest.NewServer((&HTMLFormatter{UI: "swagger-ui"}).Handler(generate))
+	defer server.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := server.Client().Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+	if code, body := get("/"); code != 200 || !strings.Contains(body, "SwaggerUIBundle") {
+		t.Errorf("unexpected page (%d):\n%s", code, body)
+	}
+	if code, body := get("/openapi.yaml"); code != 200 || body != htmlTestSpec {
+		t.Errorf("unexpected spec (%d):\n%s", code, body)
+	}
+	if code, _ := get("/other"); code != 404 {
+		t.Errorf("expected 404, got %d", code)
+	}
+	if calls != 2 {
+		t.Errorf("the spec should be generated for each request, got %d calls", calls)
+	}
+}
@@ -0,0 +1,76 @@
+// This is an utility to compare the RPC API of two Kubo versions.
+//
+// Without arguments, it dumps the endpoints of the Kubo version it was built
+// with as JSON. Given two dumps, it prints the differences between them. Given
+// only one, it compares it with the endpoints of the current version.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	docs "github.com/ipfs/ipfs-docs/tools/http-api-docs"
+)
+
+var (
+	jsonOutput     = flag.Bool("json", false, "Print the differences as JSON.")
+	markdownOutput = flag.Bool("markdown", false, "Print the differences as a Markdown changelog, for release notes.")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-json|-markdown] [OLD.json [NEW.json]]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	current := &docs.EndpointsDump{KuboVersion: docs.IPFSVersion(), Endpoints: docs.AllEndpoints()}
+	var dumps []*docs.EndpointsDump
+	for _, path := range flag.Args() {
+		dumps = append(dumps, readDump(path))
+	}
+
+	switch len(dumps) {
+	case 0:
+		if err := docs.WriteEndpoints(os.Stdout, current.Endpoints); err != nil {
+			log.Fatal(err)
+		}
+		return
+	case 1:
+		dumps = append(dumps, current)
+	case 2:
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	diff := docs.DiffEndpoints(dumps[0], dumps[1])
+	switch {
+	case *jsonOutput:
+		out, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(out))
+	case *markdownOutput:
+		fmt.Print(diff.Markdown())
+	default:
+		fmt.Print(diff)
+	}
+}
+
+func readDump(path string) *docs.EndpointsDump {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	dump, err := docs.ReadEndpoints(f)
+	if err != nil {
+		log.Fatalf("%s: %s", path, err)
+	}
+	return dump
+}
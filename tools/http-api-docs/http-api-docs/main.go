@@ -1,14 +1,433 @@
-// This is an utility to generate documentation from go-ipfs commands
+// This is an utility to generate documentation from go-ipfs commands.
+//
+// The -formatter flag selects what is generated: the Markdown reference (the
+// default), the OpenAPI spec, a Postman collection, TypeScript definitions...
+// Several formatters can be given, separated by commas, to extract the
+// command tree only once; their outputs are then written into -out-dir. The
+// options only used by some formatters start their help with their names.
+//
+// Given endpoint dumps of http-api-diff, it writes the OpenAPI spec of each
+// Kubo version into -out-dir instead. -record, -validate-against, -serve and
+// -dry-run work with the schemas of the OpenAPI spec instead of generating.
+//
+// "http-api-docs coverage" measures the documentation coverage instead, and
+// fails when it is below -threshold, and "http-api-docs consistency" checks
+// that the Markdown reference and the OpenAPI spec agree.
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 
-	docs "http-api-docs"
+	docs "github.com/ipfs/ipfs-docs/tools/http-api-docs"
 )
 
+var (
+	formatterNames = flag.String("formatter", "markdown", "Comma-separated list of what to generate: "+strings.Join(formatterList(), ", ")+", or exec:COMMAND to run an external formatter, which reads the endpoints (see -snapshot) on stdin and writes the output on stdout.")
+	include        = flag.String("include", "", "Comma-separated list of the statuses of the endpoints to document. Defaults to all of them, except removed ones for goclient, goserver and typescript.")
+	report         = flag.String("report", "", "Also write a JSON report of what is missing from the docs (unparsable responses, unsupported argument types, responses without schema, options without description) to this file.")
+	typeMap        = flag.String("type-map", "", "YAML file of placeholder types (e.g. \"<peer-id>\") merged over the built-in ones, to fix a mapping or add a new placeholder without recompiling. See LoadTypeMap.")
+	snapshot       = flag.String("snapshot", "", "Also write the endpoints extracted from the Kubo commands into this snapshot file, for -from-snapshot. Snapshots are endpoint dumps, in the versioned representation described by schemas/endpoints-v1.schema.json.")
+	fromSnapshot   = flag.String("from-snapshot", "", "Read the endpoints from this snapshot file, written by -snapshot or http-api-diff, instead of extracting them from the Kubo commands. Its Kubo version is the default of -kubo-version.")
+	config         = flag.String("config", "", "Configuration file setting the flags not given on the command line, in the http-api-docs section. Defaults to $IPFS_API_DOCS_CONFIG, or to http-api-docs.yaml if it exists. IPFS_API_DOCS_* environment variables (e.g. IPFS_API_DOCS_OUT_DIR) win over it.")
+	outDir         = flag.String("out-dir", "", "Write the outputs into this directory, named after the formatter (postman.json, kubo-rpc.d.ts...), instead of stdout. The markdown formatter writes one page per command namespace (and an index.md), and the specs of endpoint dumps are named after their minor version (openapi-v0.24.yaml...).")
+	timeout        = flag.Duration("timeout", 0, "Abort when generating or validating takes longer than this, e.g. 1m. 0 means no timeout.")
+
+	toc         = flag.Bool("toc", false, "markdown: Add a linked table of contents after the intro.")
+	anchors     = flag.String("anchors", docs.AnchorsVuePress, "markdown: Anchors of the headings of the endpoints: \"vuepress\" slugs (api-v0-pin-add), \"github\" slugs (apiv0pinadd, also Docusaurus), or the VuePress ones set \"explicit\"ly ({#api-v0-pin-add}) or with \"html\" anchors.")
+	frontMatter = flag.String("front-matter", docs.FrontMatterVuePress, "markdown: Front matter of the pages: \"vuepress\", \"docusaurus\" (with sidebar_label) or \"none\".")
+	cliOnly     = flag.Bool("cli-only", false, "markdown: Add an appendix listing the CLI commands which are not available over the RPC API (e.g. ipfs config edit).")
+	parentHelp  = flag.String("parent-help", "", "markdown, openapi: Surface the help of the parent commands (e.g. the flushing notes of \"files\") in the description of each endpoint: \"link\" to it or \"prepend\" it.")
+	templateDir = flag.String("template-dir", "", "markdown: Directory of templates (*.tmpl) overriding the blocks of templates/markdown.md.tmpl with the same name.")
+	baseURL     = flag.String("base-url", "http://127.0.0.1:5001", "postman: Default value of the {{baseUrl}} variable of the collection.")
+	pkg         = flag.String("package", "rpc", "goclient, goserver: Name of the generated package.")
+	baseID      = flag.String("base-id", "", "json-schema: URL under which the schemas are published, used for their $id.")
+	host        = flag.String("host", "127.0.0.1:5001", "asyncapi: Host of the RPC API listed in the servers.")
+	csvMapping  = flag.Bool("csv", false, "cli-mapping: Write the table as CSV instead of JSON.")
+
+	servers stringList
+	// types are the placeholder types loaded from -type-map.
+	types docs.TypeMap
+)
+
+func init() {
+	flag.StringVar(snapshot, "dump-ir", "", "Alias of -snapshot, to write the endpoints for tools which don't embed this module.")
+	flag.Var(&servers, "server-url", "gateway, openapi, routing: URL of a server to list in the spec, e.g. http://127.0.0.1:5001. Defaults to the address of the local daemon. Can be repeated.")
+}
+
+// stringList is a flag which can be given several times.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// A formatter generates files from the endpoints. Formatters writing a
+// single file print it to stdout without -out-dir.
+type formatter struct {
+	// file is the name of the single file written by the formatter, or ""
+	// for formatters writing several files.
+	file string
+	// include is the default of -include for the formatter.
+	include  string
+	generate func(ctx context.Context, endpoints []*docs.Endpoint) (map[string][]byte, error)
+}
+
+const allStatuses = "active,experimental,deprecated,removed"
+
+var formatters = map[string]formatter{
+	"markdown": {"rpc.md", allStatuses, func(ctx context.Context, endpoints []*docs.Endpoint) (map[string][]byte, error) {
+		formatter := &docs.MarkdownFormatter{TOC: *toc, ParentHelp: *parentHelp, CLIOnly: *cliOnly, Anchors: *anchors, FrontMatter: *frontMatter}
+		if *templateDir != "" {
+			if err := formatter.LoadTemplates(*templateDir); err != nil {
+				return nil, err
+			}
+		}
+		if *outDir == "" {
+			return generate(ctx, "rpc.md", formatter, endpoints)
+		}
+		generated, err := formatter.GeneratePages(endpoints)
+		if err != nil {
+			return nil, err
+		}
+		pages := make(map[string][]byte)
+		for name, page := range generated {
+			pages[name] = []byte(page)
+		}
+		return pages, nil
+	}},
+	"openapi": {"openapi.yaml", allStatuses, generateOpenAPI},
+	"postman": document("postman.json", allStatuses, func() docs.Formatter {
+		return &docs.PostmanFormatter{BaseURL: *baseURL}
+	}),
+	"json-schema": {"", allStatuses, func(ctx context.Context, endpoints []*docs.Endpoint) (map[string][]byte, error) {
+		return (&docs.JSONSchemaFormatter{BaseID: *baseID, TypeMap: types}).GenerateFiles(ctx, endpoints)
+	}},
+	"asyncapi": document("asyncapi.yaml", allStatuses, func() docs.Formatter {
+		return &docs.AsyncAPIFormatter{Host: *host, DocsURL: *docsURL, TypeMap: types}
+	}),
+	"goclient": document("client.go", "active,experimental,deprecated", func() docs.Formatter {
+		return &docs.GoClientFormatter{Package: *pkg, TypeMap: types}
+	}),
+	"goserver": document("server.go", "active,experimental,deprecated", func() docs.Formatter {
+		return &docs.GoServerFormatter{Package: *pkg, TypeMap: types}
+	}),
+	"typescript": document("kubo-rpc.d.ts", "active,experimental,deprecated", func() docs.Formatter {
+		return &docs.TypeScriptFormatter{TypeMap: types}
+	}),
+	"cli-mapping": {"cli-mapping.json", allStatuses, func(ctx context.Context, endpoints []*docs.Endpoint) (map[string][]byte, error) {
+		if *csvMapping {
+			return generate(ctx, "cli-mapping.csv", &docs.CLIMappingFormatter{CSV: true}, endpoints)
+		}
+		return generate(ctx, "cli-mapping.json", new(docs.CLIMappingFormatter), endpoints)
+	}},
+	"quickstart": document("rpc-quickstart.md", allStatuses, func() docs.Formatter {
+		return new(docs.QuickstartFormatter)
+	}),
+	// The gateway and routing APIs are modeled by hand.
+	"gateway": document("gateway-openapi.yaml", allStatuses, func() docs.Formatter {
+		return &docs.GatewayFormatter{Info: docs.OpenAPIInfo{Servers: servers}}
+	}),
+	"routing": document("routing-openapi.yaml", allStatuses, func() docs.Formatter {
+		return &docs.RoutingFormatter{Info: docs.OpenAPIInfo{Servers: servers}}
+	}),
+}
+
+// document returns a formatter writing the single document of a
+// docs.Formatter into file. The docs.Formatter is created when generating,
+// after the flags are parsed.
+func document(file, include string, newFormatter func() docs.Formatter) formatter {
+	return formatter{file, include, func(ctx context.Context, endpoints []*docs.Endpoint) (map[string][]byte, error) {
+		return generate(ctx, file, newFormatter(), endpoints)
+	}}
+}
+
+// generate runs a docs.Formatter, returning its document as file, ending
+// with a newline.
+func generate(ctx context.Context, file string, f docs.Formatter, endpoints []*docs.Endpoint) (map[string][]byte, error) {
+	content, err := f.Generate(ctx, endpoints)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return map[string][]byte{file: []byte(content)}, nil
+}
+
+// execFormatter returns the formatter of an external executable, e.g.
+// "exec:./my-formatter", writing its output into -out-dir as the name of
+// the executable.
+func execFormatter(name string) (formatter, error) {
+	f, err := docs.NewExecFormatter(name)
+	if err != nil {
+		return formatter{}, err
+	}
+	return document(filepath.Base(f.Command), allStatuses, func() docs.Formatter { return f }), nil
+}
+
+func formatterList() []string {
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func main() {
-	endpoints := docs.AllEndpoints()
-	formatter := new(docs.MarkdownFormatter)
-	fmt.Println(docs.GenerateDocs(endpoints, formatter))
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [DUMP.json...]\n       %s coverage|consistency [flags]\n\nWith endpoint dumps of http-api-diff, writes the OpenAPI spec of each Kubo version into -out-dir (openapi-v0.24.yaml...).\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if err := docs.Configure(flag.CommandLine, "http-api-docs", *config); err != nil {
+		log.Fatal(err)
+	}
+	if *typeMap != "" {
+		var err error
+		if types, err = docs.LoadTypeMap(*typeMap); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	if *timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, *timeout)
+		defer cancelTimeout()
+	}
+
+	if flag.Arg(0) == "coverage" {
+		coverage(ctx, flag.Args()[1:])
+		return
+	}
+	if flag.Arg(0) == "consistency" {
+		consistency(ctx, flag.Args()[1:])
+		return
+	}
+
+	if !slices.Contains(docs.ParentHelpModes, *parentHelp) {
+		log.Fatalf("unknown -parent-help %q, expected link or prepend", *parentHelp)
+	}
+	if openAPIMode(ctx) {
+		return
+	}
+
+	var selected []string
+	for _, name := range strings.Split(*formatterNames, ",") {
+		name = strings.TrimSpace(name)
+		if strings.HasPrefix(name, docs.ExecFormatterPrefix) {
+			f, err := execFormatter(name)
+			if err != nil {
+				log.Fatal(err)
+			}
+			formatters[name] = f
+		}
+		if _, ok := formatters[name]; !ok {
+			log.Fatalf("unknown formatter %q, expected one of %s", name, strings.Join(formatterList(), ", "))
+		}
+		selected = append(selected, name)
+	}
+	if !slices.Contains(docs.AnchorStrategies, *anchors) {
+		log.Fatalf("unknown -anchors %q, expected one of %s", *anchors, strings.Join(docs.AnchorStrategies, ", "))
+	}
+	if !slices.Contains(docs.FrontMatterStyles, *frontMatter) {
+		log.Fatalf("unknown -front-matter %q, expected one of %s", *frontMatter, strings.Join(docs.FrontMatterStyles, ", "))
+	}
+	if *outDir == "" && (len(selected) > 1 || formatters[selected[0]].file == "") {
+		log.Fatalf("-out-dir is required to generate %s", *formatterNames)
+	}
+
+	all := allEndpoints()
+	for _, name := range selected {
+		f := formatters[name]
+		parsed, err := docs.ParseStatuses(orDefault(*include, f.include))
+		if err != nil {
+			log.Fatal(err)
+		}
+		files, err := f.generate(ctx, docs.WithStatus(all, parsed))
+		if err != nil {
+			log.Fatalf("%s: %s", name, err)
+		}
+		if *outDir == "" {
+			// Formatters writing a single file may name it after their
+			// options, e.g. cli-mapping.csv.
+			for _, content := range files {
+				fmt.Print(string(content))
+			}
+			continue
+		}
+		if err := writeFiles(*outDir, files); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *report != "" {
+		if err := writeReport(ctx, all, *report); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// openAPIMode runs the modes working with the OpenAPI spec instead of the
+// formatters: the specs of endpoint dumps, -validate-against, -record,
+// -serve and -dry-run. It tells whether one of them ran.
+func openAPIMode(ctx context.Context) bool {
+	statuses, err := docs.ParseStatuses(orDefault(*include, allStatuses))
+	if err != nil {
+		log.Fatal(err)
+	}
+	switch {
+	case flag.NArg() > 0:
+		if *outDir == "" {
+			log.Fatal("-out-dir is required to generate the specs of endpoint dumps")
+		}
+		generateVersions(ctx, flag.Args(), statuses)
+	case *validateAgainst != "":
+		validate(ctx, docs.WithStatus(allEndpoints(), statuses))
+	case *record != "":
+		recordExamples(ctx, docs.WithStatus(allEndpoints(), statuses))
+	case *serve != "":
+		serveSpec(ctx, docs.WithStatus(allEndpoints(), statuses))
+	case *dryRun:
+		printPlan(ctx, docs.WithStatus(allEndpoints(), statuses))
+	default:
+		return false
+	}
+	return true
+}
+
+// allEndpoints returns the endpoints of -from-snapshot, or the ones
+// extracted from the Kubo commands, which are saved into -snapshot.
+func allEndpoints() []*docs.Endpoint {
+	if *fromSnapshot != "" {
+		dump, err := docs.LoadSnapshot(*fromSnapshot)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if *kuboVersion == "" {
+			*kuboVersion = dump.KuboVersion
+		}
+		return dump.Endpoints
+	}
+	all := docs.AllEndpoints()
+	if *snapshot != "" {
+		if err := docs.SaveSnapshot(*snapshot, all); err != nil {
+			log.Fatal(err)
+		}
+	}
+	return all
+}
+
+// writeReport writes the report of the endpoints selected by -include into
+// path.
+func writeReport(ctx context.Context, all []*docs.Endpoint, path string) error {
+	statuses, err := docs.ParseStatuses(orDefault(*include, allStatuses))
+	if err != nil {
+		return err
+	}
+	report, err := (&docs.OpenAPIFormatter{TypeMap: types}).Report(ctx, docs.WithStatus(all, statuses))
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// writeFiles writes the files generated by a formatter into dir.
+func writeFiles(dir string, files map[string][]byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// coverage prints the documentation coverage of the endpoints, and exits
+// with status 1 when the percentage of complete endpoints is below the
+// threshold.
+func coverage(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("coverage", flag.ExitOnError)
+	threshold := fs.Float64("threshold", 0, "Minimum percentage of completely documented endpoints (description, response schema and example).")
+	include := fs.String("include", allStatuses, "Comma-separated list of the statuses of the endpoints to measure.")
+	examplesDir := fs.String("examples", "examples", "Directory of the response examples recorded with -record.")
+	verbose := fs.Bool("v", false, "List what is missing from each incomplete endpoint.")
+	asJSON := fs.Bool("json", false, "Print the coverage as JSON.")
+	fs.Parse(args)
+
+	statuses, err := docs.ParseStatuses(*include)
+	if err != nil {
+		log.Fatal(err)
+	}
+	formatter := &docs.OpenAPIFormatter{TypeMap: types}
+	if formatter.Examples, err = docs.LoadExamples(*examplesDir); err != nil {
+		log.Fatal(err)
+	}
+	c, err := formatter.Coverage(ctx, docs.WithStatus(allEndpoints(), statuses))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *asJSON {
+		b, err := json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(b))
+	} else {
+		docs.WriteCoverage(os.Stdout, c, *verbose)
+	}
+	if c.Percent() < *threshold {
+		log.Fatalf("coverage %.1f%% is below the threshold of %.1f%%", c.Percent(), *threshold)
+	}
+}
+
+// consistency prints where the Markdown reference and the OpenAPI spec of
+// the endpoints disagree, and exits with status 1 if they do.
+func consistency(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("consistency", flag.ExitOnError)
+	include := fs.String("include", allStatuses, "Comma-separated list of the statuses of the endpoints to check.")
+	fs.Parse(args)
+
+	statuses, err := docs.ParseStatuses(*include)
+	if err != nil {
+		log.Fatal(err)
+	}
+	found, err := docs.CheckConsistency(ctx, docs.WithStatus(allEndpoints(), statuses))
+	if err != nil {
+		log.Fatal(err)
+	}
+	docs.WriteInconsistencies(os.Stdout, found)
+	if len(found) > 0 {
+		log.Fatalf("the Markdown reference and the OpenAPI spec disagree in %d places", len(found))
+	}
 }
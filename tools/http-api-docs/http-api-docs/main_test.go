@@ -1,7 +1,74 @@
 package main
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	docs "github.com/ipfs/ipfs-docs/tools/http-api-docs"
+)
 
 func TestMain(t *testing.T) {
 	main()
 }
+
+func TestOpenAPIFormatter(t *testing.T) {
+	files, err := formatters["openapi"].generate(context.Background(), docs.AllEndpoints()[:3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files["openapi.yaml"]) == 0 {
+		t.Errorf("the openapi formatter wrote no spec: %v", files)
+	}
+}
+
+func TestGenerateVersions(t *testing.T) {
+	dir := t.TempDir()
+	dump := filepath.Join(dir, "kubo.json")
+	f, err := os.Create(dump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := docs.WriteEndpoints(f, docs.AllEndpoints()[:3]); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	*outDir = filepath.Join(dir, "specs")
+	t.Cleanup(func() { *outDir = "" })
+	generateVersions(context.Background(), []string{dump}, docs.AllStatuses)
+	if _, err := os.Stat(filepath.Join(*outDir, docs.VersionedSpecName(docs.IPFSVersion()))); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMinVersionsOfOneDump(t *testing.T) {
+	dump := filepath.Join(t.TempDir(), "kubo-v0.1.0.json")
+	f, err := os.Create(dump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	past := docs.EndpointsDump{KuboVersion: "0.1.0", Endpoints: docs.AllEndpoints()[:2]}
+	if err := json.NewEncoder(f).Encode(past); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	history = stringList{dump}
+	t.Cleanup(func() { history = nil })
+	endpoints := docs.AllEndpoints()[:3]
+	formatter, err := newFormatter(nil, currentDump(endpoints))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The endpoints missing from the only dump of the history are new in the
+	// current version.
+	if v := formatter.MinVersions[endpoints[2].Name]; v == nil || v.Version != docs.KuboVersion() {
+		t.Errorf("%s should be new in %s, got %+v", endpoints[2].Name, docs.KuboVersion(), v)
+	}
+	if v := formatter.MinVersions[endpoints[0].Name]; v != nil && v.Version != "" {
+		t.Errorf("%s should be as old as the history, got %+v", endpoints[0].Name, v)
+	}
+}
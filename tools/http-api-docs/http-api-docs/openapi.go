@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	docs "github.com/ipfs/ipfs-docs/tools/http-api-docs"
+)
+
+// The options of the openapi formatter, and of the modes which use the
+// generated schemas (-record, -validate-against, -serve, -dry-run and the
+// specs of endpoint dumps).
+var (
+	title             = flag.String("title", "", "openapi: Title of the spec (info.title).")
+	apiVersion        = flag.String("api-version", "", "openapi: Version of the spec (info.version). Defaults to the Kubo version.")
+	kuboVersion       = flag.String("kubo-version", "", "openapi: Kubo version the spec describes (x-kubo-version). Defaults to the version of the Kubo module in the build info, or to the one of -from-snapshot.")
+	security          = flag.Bool("security", false, "openapi: Document the authentication configured with API.Authorizations (Kubo 0.25 and later).")
+	noProvenance      = flag.Bool("strip-provenance", false, "openapi: Omit the x-provenance extensions telling where each schema comes from.")
+	codeSamples       = flag.Bool("code-samples", false, "openapi: Add curl and kubo-rpc-client examples to each operation (x-codeSamples).")
+	idStyle           = flag.String("operation-id-style", docs.OperationIDSlash, "openapi: Style of the operation IDs: slash (pin/add), camel (pinAdd) or snake (pin_add).")
+	removed           = flag.String("removed", docs.RemovedDeprecate, "openapi: What to do with the removed endpoints: \"deprecate\" them (deprecated operations with x-removed: true), \"omit\" them, or list them in an \"appendix\" of the description of the spec.")
+	overlay           = flag.String("overlay", "", "openapi: YAML file patching the generated operations.")
+	examplesDir       = flag.String("examples", "examples", "openapi: Directory of the response examples recorded with -record, embedded in the spec.")
+	docsURL           = flag.String("docs-url", docs.DefaultDocsURL, "openapi, asyncapi: URL of the RPC API reference the operations link to, e.g. a staging deployment of the docs.")
+	exampleSize       = flag.Int("external-example-size", docs.DefaultExternalExampleSize, "openapi: Link the examples larger than this many bytes to their section of -docs-url (externalValue) instead of embedding them. 0 embeds all examples.")
+	jobs              = flag.Int("jobs", 0, "openapi: Number of endpoints generated concurrently. Defaults to the number of CPUs.")
+	strict            = flag.Bool("strict", false, "openapi: Fail on endpoints which can't be generated and on warnings (e.g. unsupported types), with a summary.")
+	htmlOut           = flag.String("html-out", "", "openapi: Also write a static HTML documentation site (index.html and openapi.yaml) into this directory.")
+	htmlUI            = flag.String("html-ui", "redoc", "openapi: UI of the HTML site: redoc or swagger-ui.")
+	includeDebug      = flag.String("include-debug", "", "openapi: Also write a second spec, of the debug endpoints of the daemon (/debug/vars, /debug/pprof/...) and the /api/v0/diag commands, into this file.")
+	responseTypeIndex = flag.String("response-type-index", "", "openapi: Also write a JSON index of the endpoints returning each response type to this file.")
+
+	dryRun          = flag.Bool("dry-run", false, "Instead of generating, list what the openapi formatter generates for each endpoint.")
+	record          = flag.String("record", "", "Instead of generating, add a fixture to the RPC API at this URL (e.g. the one of a throwaway daemon), call example endpoints and write their sanitized responses into -examples.")
+	validateAgainst = flag.String("validate-against", "", "Instead of generating, call a safe subset of the endpoints on the RPC API at this URL (e.g. http://127.0.0.1:5001) and report the responses which don't match the schemas of the openapi formatter.")
+	serve           = flag.String("serve", "", "Instead of generating, serve the OpenAPI spec with Swagger UI on this address (e.g. :8080), generating it again on each page load.")
+	serveTarget     = flag.String("serve-target", "http://127.0.0.1:5001", "RPC API called by \"Try it out\" in -serve mode. Its API.HTTPHeaders must allow the origin of the page.")
+
+	serverVars  stringList
+	timeFormats stringList
+	history     stringList
+	skipGlobals stringList
+)
+
+func init() {
+	flag.Var(&serverVars, "server-variable", "openapi: Default of a variable of the server listed without -server-url, as name=value, e.g. port=5002. Can be repeated.")
+	flag.Var(&skipGlobals, "skip-global-parameter", "openapi: Name of a global parameter (e.g. stream-channels) not to document. Can be repeated.")
+	flag.Var(&history, "history", "openapi: Endpoint dump of a past Kubo release, written by http-api-diff, from which the release in which each endpoint and option first appeared is derived (x-kubo-min-version). Can be repeated.")
+	flag.Var(&timeFormats, "time-format", "openapi: Representation of the times or durations of a placeholder or of a response field, as key=format, e.g. <duration-ns>=go-duration or /api/v0/swarm/peers:Peers.Latency=go-duration. The formats are date-time, unix, duration-ns and go-duration. Can be repeated.")
+}
+
+// generateOpenAPI returns the spec of the endpoints as openapi.yaml, and
+// writes the outputs derived from it: -include-debug, -html-out and
+// -response-type-index.
+func generateOpenAPI(ctx context.Context, endpoints []*docs.Endpoint) (map[string][]byte, error) {
+	formatter, err := newFormatter(servers, currentDump(endpoints))
+	if err != nil {
+		return nil, err
+	}
+	files, err := generate(ctx, "openapi.yaml", formatter, endpoints)
+	if err != nil {
+		return nil, err
+	}
+	spec := string(files["openapi.yaml"])
+
+	if *includeDebug != "" {
+		metrics, err := docs.RecordedMetricFamilies(formatter.Examples)
+		if err != nil {
+			return nil, err
+		}
+		debug := &docs.DebugFormatter{Info: docs.OpenAPIInfo{Servers: servers, ServerVariables: formatter.Info.ServerVariables}, RecordedMetrics: metrics}
+		spec, err := debug.Generate(ctx, endpoints)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(*includeDebug, []byte(spec), 0o644); err != nil {
+			return nil, err
+		}
+	}
+	if *htmlOut != "" {
+		site := &docs.HTMLFormatter{Title: formatter.Info.Title, UI: *htmlUI}
+		if err := site.WriteSite(*htmlOut, spec); err != nil {
+			return nil, err
+		}
+	}
+	if *responseTypeIndex != "" {
+		index, err := docs.ResponseTypeIndexJSON(endpoints)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(*responseTypeIndex, index, 0o644); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// newFormatter returns an OpenAPIFormatter configured by the flags, for the
+// specs of the given dumps. They are added to the -history, whose oldest
+// dump is only a baseline.
+func newFormatter(servers []string, specs []*docs.EndpointsDump) (*docs.OpenAPIFormatter, error) {
+	formatter := &docs.OpenAPIFormatter{TypeMap: types}
+	formatter.Info = docs.OpenAPIInfo{
+		Title:   *title,
+		Version: *apiVersion,
+		Servers: servers,
+	}
+	for _, v := range serverVars {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid server variable %q, expected name=value", v)
+		}
+		if formatter.Info.ServerVariables == nil {
+			formatter.Info.ServerVariables = make(map[string]string)
+		}
+		formatter.Info.ServerVariables[name] = value
+	}
+	formatter.KuboVersion = *kuboVersion
+	formatter.Security = *security
+	formatter.StripProvenance = *noProvenance
+	formatter.CodeSamples = *codeSamples
+	formatter.OperationIDStyle = *idStyle
+	formatter.Strict = *strict
+	formatter.Jobs = *jobs
+	formatter.DocsURL = *docsURL
+	formatter.ExternalExampleSize = *exampleSize
+	formatter.SkipGlobalParameters = skipGlobals
+	formatter.ParentHelp = *parentHelp
+	if !slices.Contains(docs.RemovedPolicies, *removed) {
+		return nil, fmt.Errorf("unknown -removed %q, expected deprecate, omit or appendix", *removed)
+	}
+	formatter.Removed = *removed
+	for _, v := range timeFormats {
+		key, format, ok := strings.Cut(v, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid time format %q, expected key=format", v)
+		}
+		if err := formatter.SetTimeFormat(key, format); err != nil {
+			return nil, err
+		}
+	}
+	if *overlay != "" {
+		var err error
+		formatter.Overlay, err = docs.LoadOverlay(*overlay)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(history) > 0 {
+		past, err := readDumps(history)
+		if err != nil {
+			return nil, err
+		}
+		formatter.MinVersions = docs.DeriveMinVersions(append(past, specs...))
+	}
+	if *examplesDir != "" {
+		var err error
+		formatter.Examples, err = docs.LoadExamples(*examplesDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return formatter, nil
+}
+
+// generateVersions writes the spec of each endpoint dump into outDir.
+func generateVersions(ctx context.Context, paths []string, statuses []cmds.Status) {
+	dumps, err := readDumps(paths)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, dump := range dumps {
+		dump.Endpoints = docs.WithStatus(dump.Endpoints, statuses)
+	}
+
+	formatter, err := newFormatter(servers, dumps)
+	if err != nil {
+		log.Fatal(err)
+	}
+	specs, err := docs.GenerateVersions(ctx, dumps, *formatter)
+	if err != nil {
+		log.Fatal(err)
+	}
+	files := make(map[string][]byte, len(specs))
+	for name, spec := range specs {
+		files[name] = []byte(spec)
+	}
+	if err := writeFiles(*outDir, files); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// currentDump returns the dump of the endpoints of the Kubo version the spec
+// describes.
+func currentDump(endpoints []*docs.Endpoint) []*docs.EndpointsDump {
+	version := *kuboVersion
+	if version == "" {
+		version = docs.KuboVersion()
+	}
+	return []*docs.EndpointsDump{{KuboVersion: version, Endpoints: endpoints}}
+}
+
+// readDumps reads the endpoint dumps written by http-api-diff.
+func readDumps(paths []string) ([]*docs.EndpointsDump, error) {
+	var dumps []*docs.EndpointsDump
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		dump, err := docs.ReadEndpoints(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		dumps = append(dumps, dump)
+	}
+	return dumps, nil
+}
+
+// printPlan prints what the openapi formatter generates for each endpoint.
+func printPlan(ctx context.Context, endpoints []*docs.Endpoint) {
+	formatter, err := newFormatter(servers, currentDump(endpoints))
+	if err != nil {
+		log.Fatal(err)
+	}
+	plan, err := formatter.DryRun(ctx, endpoints)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := docs.WritePlan(os.Stdout, plan); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// serveSpec serves the spec with Swagger UI until ctx is done.
+func serveSpec(ctx context.Context, endpoints []*docs.Endpoint) {
+	generate := func(ctx context.Context) (string, error) {
+		formatter, err := newFormatter(append([]string{*serveTarget}, servers...), currentDump(endpoints))
+		if err != nil {
+			return "", err
+		}
+		if err := formatter.Build(ctx, endpoints); err != nil {
+			return "", err
+		}
+		return formatter.SpecYAML()
+	}
+	site := &docs.HTMLFormatter{Title: *title, UI: "swagger-ui"}
+	server := &http.Server{Addr: *serve, Handler: site.Handler(generate)}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	log.Printf("Serving the spec on %s", *serve)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
+
+func validate(ctx context.Context, endpoints []*docs.Endpoint) {
+	mismatches, err := docs.ValidateAgainst(ctx, http.DefaultClient, *validateAgainst, endpoints)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, m := range mismatches {
+		fmt.Println(m)
+	}
+	if len(mismatches) > 0 {
+		log.Fatalf("%d mismatches between the spec and %s", len(mismatches), *validateAgainst)
+	}
+}
+
+func recordExamples(ctx context.Context, endpoints []*docs.Endpoint) {
+	examples, err := docs.RecordExamples(ctx, http.DefaultClient, *record, endpoints)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := docs.WriteExamples(*examplesDir, examples); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Recorded %d examples into %s", len(examples), *examplesDir)
+}
@@ -0,0 +1,80 @@
+// This is an utility to check an OpenAPI spec of the RPC API against the lint
+// rules of the docs package, e.g. to gate its regeneration in CI.
+//
+// Without arguments, it checks the spec generated for the Kubo version it was
+// built with. It exits with status 1 when there are errors.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	docs "github.com/ipfs/ipfs-docs/tools/http-api-docs"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+var format = flag.String("format", "json", "Output format: json, sarif or text.")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-format json|sarif|text] [SPEC.yaml]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var findings []docs.LintFinding
+	switch flag.NArg() {
+	case 0:
+		formatter := new(docs.OpenAPIFormatter)
+		if err := formatter.Build(context.Background(), docs.AllEndpoints()); err != nil {
+			log.Fatal(err)
+		}
+		findings = formatter.Lint()
+	case 1:
+		b, err := os.ReadFile(flag.Arg(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		var spec openapi3.Spec
+		if err := spec.UnmarshalYAML(b); err != nil {
+			log.Fatalf("%s: %s", flag.Arg(0), err)
+		}
+		findings = docs.LintSpec(&spec)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	switch *format {
+	case "json":
+		if findings == nil {
+			findings = []docs.LintFinding{}
+		}
+		out, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(out))
+	case "sarif":
+		out, err := docs.LintSARIF("http-api-lint", findings)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(out))
+	case "text":
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+
+	if n := docs.LintErrors(findings); n > 0 {
+		log.Printf("%d errors", n)
+		os.Exit(1)
+	}
+}
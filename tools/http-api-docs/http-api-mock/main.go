@@ -0,0 +1,57 @@
+// This is an utility to serve a mock of the go-ipfs RPC API, answering the
+// documented endpoints with canned responses.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+
+	docs "github.com/ipfs/ipfs-docs/tools/http-api-docs"
+)
+
+var (
+	listen  = flag.String("listen", "127.0.0.1:5001", "Address to listen on.")
+	include = flag.String("include", "active,experimental,deprecated", "Comma-separated list of the statuses of the endpoints to mock.")
+	typeMap = flag.String("type-map", "", "YAML file of placeholder types merged over the built-in ones, see LoadTypeMap.")
+)
+
+func main() {
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	handler, err := newHandler()
+	if err != nil {
+		log.Fatal(err)
+	}
+	server := &http.Server{Addr: *listen, Handler: handler}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	log.Printf("Mocking the RPC API on %s", *listen)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
+
+// newHandler returns the mock of the endpoints selected by the flags.
+func newHandler() (http.Handler, error) {
+	statuses, err := docs.ParseStatuses(*include)
+	if err != nil {
+		return nil, err
+	}
+	m := docs.NewMockServer(docs.WithStatus(docs.AllEndpoints(), statuses))
+	if *typeMap != "" {
+		if m.TypeMap, err = docs.LoadTypeMap(*typeMap); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,24 @@
+package docs
+
+import (
+	"bytes"
+	_ "embed"
+)
+
+// IRVersion is the version of the intermediate representation of the
+// endpoints: the dumps written by WriteEndpoints. Fields may be added within
+// a version. Removing, renaming or changing the meaning of one bumps it,
+// along with the version in the name of the schema.
+const IRVersion = 1
+
+// IRSchemaURL is the $id of IRSchema, which the dumps reference.
+const IRSchemaURL = "https://raw.githubusercontent.com/ipfs/ipfs-docs/main/tools/http-api-docs/schemas/endpoints-v1.schema.json"
+
+//go:embed schemas/endpoints-v1.schema.json
+var irSchema []byte
+
+// IRSchema returns the JSON Schema (draft 2020-12) of the dumps, documenting
+// each field of Endpoint and Argument for tools consuming them.
+func IRSchema() []byte {
+	return bytes.Clone(irSchema)
+}
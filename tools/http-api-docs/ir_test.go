@@ -0,0 +1,80 @@
+package docs
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestIRSchema checks that the schema of the dumps documents the fields of
+// the types they are made of, and only those.
+func TestIRSchema(t *testing.T) {
+	var schema struct {
+		ID         string `json:"$id"`
+		Properties map[string]any
+		Defs       map[string]struct{ Properties map[string]any } `json:"$defs"`
+	}
+	if err := json.Unmarshal(IRSchema(), &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.ID != IRSchemaURL {
+		t.Errorf("$id is %s, expected IRSchemaURL", schema.ID)
+	}
+
+	for name, typ := range map[string]reflect.Type{
+		"":              reflect.TypeOf(EndpointsDump{}),
+		"Endpoint":      reflect.TypeOf(Endpoint{}),
+		"Argument":      reflect.TypeOf(Argument{}),
+		"ParentHelp":    reflect.TypeOf(ParentHelp{}),
+		"LongPoll":      reflect.TypeOf(LongPoll{}),
+		"FieldPresence": reflect.TypeOf(FieldPresence{}),
+		"AsyncEffect":   reflect.TypeOf(AsyncEffect{}),
+		"AsyncFollowUp": reflect.TypeOf(AsyncFollowUp{}),
+	} {
+		properties := schema.Properties
+		if name != "" {
+			properties = schema.Defs[name].Properties
+		}
+		var fields []string
+		for i := 0; i < typ.NumField(); i++ {
+			field, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+			if field == "" {
+				field = typ.Field(i).Name
+			}
+			fields = append(fields, field)
+			if _, ok := properties[field]; !ok {
+				t.Errorf("%s.%s is missing from the schema", typ.Name(), field)
+			}
+		}
+		for property := range properties {
+			if !slices.Contains(fields, property) {
+				t.Errorf("%s has no field %s of the schema", typ.Name(), property)
+			}
+		}
+	}
+}
+
+func TestReadEndpointsVersion(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteEndpoints(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	dump, err := ReadEndpoints(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dump.IRVersion != IRVersion || dump.Schema != IRSchemaURL {
+		t.Errorf("unexpected version %d and $schema %s", dump.IRVersion, dump.Schema)
+	}
+
+	// Dumps predating the versioning are read, newer ones are not.
+	if _, err := ReadEndpoints(strings.NewReader(`{"KuboVersion": "0.24.0", "Endpoints": []}`)); err != nil {
+		t.Errorf("unexpected error for an unversioned dump: %s", err)
+	}
+	if _, err := ReadEndpoints(strings.NewReader(`{"IRVersion": 2, "KuboVersion": "0.40.0", "Endpoints": []}`)); err == nil {
+		t.Errorf("expected an error for a dump of a newer version")
+	}
+}
@@ -0,0 +1,161 @@
+package docs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// JSONSchemaFormatter generates a standalone JSON Schema (draft 2020-12) of
+// the response of each endpoint, for runtime validation with libraries like
+// ajv. Schemas of streaming endpoints describe each streamed value.
+type JSONSchemaFormatter struct {
+	// BaseID, if set, is the URL under which the schemas are published.
+	// The $id of each schema is BaseID followed by its file name.
+	BaseID string
+	// TypeMap overrides the types of placeholders (see LoadTypeMap).
+	TypeMap TypeMap
+}
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// GenerateFiles returns the schemas, indexed by file name (e.g.
+// "api-v0-pin-add.json"). Endpoints returning text have no schema.
+func (jf *JSONSchemaFormatter) GenerateFiles(ctx context.Context, api []*Endpoint) (map[string][]byte, error) {
+	schemas := make(map[string][]byte)
+	for _, endp := range api {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		out, err := responseJSONSchema(jf.TypeMap, endp)
+		if err != nil {
+			return nil, err
+		}
+		if out == nil {
+			continue
+		}
+		file := endpointAnchor(endp.Name) + ".json"
+		out["$schema"] = jsonSchemaDraft
+		if jf.BaseID != "" {
+			out["$id"] = jf.BaseID + file
+		}
+		schemas[file], err = json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return nil, &EndpointError{Endpoint: endp.Name, Err: err}
+		}
+	}
+	return schemas, nil
+}
+
+// Generate returns a single document bundling the schemas under $defs,
+// keyed by the anchor of the endpoint (e.g. "api-v0-pin-add"). Its $id is
+// BaseID followed by jsonSchemaBundle.
+func (jf *JSONSchemaFormatter) Generate(ctx context.Context, api []*Endpoint) (string, error) {
+	defs := make(map[string]any)
+	for _, endp := range api {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		out, err := responseJSONSchema(jf.TypeMap, endp)
+		if err != nil {
+			return "", err
+		}
+		if out != nil {
+			defs[endpointAnchor(endp.Name)] = out
+		}
+	}
+	bundle := map[string]any{
+		"$schema": jsonSchemaDraft,
+		"title":   "Responses of the Kubo RPC API",
+		"$defs":   defs,
+	}
+	if jf.BaseID != "" {
+		bundle["$id"] = jf.BaseID + jsonSchemaBundle
+	}
+	b, err := json.MarshalIndent(bundle, "", "  ")
+	return string(b), err
+}
+
+// jsonSchemaBundle is the file name of the document returned by
+// JSONSchemaFormatter.Generate.
+const jsonSchemaBundle = "kubo-rpc.schema.json"
+
+// responseJSONSchema returns the schema of the response of an endpoint, or
+// nil if it returns text.
+func responseJSONSchema(types TypeMap, endp *Endpoint) (map[string]any, error) {
+	if endp.Response == "" || endp.Response == textResponse {
+		return nil, nil
+	}
+	var doc any
+	if err := json.Unmarshal([]byte(endp.Response), &doc); err != nil {
+		return nil, &EndpointError{Endpoint: endp.Name, Err: fmt.Errorf("parsing the response: %w", err)}
+	}
+	// The required and nullable fields are the same as in the OpenAPI spec.
+	schema := inlineSchema(applyPresence(endp.ResponseFields, "", doc, genSchemaOrRefForResponse(&warnings{endpoint: endp.Name}, types, doc, false)))
+	if schema == nil {
+		return nil, nil
+	}
+
+	// OpenAPI 3.0 schemas, as generated, are valid JSON Schemas but for
+	// nullable: only translate it and add the keywords of standalone
+	// documents.
+	out, err := schemaMap(schema)
+	if err != nil {
+		return nil, &EndpointError{Endpoint: endp.Name, Err: err}
+	}
+	jsonSchemaNullable(out)
+	out["title"] = "Response of " + endp.Name
+	if endp.Streaming {
+		out["description"] = "Each of the newline-delimited JSON values streamed by " + endp.Name + "."
+	}
+	return out, nil
+}
+
+// schemaMap returns a generated schema as a generic JSON object, to embed it
+// in other documents.
+func schemaMap(schema *openapi3.Schema) (map[string]any, error) {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]any
+	err = json.Unmarshal(raw, &out)
+	return out, err
+}
+
+// jsonSchemaNullable replaces the nullable keyword of OpenAPI 3.0, which
+// JSON Schema ignores, in a schema and its subschemas: "null" is added to the
+// type, or, for schemas without a type, is an alternative in an anyOf.
+func jsonSchemaNullable(v any) {
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			jsonSchemaNullable(item)
+		}
+	case map[string]any:
+		for _, sub := range v {
+			jsonSchemaNullable(sub)
+		}
+		if nullable, ok := v["nullable"].(bool); ok {
+			delete(v, "nullable")
+			if !nullable {
+				return
+			}
+			if t, ok := v["type"].(string); ok {
+				v["type"] = []any{t, "null"}
+				if enum, ok := v["enum"].([]any); ok {
+					v["enum"] = append(enum, nil)
+				}
+				return
+			}
+			schema := make(map[string]any, len(v))
+			for k, sub := range v {
+				schema[k] = sub
+				delete(v, k)
+			}
+			v["anyOf"] = []any{schema, map[string]any{"type": "null"}}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package docs
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestJSONSchemaFixture(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	schemas, err := (&JSONSchemaFormatter{BaseID: "https://example.com/schemas/"}).GenerateFiles(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schemas) != 3 {
+		t.Errorf("expected schemas for json, stream and upload only, got %d", len(schemas))
+	}
+
+	var schema struct {
+		Schema     string `json:"$schema"`
+		ID         string `json:"$id"`
+		Type       string
+		Required   []string
+		Properties map[string]struct {
+			Type                 any
+			Nullable             any
+			AdditionalProperties struct{ Type string }
+		}
+	}
+	if err := json.Unmarshal(schemas["fixture-v0-json.json"], &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Schema != jsonSchemaDraft || schema.ID != "https://example.com/schemas/fixture-v0-json.json" {
+		t.Errorf("unexpected $schema or $id in %s", schemas["fixture-v0-json.json"])
+	}
+	if schema.Type != "object" || schema.Properties["Size"].Type != "integer" || schema.Properties["Counts"].AdditionalProperties.Type != "integer" {
+		t.Errorf("unexpected schema %s", schemas["fixture-v0-json.json"])
+	}
+	// Like in the OpenAPI spec, the map is nullable and the fields required,
+	// but with the keywords of JSON Schema.
+	counts := schema.Properties["Counts"]
+	if !reflect.DeepEqual(counts.Type, []any{"object", "null"}) || counts.Nullable != nil || len(schema.Required) == 0 {
+		t.Errorf("unexpected presence of the fields in %s", schemas["fixture-v0-json.json"])
+	}
+}
+
+func TestJSONSchemaBundle(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	out, err := (&JSONSchemaFormatter{BaseID: "https://example.com/schemas/"}).Generate(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bundle struct {
+		Schema string                           `json:"$schema"`
+		ID     string                           `json:"$id"`
+		Defs   map[string]struct{ Type string } `json:"$defs"`
+	}
+	if err := json.Unmarshal([]byte(out), &bundle); err != nil {
+		t.Fatal(err)
+	}
+	if bundle.Schema != jsonSchemaDraft || bundle.ID != "https://example.com/schemas/"+jsonSchemaBundle {
+		t.Errorf("unexpected $schema or $id in %s", out)
+	}
+	if len(bundle.Defs) != 3 || bundle.Defs["fixture-v0-json"].Type != "object" {
+		t.Errorf("expected the schemas of json, stream and upload under $defs, got %s", out)
+	}
+}
+
+func TestJSONSchemaNullable(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"Name":  map[string]any{"type": "string", "nullable": true, "enum": []any{"a"}},
+			"Links": map[string]any{"nullable": true, "oneOf": []any{map[string]any{"type": "string"}}},
+			"Size":  map[string]any{"type": "integer", "nullable": false},
+		},
+	}
+	jsonSchemaNullable(schema)
+	want := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"Name": map[string]any{"type": []any{"string", "null"}, "enum": []any{"a", nil}},
+			"Links": map[string]any{"anyOf": []any{
+				map[string]any{"oneOf": []any{map[string]any{"type": "string"}}},
+				map[string]any{"type": "null"},
+			}},
+			"Size": map[string]any{"type": "integer"},
+		},
+	}
+	if !reflect.DeepEqual(schema, want) {
+		t.Errorf("got %v, want %v", schema, want)
+	}
+}
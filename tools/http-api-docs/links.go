@@ -0,0 +1,41 @@
+package docs
+
+import (
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// addLinks adds to the successful response of an endpoint the links of
+// operationLinks whose target is in the spec.
+func (myself *OpenAPIFormatter) addLinks(resp *openapi3.Response, endp *Endpoint) {
+	for _, link := range operationLinks[endp.Name] {
+		id, ok := myself.operationIDs[link.Target]
+		if !ok {
+			continue
+		}
+		l := (&openapi3.Link{}).
+			WithOperationID(id).
+			WithParameters(map[string]any{link.Parameter: linkExpression(link.Field)})
+		description := link.Description
+		if endp.Streaming {
+			description += " The field is the one of the last streamed value."
+		}
+		if description != "" {
+			l.WithDescription(strings.TrimSpace(description))
+		}
+		resp.WithLinksItem(link.Name, openapi3.LinkOrRef{Link: l})
+	}
+}
+
+// linkExpression returns the runtime expression of a field of the response
+// body, e.g. "$response.body#/Hash" for "Hash" or "$response.body#/Cid/~1"
+// for "Cid./".
+func linkExpression(field string) string {
+	var pointer strings.Builder
+	for _, name := range strings.Split(field, ".") {
+		name = strings.ReplaceAll(name, "~", "~0")
+		pointer.WriteString("/" + strings.ReplaceAll(name, "/", "~1"))
+	}
+	return "$response.body#" + pointer.String()
+}
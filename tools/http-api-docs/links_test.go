@@ -0,0 +1,44 @@
+package docs
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLinks(t *testing.T) {
+	api := []*Endpoint{
+		{Name: "/api/v0/add", Response: `{"Hash": "<string>", "Name": "<string>"}`, Streaming: true},
+		{Name: "/api/v0/pin/add", Arguments: []*Argument{{Name: "ipfs-path", Type: "string", Required: true}}},
+		{Name: "/api/v0/key/gen", Response: `{"Id": "<string>", "Name": "<string>"}`},
+	}
+	formatter := new(OpenAPIFormatter)
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+
+	add := formatter.spec.Paths.MapOfPathItemValues["/api/v0/add"].MapOfOperationValues["post"]
+	links := add.Responses.MapOfResponseOrRefValues["200"].Response.Links
+	if len(links) != 1 {
+		t.Fatalf("expected only the link to pin/add, whose target is in the spec, got %+v", links)
+	}
+	link := links["PinAdd"].Link
+	if link == nil || *link.OperationID != "pin/add" || link.Parameters["arg"] != "$response.body#/Hash" {
+		t.Errorf("unexpected link %+v", link)
+	}
+
+	keyGen := formatter.spec.Paths.MapOfPathItemValues["/api/v0/key/gen"].MapOfOperationValues["post"]
+	if links := keyGen.Responses.MapOfResponseOrRefValues["200"].Response.Links; len(links) != 0 {
+		t.Errorf("name/publish is not in the spec, got links %+v", links)
+	}
+}
+
+func TestLinkExpression(t *testing.T) {
+	for field, want := range map[string]string{
+		"Hash":  "$response.body#/Hash",
+		"Cid./": "$response.body#/Cid/~1",
+	} {
+		if got := linkExpression(field); got != want {
+			t.Errorf("linkExpression(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// Levels of the lint findings, as in SARIF.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+	LintNote    = "note"
+)
+
+// LintRule is a check of LintSpec.
+type LintRule struct {
+	ID          string
+	Level       string
+	Description string
+}
+
+// LintRules are the rules checked by LintSpec, like the ones of Spectral's
+// OpenAPI rule set the generated specs are expected to pass.
+var LintRules = []LintRule{
+	{"operation-description", LintWarning, "Operations have a summary or a description."},
+	{"operation-operationId", LintError, "Operations have an operationId."},
+	{"operation-operationId-unique", LintError, "The operationIds are unique."},
+	{"parameter-schema-type", LintError, "Parameters have a typed schema."},
+	{"response-schema", LintError, "JSON responses have a schema."},
+	{"response-example", LintNote, "JSON responses have an example."},
+}
+
+// LintFinding is a violation of a LintRule. Location is the operation, like
+// "paths./api/v0/add.post", followed by the parameter or response if the
+// finding is about one.
+type LintFinding struct {
+	Rule     string
+	Level    string
+	Location string
+	Message  string
+}
+
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", f.Level, f.Location, f.Message, f.Rule)
+}
+
+// LintSpec checks a spec against LintRules, and returns the findings sorted
+// by location.
+func LintSpec(spec *openapi3.Spec) []LintFinding {
+	levels := make(map[string]string, len(LintRules))
+	for _, rule := range LintRules {
+		levels[rule.ID] = rule.Level
+	}
+	var findings []LintFinding
+	report := func(rule, location, format string, a ...any) {
+		findings = append(findings, LintFinding{Rule: rule, Level: levels[rule], Location: location, Message: fmt.Sprintf(format, a...)})
+	}
+
+	paths := make([]string, 0, len(spec.Paths.MapOfPathItemValues))
+	for path := range spec.Paths.MapOfPathItemValues {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	operationIDs := make(map[string]string)
+	for _, path := range paths {
+		item := spec.Paths.MapOfPathItemValues[path]
+		methods := make([]string, 0, len(item.MapOfOperationValues))
+		for method := range item.MapOfOperationValues {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			op := item.MapOfOperationValues[method]
+			location := "paths." + path + "." + method
+
+			if isBlank(op.Summary) && isBlank(op.Description) {
+				report("operation-description", location, "no summary or description")
+			}
+			switch {
+			case isBlank(op.ID):
+				report("operation-operationId", location, "no operationId")
+			case operationIDs[*op.ID] != "":
+				report("operation-operationId-unique", location, "operationId %q is also the one of %s", *op.ID, operationIDs[*op.ID])
+			default:
+				operationIDs[*op.ID] = location
+			}
+
+			for _, p := range op.Parameters {
+				if p.Parameter == nil {
+					continue
+				}
+				if p.Parameter.Schema == nil && len(p.Parameter.Content) == 0 {
+					report("parameter-schema-type", location+".parameters."+p.Parameter.Name, "no schema")
+				} else if p.Parameter.Schema != nil && !isTyped(p.Parameter.Schema) {
+					report("parameter-schema-type", location+".parameters."+p.Parameter.Name, "the schema has no type")
+				}
+			}
+
+			statuses := make([]string, 0, len(op.Responses.MapOfResponseOrRefValues))
+			for status := range op.Responses.MapOfResponseOrRefValues {
+				statuses = append(statuses, status)
+			}
+			sort.Strings(statuses)
+			for _, status := range statuses {
+				resp := op.Responses.MapOfResponseOrRefValues[status].Response
+				if resp == nil {
+					continue
+				}
+				for mime, media := range resp.Content {
+					if !strings.HasSuffix(mime, "json") {
+						continue
+					}
+					responseLocation := location + ".responses." + status + "." + mime
+					if media.Schema == nil {
+						report("response-schema", responseLocation, "no schema")
+					}
+					if media.Example == nil && len(media.Examples) == 0 && (media.Schema == nil || media.Schema.Schema == nil || media.Schema.Schema.Example == nil) {
+						report("response-example", responseLocation, "no example")
+					}
+				}
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Location < findings[j].Location })
+	return findings
+}
+
+func isBlank(s *string) bool {
+	return s == nil || strings.TrimSpace(*s) == ""
+}
+
+// isTyped tells whether a schema has a type, is a reference or combines
+// schemas which have one.
+func isTyped(schema *openapi3.SchemaOrRef) bool {
+	if schema.SchemaReference != nil {
+		return true
+	}
+	s := schema.Schema
+	if s.Type != nil {
+		return true
+	}
+	alternatives := append(append(append([]openapi3.SchemaOrRef{}, s.AnyOf...), s.OneOf...), s.AllOf...)
+	for _, alt := range alternatives {
+		if !isTyped(&alt) {
+			return false
+		}
+	}
+	return len(alternatives) > 0
+}
+
+// LintErrors counts the findings of level LintError.
+func LintErrors(findings []LintFinding) int {
+	n := 0
+	for _, f := range findings {
+		if f.Level == LintError {
+			n++
+		}
+	}
+	return n
+}
+
+// Lint checks the spec built by the last call to Build (see LintSpec).
+func (myself *OpenAPIFormatter) Lint() []LintFinding {
+	return LintSpec(&myself.spec)
+}
+
+// LintSARIF renders the findings as a SARIF 2.1.0 log, for code scanning
+// tools.
+func LintSARIF(tool string, findings []LintFinding) ([]byte, error) {
+	type message struct {
+		Text string `json:"text"`
+	}
+	type rule struct {
+		ID               string  `json:"id"`
+		ShortDescription message `json:"shortDescription"`
+		DefaultLevel     struct {
+			Level string `json:"level"`
+		} `json:"defaultConfiguration"`
+	}
+	type location struct {
+		LogicalLocations []struct {
+			FullyQualifiedName string `json:"fullyQualifiedName"`
+		} `json:"logicalLocations"`
+	}
+	type result struct {
+		RuleID    string     `json:"ruleId"`
+		Level     string     `json:"level"`
+		Message   message    `json:"message"`
+		Locations []location `json:"locations"`
+	}
+
+	rules := make([]rule, len(LintRules))
+	for i, r := range LintRules {
+		rules[i].ID = r.ID
+		rules[i].ShortDescription.Text = r.Description
+		rules[i].DefaultLevel.Level = r.Level
+	}
+	results := make([]result, len(findings))
+	for i, f := range findings {
+		var loc location
+		loc.LogicalLocations = append(loc.LogicalLocations, struct {
+			FullyQualifiedName string `json:"fullyQualifiedName"`
+		}{f.Location})
+		results[i] = result{RuleID: f.Rule, Level: f.Level, Message: message{f.Message}, Locations: []location{loc}}
+	}
+
+	log := map[string]any{
+		"version": "2.1.0",
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"runs": []any{map[string]any{
+			"tool":    map[string]any{"driver": map[string]any{"name": tool, "rules": rules}},
+			"results": results,
+		}},
+	}
+	return json.MarshalIndent(log, "", "  ")
+}
@@ -0,0 +1,91 @@
+package docs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+const lintSpec = `
+openapi: 3.0.3
+info: {title: lint, version: "1"}
+paths:
+  /a:
+    post:
+      operationId: same
+      parameters:
+        - {name: untyped, in: query, schema: {description: No type.}}
+        - {name: typed, in: query, schema: {anyOf: [{type: string}, {type: integer}]}}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json: {}
+  /b:
+    post:
+      operationId: same
+      summary: B.
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json: {schema: {type: object}, example: {}}
+            text/plain: {}
+`
+
+func TestLintSpec(t *testing.T) {
+	var spec openapi3.Spec
+	if err := spec.UnmarshalYAML([]byte(lintSpec)); err != nil {
+		t.Fatal(err)
+	}
+	findings := LintSpec(&spec)
+	want := []LintFinding{
+		{"operation-description", LintWarning, "paths./a.post", "no summary or description"},
+		{"parameter-schema-type", LintError, "paths./a.post.parameters.untyped", "the schema has no type"},
+		{"response-schema", LintError, "paths./a.post.responses.200.application/json", "no schema"},
+		{"response-example", LintNote, "paths./a.post.responses.200.application/json", "no example"},
+		{"operation-operationId-unique", LintError, "paths./b.post", `operationId "same" is also the one of paths./a.post`},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("got %v, want %v", findings, want)
+	}
+	for i := range want {
+		if findings[i] != want[i] {
+			t.Errorf("got %v, want %v", findings[i], want[i])
+		}
+	}
+	if n := LintErrors(findings); n != 3 {
+		t.Errorf("%d errors, want 3", n)
+	}
+
+	out, err := LintSARIF("test", findings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log struct {
+		Runs []struct {
+			Results []struct{ RuleID string }
+		}
+	}
+	if err := json.Unmarshal(out, &log); err != nil {
+		t.Fatal(err)
+	}
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != len(want) {
+		t.Errorf("unexpected SARIF log %s", out)
+	}
+}
+
+func TestLintFixture(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	formatter := new(OpenAPIFormatter)
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range formatter.Lint() {
+		if f.Level != LintNote {
+			t.Error(f)
+		}
+	}
+}
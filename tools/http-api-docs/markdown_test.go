@@ -1,9 +1,177 @@
 package docs
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// generateDocs returns the docs of api, failing the test on errors.
+func generateDocs(t *testing.T, api []*Endpoint, formatter BlockFormatter) string {
+	t.Helper()
+	doc, err := GenerateDocs(api, formatter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// generatePages returns the pages of api, failing the test on errors.
+func generatePages(t *testing.T, md *MarkdownFormatter, api []*Endpoint) map[string]string {
+	t.Helper()
+	pages, err := md.GeneratePages(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pages
+}
 
 func TestMarkdown(t *testing.T) {
 	endpoints := AllEndpoints()
 	formatter := new(MarkdownFormatter)
-	GenerateDocs(endpoints, formatter)
+	generateDocs(t, endpoints, formatter)
+}
+
+func TestMarkdownTOC(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	if doc := generateDocs(t, api, new(MarkdownFormatter)); strings.Contains(doc, "## Table of contents") {
+		t.Errorf("the table of contents should be optional")
+	}
+	doc := generateDocs(t, api, &MarkdownFormatter{TOC: true})
+	if !strings.Contains(doc, "- fixture\n  - [/fixture/v0/json](#fixture-v0-json)\n") {
+		t.Errorf("missing table of contents in:\n%s", doc)
+	}
+}
+
+func TestMarkdownPages(t *testing.T) {
+	api := []*Endpoint{
+		{Name: "/api/v0/add", Response: `{"Hash": "<string>"}`, ResponseType: "cmd.AddEvent"},
+		{Name: "/api/v0/pin/add", Response: `{"Pins": ["<string>"]}`, ResponseType: "pin.AddPinOutput"},
+		{Name: "/api/v0/pin/ls", Response: `{"Keys": {}}`},
+	}
+	pages := generatePages(t, new(MarkdownFormatter), api)
+	if len(pages) != 3 {
+		t.Fatalf("expected index, add and pin pages, got %d pages", len(pages))
+	}
+	index := pages["index.md"]
+	for _, want := range []string{"- [pin](pin.md)\n  - [/pin/add](pin.md#api-v0-pin-add)", "[`/add`](add.md#api-v0-add)"} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index does not contain %q", want)
+		}
+	}
+	if pin := pages["pin.md"]; !strings.Contains(pin, "## /api/v0/pin/ls") || strings.Contains(pin, "## /api/v0/add") {
+		t.Errorf("unexpected pin page:\n%s", pin)
+	}
+}
+
+func TestGenerationDate(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	intro := new(MarkdownFormatter).GenerateIntro()
+	if !strings.Contains(intro, "Generated on 2023-11-14") {
+		t.Errorf("SOURCE_DATE_EPOCH should set the date of the intro")
+	}
+}
+
+func TestMarkdownTemplates(t *testing.T) {
+	dir := t.TempDir()
+	override := `{{define "page"}}---
+layout: rpc
+---
+{{end}}{{define "example"}}Try it with ` + "`ipfs {{.Name}}`" + `.
+{{end}}`
+	if err := os.WriteFile(filepath.Join(dir, "site.tmpl"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	formatter := new(MarkdownFormatter)
+	if err := formatter.LoadTemplates(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	api := []*Endpoint{{Name: "/api/v0/pin/ls", Response: `{"Keys": {}}`}}
+	page := generatePages(t, formatter, api)["pin.md"]
+	if !strings.HasPrefix(page, "---\nlayout: rpc\n---\n") || !strings.Contains(page, "Try it with `ipfs /api/v0/pin/ls`.") {
+		t.Errorf("the templates are not overridden:\n%s", page)
+	}
+	if !strings.Contains(page, "### Arguments") {
+		t.Errorf("the other templates should be the default ones:\n%s", page)
+	}
+	if strings.Contains(generatePages(t, new(MarkdownFormatter), api)["pin.md"], "layout: rpc") {
+		t.Errorf("the default templates should not be changed")
+	}
+
+	if err := new(MarkdownFormatter).LoadTemplates(t.TempDir()); err == nil {
+		t.Errorf("expected an error for a directory without templates")
+	}
+}
+
+func TestMarkdownTemplateErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.tmpl"), []byte(`{{define "example"}}{{.Missing}}{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	load := func() *MarkdownFormatter {
+		formatter := new(MarkdownFormatter)
+		if err := formatter.LoadTemplates(dir); err != nil {
+			t.Fatal(err)
+		}
+		return formatter
+	}
+	api := []*Endpoint{{Name: "/api/v0/pin/ls", Response: `{"Keys": {}}`}}
+	if doc, err := GenerateDocs(api, load()); err == nil || doc != "" {
+		t.Errorf("expected an error and no docs, got %v", err)
+	}
+	if pages, err := load().GeneratePages(api); err == nil || pages != nil {
+		t.Errorf("expected an error and no pages, got %v", err)
+	}
+}
+
+func TestMarkdownAnchors(t *testing.T) {
+	api := []*Endpoint{{Name: "/api/v0/pin/add", Response: `{"Pins": ["<string>"]}`}}
+	for _, c := range []struct {
+		anchors string
+		want    []string
+	}{
+		{AnchorsVuePress, []string{"\n## /api/v0/pin/add\n", "(#api-v0-pin-add)"}},
+		{AnchorsGitHub, []string{"\n## /api/v0/pin/add\n", "(#apiv0pinadd)"}},
+		{AnchorsExplicit, []string{"\n## /api/v0/pin/add {#api-v0-pin-add}\n", "(#api-v0-pin-add)"}},
+		{AnchorsHTML, []string{"\n<a id=\"api-v0-pin-add\"></a>\n\n## /api/v0/pin/add\n", "(#api-v0-pin-add)"}},
+	} {
+		doc := generateDocs(t, api, &MarkdownFormatter{TOC: true, Anchors: c.anchors})
+		for _, want := range c.want {
+			if !strings.Contains(doc, want) {
+				t.Errorf("%s: the docs do not contain %q", c.anchors, want)
+			}
+		}
+	}
+	if got := githubSlug("Appendix: CLI-only commands"); got != "appendix-cli-only-commands" {
+		t.Errorf("unexpected slug %q", got)
+	}
+}
+
+func TestMarkdownFrontMatter(t *testing.T) {
+	api := []*Endpoint{{Name: "/api/v0/pin/add", Response: `{"Pins": ["<string>"]}`}}
+	pages := generatePages(t, &MarkdownFormatter{FrontMatter: FrontMatterDocusaurus}, api)
+	want := "---\ntitle: Kubo RPC API - pin\nsidebar_label: pin\ndescription: RPC API v0 reference for the pin commands of Kubo IPFS daemon.\ntoc_max_heading_level: 2\n---\n\n# pin commands\n"
+	if !strings.HasPrefix(pages["pin.md"], want) {
+		t.Errorf("unexpected front matter:\n%s", pages["pin.md"])
+	}
+	if !strings.HasPrefix(pages["index.md"], "---\ntitle: Kubo RPC API\nsidebar_label: RPC API\n") {
+		t.Errorf("unexpected front matter of the index:\n%s", pages["index.md"])
+	}
+
+	pages = generatePages(t, &MarkdownFormatter{FrontMatter: FrontMatterNone}, api)
+	if !strings.HasPrefix(pages["pin.md"], "# pin commands\n") || !strings.HasPrefix(pages["index.md"], "# Kubo RPC API v0 reference") {
+		t.Errorf("the front matter should be omitted:\n%s", pages["pin.md"])
+	}
+}
+
+func TestMarkdownHumanizedDefault(t *testing.T) {
+	api := []*Endpoint{{Name: "/api/v0/add", Options: []*Argument{
+		{Name: "inline-limit", Type: "int", Default: "262144", Description: "Maximum block size to inline."},
+	}}}
+	doc := generateDocs(t, api, new(MarkdownFormatter))
+	if !strings.Contains(doc, " Default: `262144` (256 KiB). ") {
+		t.Errorf("the default should be followed by its human-readable form only, in:\n%s", doc)
+	}
 }
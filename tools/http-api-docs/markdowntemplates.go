@@ -0,0 +1,145 @@
+package docs
+
+import (
+	"bytes"
+	"embed"
+	"html"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/markdown.md.tmpl
+var markdownTemplateFS embed.FS
+
+var markdownFuncs = template.FuncMap{
+	"escape":              html.EscapeString,
+	"upper":               strings.ToUpper,
+	"lower":               strings.ToLower,
+	"join":                strings.Join,
+	"helptext":            helptextMarkdown,
+	"statusLabel":         statusLabel,
+	"statusDescription":   statusDescription,
+	"argumentDescription": argumentDescription,
+	"humanizeDefault":     humanizeDefault,
+	"hasFileArgument":     hasFileArgument,
+	"exampleQuery":        exampleQuery,
+	"apiDescription":      func() string { return apiDescription },
+	"argument": func(arg *Argument, alias string) markdownArgument {
+		return markdownArgument{Argument: arg, Alias: alias}
+	},
+	"frontMatter": func(style, title, description, label string) markdownFrontMatter {
+		return markdownFrontMatter{Style: style, Title: title, Description: description, SidebarLabel: label}
+	},
+}
+
+// markdownTemplates are the default templates of MarkdownFormatter.
+var markdownTemplates = template.Must(template.New("markdown").Funcs(markdownFuncs).ParseFS(markdownTemplateFS, "templates/markdown.md.tmpl"))
+
+// LoadTemplates loads the templates (*.tmpl) of dir, which override the
+// default ones defined with the same name: "intro", "front-matter",
+// "status", "index", "page", "endpoint", "arguments", "argument", "body",
+// "body-description", "response", "example", "response-types" and
+// "cli-only".
+func (md *MarkdownFormatter) LoadTemplates(dir string) error {
+	templates, err := markdownTemplates.Clone()
+	if err != nil {
+		return err
+	}
+	if _, err := templates.ParseGlob(filepath.Join(dir, "*.tmpl")); err != nil {
+		return err
+	}
+	md.templates = templates
+	return nil
+}
+
+// execute renders a template. Errors, which can only come from templates
+// loaded with LoadTemplates, are returned by Err.
+func (md *MarkdownFormatter) execute(name string, data any) string {
+	templates := md.templates
+	if templates == nil {
+		templates = markdownTemplates
+	}
+	buf := new(bytes.Buffer)
+	if err := templates.ExecuteTemplate(buf, name, data); err != nil && md.err == nil {
+		md.err = err
+	}
+	return buf.String()
+}
+
+// Err returns the first error of the templates met while generating.
+func (md *MarkdownFormatter) Err() error {
+	return md.err
+}
+
+// String returns the namespace, which was the data of the page template
+// before the front matter was configurable.
+func (p markdownPage) String() string {
+	return p.Namespace
+}
+
+// link returns the link to an endpoint from the docs.
+func (md *MarkdownFormatter) link(name string) markdownLink {
+	return markdownLink{Name: name, Path: strings.TrimPrefix(name, APIPrefix), Link: md.endpointLink(name)}
+}
+
+// The data of the templates.
+type (
+	markdownIntro struct {
+		Date        string
+		KuboVersion string
+		// FrontMatter is the style of the front matter.
+		FrontMatter string
+	}
+	markdownPage struct {
+		Namespace   string
+		FrontMatter string
+	}
+	markdownFrontMatter struct {
+		Style        string
+		Title        string
+		Description  string
+		SidebarLabel string
+	}
+	markdownIndex struct {
+		// Pages is set when the namespaces have their own page.
+		Pages      bool
+		Namespaces []markdownNamespace
+	}
+	markdownNamespace struct {
+		Name      string
+		Page      string
+		Endpoints []markdownLink
+	}
+	markdownLink struct {
+		// Name is the name of the endpoint, e.g. "/api/v0/pin/add", and
+		// Path the same without the API prefix.
+		Name string
+		Path string
+		Link string
+	}
+	markdownEndpoint struct {
+		*Endpoint
+		// ParentHelp is the help of the parent commands, to link or
+		// prepend depending on ParentHelpMode.
+		ParentHelp     string
+		ParentHelpMode string
+		// Anchor is the anchor of the heading, to set explicitly depending
+		// on the Anchors strategy.
+		Anchor  string
+		Anchors string
+	}
+	markdownArguments struct {
+		Arguments []*Argument
+		Options   []*Argument
+	}
+	markdownArgument struct {
+		*Argument
+		// Alias is the name of the query parameter.
+		Alias string
+	}
+	markdownResponseType struct {
+		Name      string
+		Endpoints []markdownLink
+	}
+)
@@ -0,0 +1,106 @@
+package docs
+
+import (
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// MetricFamily is a family of the Prometheus metrics exposed by the daemon
+// on /debug/metrics/prometheus.
+type MetricFamily struct {
+	Name string `json:"name"`
+	// Type is "counter", "gauge", "summary", "histogram" or "untyped".
+	Type string `json:"type"`
+	Help string `json:"help,omitempty"`
+	// Labels are the names of the labels of the metrics of the family.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// metricsPath is the path of the Prometheus endpoint of the daemon.
+const metricsPath = "/debug/metrics/prometheus"
+
+// mimePrometheus is the content type of the Prometheus text format.
+const mimePrometheus = "text/plain; version=0.0.4"
+
+// MetricFamilies returns the metric families of the daemon, sorted by name:
+// the ones registered in g, which is the registry of Kubo
+// (prometheus.DefaultGatherer) when nil, the ones the daemon registers when
+// it starts (daemonMetricFamilies) and the recorded ones, scraped from a
+// daemon by RecordExamples. The registry of the generator only has the
+// families of the packages it imports, and none of the vectors without
+// metrics yet, so the ones of the subsystems the daemon starts (bitswap,
+// libp2p, the gateway, the provider...) are only known from a recording.
+func MetricFamilies(g prometheus.Gatherer, recorded []MetricFamily) ([]MetricFamily, error) {
+	if g == nil {
+		g = prometheus.DefaultGatherer
+	}
+	gathered, err := g.Gather()
+	if err != nil {
+		return nil, err
+	}
+	families := slices.Clone(daemonMetricFamilies)
+	add := func(family MetricFamily) {
+		if !slices.ContainsFunc(families, func(f MetricFamily) bool { return f.Name == family.Name }) {
+			families = append(families, family)
+		}
+	}
+	for _, family := range recorded {
+		add(family)
+	}
+	for _, mf := range gathered {
+		add(metricFamily(mf))
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i].Name < families[j].Name })
+	return families, nil
+}
+
+// ParseMetricFamilies returns the metric families of a response of
+// /debug/metrics/prometheus, sorted by name.
+func ParseMetricFamilies(r io.Reader) ([]MetricFamily, error) {
+	var parser expfmt.TextParser
+	parsed, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return nil, err
+	}
+	families := make([]MetricFamily, 0, len(parsed))
+	for _, mf := range parsed {
+		families = append(families, metricFamily(mf))
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i].Name < families[j].Name })
+	return families, nil
+}
+
+// metricFamily returns the family of mf, with the label names of its
+// metrics.
+func metricFamily(mf *dto.MetricFamily) MetricFamily {
+	family := MetricFamily{
+		Name: mf.GetName(),
+		Type: strings.ToLower(mf.GetType().String()),
+		Help: mf.GetHelp(),
+	}
+	for _, m := range mf.GetMetric() {
+		for _, label := range m.GetLabel() {
+			if !slices.Contains(family.Labels, label.GetName()) {
+				family.Labels = append(family.Labels, label.GetName())
+			}
+		}
+	}
+	return family
+}
+
+// genMetricsOperation returns the operation of the Prometheus endpoint,
+// listing the metric families in x-metrics.
+func genMetricsOperation(families []MetricFamily) openapi3.Operation {
+	op := debugOp("debugMetricsPrometheus", "Prometheus metrics",
+		"The metrics of the daemon in the Prometheus text format, for scraping. The metric families are listed in `x-metrics`.",
+		mimePrometheus, stringSchema())
+	op.WithMapOfAnythingItem("x-metrics", families)
+	return op
+}
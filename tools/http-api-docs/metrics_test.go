@@ -0,0 +1,81 @@
+package docs
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+func TestMetricFamilies(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	blocks := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "ipfs_test_blocks_total", Help: "Blocks."}, []string{"kind"})
+	blocks.WithLabelValues("raw").Inc()
+	reg.MustRegister(blocks)
+
+	families, err := MetricFamilies(reg, []MetricFamily{{Name: "ipfs_http_gw_requests_total", Type: "counter"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.IndexFunc(families, func(f MetricFamily) bool { return f.Name == "ipfs_test_blocks_total" })
+	if i < 0 {
+		t.Fatalf("missing the gathered family in %+v", families)
+	}
+	if f := families[i]; f.Type != "counter" || f.Help != "Blocks." || !slices.Equal(f.Labels, []string{"kind"}) {
+		t.Errorf("unexpected family %+v", f)
+	}
+	if !slices.ContainsFunc(families, func(f MetricFamily) bool { return f.Name == "ipfs_http_requests_total" }) {
+		t.Errorf("missing the families registered by the daemon")
+	}
+	if !slices.ContainsFunc(families, func(f MetricFamily) bool { return f.Name == "ipfs_http_gw_requests_total" }) {
+		t.Errorf("missing the recorded families")
+	}
+	if !slices.IsSortedFunc(families, func(a, b MetricFamily) int { return strings.Compare(a.Name, b.Name) }) {
+		t.Errorf("families are not sorted")
+	}
+}
+
+func TestParseMetricFamilies(t *testing.T) {
+	scraped := `# HELP ipfs_bitswap_wanthaves_broadcast Number of want-haves broadcast.
+# TYPE ipfs_bitswap_wanthaves_broadcast counter
+ipfs_bitswap_wanthaves_broadcast 3
+# HELP ipfs_http_gw_responses_total Gateway responses.
+# TYPE ipfs_http_gw_responses_total summary
+ipfs_http_gw_responses_total{code="200",quantile="0.5"} 1
+ipfs_http_gw_responses_total_sum{code="200"} 1
+ipfs_http_gw_responses_total_count{code="200"} 1
+`
+	families, err := ParseMetricFamilies(strings.NewReader(scraped))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MetricFamily{
+		{Name: "ipfs_bitswap_wanthaves_broadcast", Type: "counter", Help: "Number of want-haves broadcast."},
+		{Name: "ipfs_http_gw_responses_total", Type: "summary", Help: "Gateway responses.", Labels: []string{"code"}},
+	}
+	if !reflect.DeepEqual(families, want) {
+		t.Errorf("got %+v, want %+v", families, want)
+	}
+}
+
+func TestDebugSpecMetrics(t *testing.T) {
+	out, err := (&DebugFormatter{Metrics: prometheus.NewRegistry()}).Generate(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec openapi3.Spec
+	if err := spec.UnmarshalYAML([]byte(out)); err != nil {
+		t.Fatal(err)
+	}
+	op := spec.Paths.MapOfPathItemValues["/debug/metrics/prometheus"].MapOfOperationValues["get"]
+	if _, ok := op.Responses.MapOfResponseOrRefValues["200"].Response.Content[mimePrometheus]; !ok {
+		t.Errorf("expected a %s response", mimePrometheus)
+	}
+	if metrics, ok := op.MapOfAnything["x-metrics"].([]any); !ok || len(metrics) != len(daemonMetricFamilies) {
+		t.Errorf("expected the families of the daemon in x-metrics, got %v", op.MapOfAnything["x-metrics"])
+	}
+}
@@ -0,0 +1,115 @@
+package docs
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MinVersions are the Kubo releases in which endpoints and options first
+// appeared, by endpoint name, for the x-kubo-min-version extension. Client
+// libraries supporting several Kubo versions use it to feature-gate calls.
+type MinVersions map[string]*EndpointMinVersion
+
+// EndpointMinVersion is the release in which an endpoint first appeared, if
+// known, and the ones of the options added later.
+type EndpointMinVersion struct {
+	Version string            `json:",omitempty"`
+	Options map[string]string `json:",omitempty"`
+}
+
+// DeriveMinVersions returns the releases in which the endpoints and options
+// of the given dumps first appeared. Those of the oldest dump are left out,
+// as they may be older.
+func DeriveMinVersions(dumps []*EndpointsDump) MinVersions {
+	sorted := append([]*EndpointsDump{}, dumps...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareVersions(sorted[i].KuboVersion, sorted[j].KuboVersion) < 0
+	})
+
+	versions := make(MinVersions)
+	seen := make(map[string]map[string]bool)
+	for i, dump := range sorted {
+		for _, endp := range dump.Endpoints {
+			options, existed := seen[endp.Name]
+			if !existed {
+				options = make(map[string]bool)
+				seen[endp.Name] = options
+				if i > 0 {
+					versions[endp.Name] = &EndpointMinVersion{Version: dump.KuboVersion}
+				}
+			}
+			for _, opt := range endp.Options {
+				if options[opt.Name] {
+					continue
+				}
+				options[opt.Name] = true
+				if !existed || i == 0 {
+					// Options of new endpoints are as old as them.
+					continue
+				}
+				if versions[endp.Name] == nil {
+					versions[endp.Name] = &EndpointMinVersion{}
+				}
+				if versions[endp.Name].Options == nil {
+					versions[endp.Name].Options = make(map[string]string)
+				}
+				versions[endp.Name].Options[opt.Name] = dump.KuboVersion
+			}
+		}
+	}
+	return versions
+}
+
+// compareVersions compares two Kubo versions like "0.24.0" or
+// "v0.25.0-rc1", returning -1, 0 or 1. Release candidates are older than
+// their release.
+func compareVersions(a, b string) int {
+	a, preA, _ := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	b, preB, _ := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		var x, y int
+		if i < len(partsA) {
+			x, _ = strconv.Atoi(partsA[i])
+		}
+		if i < len(partsB) {
+			y, _ = strconv.Atoi(partsB[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	default:
+		return strings.Compare(preA, preB)
+	}
+}
+
+// minVersion returns the release in which an endpoint, or one of its options
+// if option is set, first appeared: the one of MinVersions, or of
+// knownMinVersions. It is empty if unknown.
+func (myself *OpenAPIFormatter) minVersion(endpoint, option string) string {
+	for _, versions := range []MinVersions{myself.MinVersions, knownMinVersions} {
+		v := versions[endpoint]
+		if v == nil {
+			continue
+		}
+		if option == "" && v.Version != "" {
+			return v.Version
+		}
+		if option != "" && v.Options[option] != "" {
+			return v.Options[option]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package docs
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDeriveMinVersions(t *testing.T) {
+	pin := func(opts ...string) *Endpoint {
+		endp := &Endpoint{Name: "/api/v0/pin/add"}
+		for _, name := range opts {
+			endp.Options = append(endp.Options, &Argument{Name: name})
+		}
+		return endp
+	}
+	dumps := []*EndpointsDump{
+		{KuboVersion: "0.25.0", Endpoints: []*Endpoint{pin("recursive", "name"), {Name: "/api/v0/pin/ls", Options: []*Argument{{Name: "names"}}}}},
+		{KuboVersion: "0.24.0", Endpoints: []*Endpoint{pin("recursive")}},
+		{KuboVersion: "0.25.0-rc1", Endpoints: []*Endpoint{pin("recursive")}},
+	}
+	want := MinVersions{
+		"/api/v0/pin/add": {Options: map[string]string{"name": "0.25.0"}},
+		"/api/v0/pin/ls":  {Version: "0.25.0"},
+	}
+	got := DeriveMinVersions(dumps)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	formatter := &OpenAPIFormatter{MinVersions: got}
+	if v := formatter.minVersion("/api/v0/pin/add", "name"); v != "0.25.0" {
+		t.Errorf("unexpected min version of the name option: %q", v)
+	}
+	if v := formatter.minVersion("/api/v0/routing/get", ""); v != "0.16.0" {
+		t.Errorf("the known min versions should be used, got %q", v)
+	}
+	if err := formatter.Build(context.Background(), dumps[0].Endpoints); err != nil {
+		t.Fatal(err)
+	}
+	op := formatter.spec.Paths.MapOfPathItemValues["/api/v0/pin/ls"].MapOfOperationValues["post"]
+	if op.MapOfAnything["x-kubo-min-version"] != "0.25.0" {
+		t.Errorf("missing x-kubo-min-version on pin/ls: %v", op.MapOfAnything)
+	}
+	for _, p := range formatter.spec.Paths.MapOfPathItemValues["/api/v0/pin/add"].MapOfOperationValues["post"].Parameters {
+		if p.Parameter == nil {
+			// A global parameter.
+			continue
+		}
+		if v, ok := p.Parameter.MapOfAnything["x-kubo-min-version"]; ok != (p.Parameter.Name == "name") || (ok && v != "0.25.0") {
+			t.Errorf("unexpected x-kubo-min-version of %s: %v", p.Parameter.Name, v)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		want int
+	}{
+		{"0.24.0", "0.25.0", -1},
+		{"v0.30.0", "0.4.23", 1},
+		{"0.25.0-rc1", "0.25.0", -1},
+		{"0.25", "0.25.0", 0},
+	} {
+		if got := compareVersions(c.a, c.b); got != c.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
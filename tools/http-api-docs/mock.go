@@ -0,0 +1,120 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+// MockServer is an http.Handler answering the given endpoints with canned
+// responses, built from their documented responses, so that clients can be
+// tested without a Kubo daemon. Like the RPC API, it only accepts POST
+// requests and checks that the required arguments are given, but the
+// responses don't depend on the request.
+type MockServer struct {
+	// TypeMap overrides the types of placeholders (see LoadTypeMap).
+	TypeMap TypeMap
+
+	endpoints map[string]*Endpoint
+}
+
+// NewMockServer returns a MockServer for the given endpoints.
+func NewMockServer(api []*Endpoint) *MockServer {
+	m := &MockServer{endpoints: make(map[string]*Endpoint, len(api))}
+	for _, endp := range api {
+		m.endpoints[endp.Name] = endp
+	}
+	return m
+}
+
+// mockText is the body of endpoints returning text.
+const mockText = "mock response\n"
+
+func (m *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endp, ok := m.endpoints[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "405 - Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	given := len(r.URL.Query()["arg"])
+	for _, arg := range endp.Arguments {
+		if arg.Type == "file" || !arg.Required {
+			continue
+		}
+		if given == 0 {
+			mockError(w, http.StatusBadRequest, cmds.ErrClient, fmt.Sprintf("argument %q is required", arg.Name))
+			return
+		}
+		given--
+	}
+
+	w.Header().Set("Trailer", "X-Stream-Error")
+	if endp.Response == "" || endp.Response == textResponse {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Stream-Output", "1")
+		fmt.Fprint(w, mockText)
+		return
+	}
+
+	var doc any
+	if err := json.Unmarshal([]byte(endp.Response), &doc); err != nil {
+		mockError(w, http.StatusInternalServerError, cmds.ErrNormal, "no mock response: "+err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if endp.Streaming {
+		w.Header().Set("X-Chunked-Output", "1")
+	}
+	// Encoder.Encode ends the value with a newline, which makes it a
+	// valid stream of one object as well.
+	json.NewEncoder(w).Encode(mockValue(m.TypeMap, doc))
+}
+
+// mockError writes an error like the go-ipfs-cmds HTTP handler does.
+func mockError(w http.ResponseWriter, status int, code cmds.ErrorType, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"Message": msg,
+		"Code":    code,
+		"Type":    "error",
+	})
+}
+
+// mockValue replaces the placeholders of a documented response (e.g.
+// "<int>") with values of their type.
+func mockValue(types TypeMap, x any) any {
+	switch v := x.(type) {
+	case string:
+		if t, ok := types.lookup(v); ok && t.Example != nil {
+			return t.Example
+		}
+		return v
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = mockValue(types, item)
+		}
+		return items
+	case map[string]any:
+		obj := make(map[string]any, len(v))
+		for k, item := range v {
+			// Maps are documented with a placeholder key.
+			if strings.HasPrefix(k, "<") && strings.HasSuffix(k, ">") {
+				k = strings.Trim(k, "<>")
+			}
+			obj[k] = mockValue(types, item)
+		}
+		return obj
+	default:
+		return v
+	}
+}
@@ -0,0 +1,65 @@
+package docs
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMockServer(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	server := httptest.NewServer(NewMockServer(api))
+	defer server.Close()
+
+	post := func(path string) (*http.Response, string) {
+		resp, err := server.Client().Post(server.URL+path, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	resp, body := post(fixturePrefix + "/json?arg=key")
+	var out fixtureOutput
+	if err := json.Unmarshal([]byte(body), &out); err != nil || resp.StatusCode != 200 {
+		t.Fatalf("unexpected response (%d, %v): %s", resp.StatusCode, err, body)
+	}
+	if out.Name != "string" || out.Size != 1 || len(out.Tags) != 1 || out.Counts["string"] != 1 {
+		t.Errorf("unexpected mock value %+v", out)
+	}
+
+	if resp, body := post(fixturePrefix + "/json"); resp.StatusCode != 400 || !strings.Contains(body, `"argument \"key\" is required"`) {
+		t.Errorf("missing arguments should fail (%d): %s", resp.StatusCode, body)
+	}
+	if resp, _ := post(fixturePrefix + "/stream"); resp.Header.Get("X-Chunked-Output") != "1" {
+		t.Errorf("stream should be chunked")
+	}
+	if resp, body := post(fixturePrefix + "/options"); resp.Header.Get("Content-Type") != "text/plain" || body != mockText {
+		t.Errorf("unexpected text response %q", body)
+	}
+	if resp, _ := post(fixturePrefix + "/unknown"); resp.StatusCode != 404 {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+	get, err := server.Client().Get(server.URL + fixturePrefix + "/json?arg=key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get.Body.Close()
+	if get.StatusCode != 405 {
+		t.Errorf("expected 405 for GET, got %d", get.StatusCode)
+	}
+}
+
+func TestMockValue(t *testing.T) {
+	var doc any
+	json.Unmarshal([]byte(`{"Cid": {"/": "<cid-string>"}, "Peers": {"<string>": ["<multiaddr-string>"]}, "Other": "literal"}`), &doc)
+	got, _ := json.Marshal(mockValue(nil, doc))
+	if want := `{"Cid":{"/":"bafkqaaa"},"Other":"literal","Peers":{"string":["/ip4/127.0.0.1/tcp/4001"]}}`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package docs
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// multipartBoundary is the boundary of the examples of multipart bodies.
+const multipartBoundary = "boundary"
+
+// genMultipartExamples returns the examples of the multipart body of an
+// endpoint: the raw body, showing the headers of each part.
+func genMultipartExamples(endpoint string) map[string]openapi3.ExampleOrRef {
+	examples := multipartExamples[endpoint]
+	if raw, ok := rawBodyEndpoints[endpoint]; ok {
+		examples = append(examples, multipartExample{Name: "data", Summary: "Raw data", Parts: []multipartPart{
+			{ContentType: raw.MediaType, Content: raw.Example},
+		}})
+	}
+	if len(examples) == 0 {
+		return nil
+	}
+	out := make(map[string]openapi3.ExampleOrRef, len(examples))
+	for _, ex := range examples {
+		description := strings.TrimSpace(ex.Description + " Sent with `Content-Type: multipart/form-data; boundary=" + multipartBoundary + "`; the lines end with CRLF.")
+		var body any = renderMultipart(ex.Parts)
+		out[ex.Name] = openapi3.ExampleOrRef{Example: &openapi3.Example{
+			Summary:     ptr(ex.Summary),
+			Description: &description,
+			Value:       &body,
+		}}
+	}
+	return out
+}
+
+// renderMultipart returns a multipart body as sent by the Kubo clients, with
+// LF line endings for readability. Parts without path have no filename.
+func renderMultipart(parts []multipartPart) string {
+	var b strings.Builder
+	for _, part := range parts {
+		fmt.Fprintf(&b, "--%s\n", multipartBoundary)
+		if part.Path == "" {
+			b.WriteString("Content-Disposition: form-data; name=\"file\"\n")
+		} else {
+			fmt.Fprintf(&b, "Content-Disposition: form-data; name=\"file\"; filename=\"%s\"\n", url.QueryEscape(part.Path))
+		}
+		fmt.Fprintf(&b, "Content-Type: %s\n", part.ContentType)
+		if part.Abspath != "" {
+			fmt.Fprintf(&b, "Abspath: %s\n", part.Abspath)
+		}
+		fmt.Fprintf(&b, "\n%s", part.Content)
+		if part.Content != "" && !strings.HasSuffix(part.Content, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	fmt.Fprintf(&b, "--%s--\n", multipartBoundary)
+	return b.String()
+}
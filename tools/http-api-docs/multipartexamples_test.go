@@ -0,0 +1,36 @@
+package docs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMultipartExamples(t *testing.T) {
+	examples := genMultipartExamples("/api/v0/add")
+	for _, name := range []string{"file", "directory", "wrapped", "nocopy"} {
+		if _, ok := examples[name]; !ok {
+			t.Errorf("add has no %s example", name)
+		}
+	}
+	dir := (*examples["directory"].Example.Value).(string)
+	for _, want := range []string{
+		"--boundary\nContent-Disposition: form-data; name=\"file\"; filename=\"docs\"\nContent-Type: application/x-directory\n\n--boundary\n",
+		"filename=\"docs%2Fimages%2Flogo.svg\"",
+	} {
+		if !strings.Contains(dir, want) {
+			t.Errorf("the directory example does not contain %q:\n%s", want, dir)
+		}
+	}
+	if nocopy := (*examples["nocopy"].Example.Value).(string); !strings.Contains(nocopy, "\nAbspath: /home/user/hello.txt\n") {
+		t.Errorf("the nocopy example has no Abspath header:\n%s", nocopy)
+	}
+
+	put := genMultipartExamples("/api/v0/dag/put")["data"].Example
+	want := "--boundary\nContent-Disposition: form-data; name=\"file\"\nContent-Type: application/octet-stream\n\n{\"hello\": \"world\"}\n--boundary--\n"
+	if got := (*put.Value).(string); got != want {
+		t.Errorf("unexpected raw example:\n%s", got)
+	}
+	if genMultipartExamples("/api/v0/version") != nil {
+		t.Error("endpoints without files have no examples")
+	}
+}
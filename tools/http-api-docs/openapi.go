@@ -1,10 +1,14 @@
 package docs
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -17,105 +21,497 @@ import (
 type OpenAPIFormatter struct {
 	reflector openapi3.Reflector
 	spec      openapi3.Spec
-	md        MarkdownFormatter
+
+	// names of the component schemas for response types
+	schemaNames map[string]string
+	// operation IDs, by endpoint name
+	operationIDs map[string]string
+
+	// Info overrides the metadata of the spec. Empty fields keep the
+	// defaults.
+	Info OpenAPIInfo
+
+	// KuboVersion is the version of Kubo the spec describes, used for
+	// info.version (unless Info.Version is set) and x-kubo-version.
+	// Defaults to KuboVersion().
+	KuboVersion string
+
+	// Security documents the authentication configured with
+	// API.Authorizations (Kubo 0.25 and later), for deployments which
+	// require it.
+	Security bool
+
+	// CodeSamples adds example calls to each operation (x-codeSamples).
+	CodeSamples bool
+
+	// OperationIDStyle is the style of the operation IDs: OperationIDSlash
+	// (the default), OperationIDCamel or OperationIDSnake. With the latter
+	// two, the x-operation-ids extension maps the IDs back to the
+	// endpoints.
+	OperationIDStyle string
+
+	// ParentHelp surfaces the help of the parent commands in the
+	// descriptions of the operations: ParentHelpLink or ParentHelpPrepend.
+	ParentHelp string
+
+	// Removed is the policy for the removed endpoints: RemovedDeprecate
+	// (the default), RemovedOmit or RemovedAppendix.
+	Removed string
+
+	// TimeFormats overrides the representation of the times and durations
+	// documented by a placeholder (see DefaultTimeFormats), e.g.
+	// "<duration-ns>": DurationGo.
+	TimeFormats map[string]string
+	// TimeFields overrides the representation of fields of the responses,
+	// by endpoint name and path of the field (e.g. "Peers.Latency"), on
+	// top of the known ones. See SetTimeFormat.
+	TimeFields map[string]map[string]string
+	// TypeMap overrides the types of placeholders, after the time formats
+	// (see LoadTypeMap).
+	TypeMap TypeMap
+
+	// DocsURL is the URL of the RPC API reference the operations link to,
+	// e.g. a staging deployment of the docs. Defaults to DefaultDocsURL.
+	DocsURL string
+	// ExternalExampleSize is the size in bytes above which examples are
+	// replaced by a link to the reference (externalValue). 0 embeds all
+	// examples.
+	ExternalExampleSize int
+
+	// SkipGlobalParameters are the names of the global parameters (see
+	// GlobalParameters) not to document, e.g. stream-channels.
+	SkipGlobalParameters []string
+	// names of the global parameters referenced by the operations
+	globalParameters []string
+
+	// MinVersions are the releases in which the endpoints and options
+	// first appeared (see DeriveMinVersions), on top of the known ones,
+	// for the x-kubo-min-version extension.
+	MinVersions MinVersions
+
+	// Examples are the responses recorded by RecordExamples, by endpoint
+	// name (see LoadExamples), embedded instead of the documented
+	// pseudo-JSON.
+	Examples map[string]*RecordedExample
+
+	// Overlay patches the generated operations, if set.
+	Overlay *Overlay
+
+	// StripProvenance omits the x-provenance extensions, e.g. for release
+	// builds.
+	StripProvenance bool
+
+	// Jobs is the number of endpoints Build generates concurrently.
+	// Defaults to GOMAXPROCS. The spec doesn't depend on it.
+	Jobs int
+
+	// Strict makes Build fail on the first endpoint which can't be
+	// generated, instead of skipping it, and with a WarningsError when
+	// there were warnings.
+	Strict bool
+	// Failures lists the endpoints skipped by the last call to Build.
+	Failures []*EndpointError
+	// Warnings lists the problems found by the last call to Build.
+	Warnings []Warning
 }
 
-// FIXME Share this with markdown.go
-var description = `When a Kubo IPFS node is running as a daemon, it exposes an HTTP RPC API that allows you to control the node and run the same commands you can from the command line.
+// EndpointError is the error for an endpoint which couldn't be generated.
+type EndpointError struct {
+	Endpoint string
+	Err      error
+}
 
-In many cases, using this RPC API is preferable to embedding IPFS directly in your program — it allows you to maintain peer connections that are longer lived than your app and you can keep a single IPFS node running instead of several if your app can be launched multiple times. In fact, the ` + "`ipfs`" + ` CLI commands use this RPC API when operating in online mode.`
+func (e *EndpointError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Endpoint, e.Err)
+}
+
+func (e *EndpointError) Unwrap() error {
+	return e.Err
+}
+
+// OpenAPIInfo is the metadata of the spec, for forks and hosted deployments
+// to brand it.
+type OpenAPIInfo struct {
+	Title       string
+	Version     string
+	Description string
+	// Servers are the URLs of the servers to list in the spec, e.g.
+	// "http://127.0.0.1:5001".
+	Servers []string
+	// ServerVariables override the defaults of the variables of the
+	// server listed when Servers is empty (see rpcServer), e.g. "port".
+	ServerVariables map[string]string
+}
 
 func (myself *OpenAPIFormatter) GenerateMetadata() {
+	kuboVersion := myself.KuboVersion
+	if kuboVersion == "" {
+		kuboVersion = KuboVersion()
+	}
+	info := OpenAPIInfo{
+		Title:       "IPFS RPC API",
+		Version:     kuboVersion,
+		Description: apiDescription,
+	}
+	if myself.Info.Title != "" {
+		info.Title = myself.Info.Title
+	}
+	if myself.Info.Version != "" {
+		info.Version = myself.Info.Version
+	}
+	if myself.Info.Description != "" {
+		info.Description = myself.Info.Description
+	}
+
 	myself.reflector = openapi3.Reflector{}
 	myself.reflector.Spec = &openapi3.Spec{Openapi: "3.0.0"}
 	myself.reflector.Spec.Info.
-		WithTitle("IPFS RPC API").
-		WithVersion("0.13.0").
-		WithDescription(description)
-	myself.reflector.Spec.WithExternalDocs(openapi3.ExternalDocumentation{
-		URL: "https://docs.ipfs.tech/reference/kubo/rpc/",
-	})
+		WithTitle(info.Title).
+		WithVersion(info.Version).
+		WithDescription(info.Description).
+		WithMapOfAnythingItem("x-kubo-version", kuboVersion)
+	for _, url := range myself.Info.Servers {
+		myself.reflector.Spec.Servers = append(myself.reflector.Spec.Servers, openapi3.Server{URL: url})
+	}
+	if len(myself.Info.Servers) == 0 {
+		myself.reflector.Spec.Servers = []openapi3.Server{rpcServer(myself.Info.ServerVariables)}
+	}
+	docsURL := myself.DocsURL
+	if docsURL == "" {
+		docsURL = DefaultDocsURL
+	}
+	myself.reflector.Spec.WithExternalDocs(openapi3.ExternalDocumentation{URL: docsURL})
+	if notes := releaseNotesURL(kuboVersion); notes != "" {
+		myself.reflector.Spec.Info.WithMapOfAnythingItem("x-release-notes", notes)
+	}
+	myself.reflector.Spec.WithComponents(genErrorComponents())
+	myself.reflector.Spec.Components.WithHeaders(genHeaderComponents())
+	for name, schema := range sharedSchemas() {
+		myself.reflector.Spec.Components.Schemas.WithMapOfSchemaOrRefValuesItem(name, schema)
+	}
+	for _, schema := range myself.reflector.Spec.Components.Schemas.MapOfSchemaOrRefValues {
+		myself.setProvenance(schema.Schema, ProvenanceManual)
+	}
+	if myself.Security {
+		myself.reflector.Spec.Components.WithSecuritySchemes(genSecuritySchemes())
+	}
 	myself.spec = *myself.reflector.Spec
-	myself.md = MarkdownFormatter{}
-}
-
-func genParameterForArgument(arg *Argument, aliasToArg bool) *openapi3.Parameter {
-	var t openapi3.SchemaType
-	switch arg.Type {
-	case "bool":
-		t = openapi3.SchemaTypeBoolean
-	case "int", "uint", "int64":
-		t = openapi3.SchemaTypeInteger
-	case "string":
-		t = openapi3.SchemaTypeString
-	case "array":
-		t = openapi3.SchemaTypeArray
-	case "file":
-		// This will be the request body.
-		return nil
-	default:
-		log.Printf("WARN: Unsupported type for argument: %s\n", arg.Type)
-		t = openapi3.SchemaTypeString
+}
+
+// releaseVersion matches the versions of Kubo with a GitHub release page:
+// releases and release candidates, e.g. "0.30.0" and "0.30.0-rc1".
+var releaseVersion = regexp.MustCompile(`^\d+\.\d+\.\d+(-rc\d+)?$`)
+
+// releaseNotesURL returns the GitHub release page of a Kubo version, or ""
+// for pseudo-versions and local builds, which were never released.
+func releaseNotesURL(kuboVersion string) string {
+	if !releaseVersion.MatchString(kuboVersion) {
+		return ""
+	}
+	return "https://github.com/ipfs/kubo/releases/tag/v" + kuboVersion
+}
+
+// rpcServerVariables are the variables of the URL of the default server,
+// with their defaults: those of Addresses.API.
+var rpcServerVariables = []struct {
+	name, def, description string
+}{
+	{"host", "127.0.0.1", "Host of the RPC API (`Addresses.API`)."},
+	{"port", "5001", "Port of the RPC API (`Addresses.API`)."},
+}
+
+// rpcServer returns the default server, a Kubo daemon at the address of
+// Addresses.API, with the given defaults of its variables.
+func rpcServer(defaults map[string]string) openapi3.Server {
+	server := openapi3.Server{
+		URL: "http://{host}:{port}",
+		Description: ptr("Kubo daemon at `Addresses.API`. When it is a Unix socket (`/unix/<path>` multiaddr), " +
+			"send the same requests over the socket, with any host, e.g. " +
+			"`curl --unix-socket <path> -X POST http://localhost/api/v0/version`."),
+		Variables: make(map[string]openapi3.ServerVariable, len(rpcServerVariables)),
+	}
+	for _, v := range rpcServerVariables {
+		variable := openapi3.ServerVariable{Default: v.def, Description: ptr(v.description)}
+		if def, ok := defaults[v.name]; ok {
+			variable.Default = def
+		}
+		server.Variables[v.name] = variable
+	}
+	for name := range defaults {
+		if _, ok := server.Variables[name]; !ok {
+			log.Printf("WARN: Ignoring unknown server variable %q\n", name)
+		}
 	}
+	return server
+}
+
+// securitySchemes are the names of the security schemes matching the
+// AuthSecret types of API.Authorizations ("bearer:" and "basic:").
+var securitySchemes = []string{"bearerAuth", "basicAuth"}
+
+func genSecuritySchemes() openapi3.ComponentsSecuritySchemes {
+	scope := " Each authorization of `API.Authorizations` only grants access to the endpoints under its `AllowedPaths`."
+	bearer := "`Authorization: Bearer <secret>`, for authorizations with an `AuthSecret` of the form `bearer:<secret>`." + scope
+	basic := "HTTP basic authentication, for authorizations with an `AuthSecret` of the form `basic:<user>:<password>`." + scope
+	schemes := openapi3.ComponentsSecuritySchemes{}
+	schemes.WithMapOfSecuritySchemeOrRefValuesItem(securitySchemes[0], openapi3.SecuritySchemeOrRef{
+		SecurityScheme: &openapi3.SecurityScheme{HTTPSecurityScheme: &openapi3.HTTPSecurityScheme{
+			Scheme:      "bearer",
+			Description: &bearer,
+			Bearer:      &openapi3.Bearer{},
+		}},
+	})
+	schemes.WithMapOfSecuritySchemeOrRefValuesItem(securitySchemes[1], openapi3.SecuritySchemeOrRef{
+		SecurityScheme: &openapi3.SecurityScheme{HTTPSecurityScheme: &openapi3.HTTPSecurityScheme{
+			Scheme:      "basic",
+			Description: &basic,
+		}},
+	})
+	return schemes
+}
+
+// errorResponses are the standard error statuses of the RPC API, with the
+// name under which they are registered in components/responses.
+var errorResponses = []struct {
+	status      int
+	name        string
+	description string
+}{
+	{http.StatusBadRequest, "BadRequest", "Malformed RPC, argument type error, etc."},
+	{http.StatusForbidden, "Forbidden", "RPC call forbidden, e.g. because of a missing or wrong Origin header."},
+	{http.StatusNotFound, "NotFound", "RPC endpoint doesn't exist."},
+	{http.StatusInternalServerError, "InternalServerError", "RPC endpoint returned an error."},
+}
+
+const errorSchemaName = "Error"
+
+// genErrorComponents returns the components shared by all operations to
+// describe errors. Commands fail with a JSON-encoded cmds.Error. Requests
+// rejected before reaching a command get a text/plain body instead.
+func genErrorComponents() openapi3.Components {
+	object := openapi3.SchemaTypeObject
+	str := openapi3.SchemaTypeString
+	integer := openapi3.SchemaTypeInteger
+	codeDescription := "0: command failed, 1: invalid argument, 2: internal error, 3: rate limited, 4: request forbidden"
 	schema := openapi3.Schema{
-		Type: &t,
+		Type:     &object,
+		Required: []string{"Message", "Code", "Type"},
+		Properties: map[string]openapi3.SchemaOrRef{
+			"Message": {Schema: &openapi3.Schema{Type: &str}},
+			"Code": {Schema: &openapi3.Schema{
+				Type:        &integer,
+				Enum:        []interface{}{0, 1, 2, 3, 4},
+				Description: &codeDescription,
+			}},
+			"Type": {Schema: &openapi3.Schema{
+				Type: &str,
+				Enum: []interface{}{"error"},
+			}},
+		},
 	}
-	if t == openapi3.SchemaTypeArray {
-		t2 := openapi3.SchemaTypeString
-		schema.Items = &openapi3.SchemaOrRef{
-			Schema: &openapi3.Schema{
-				Type: &t2,
+	schema.WithExample(map[string]any{
+		"Message": "invalid path \"foo\": path does not have enough components",
+		"Code":    0,
+		"Type":    "error",
+	})
+
+	components := openapi3.Components{}
+	components.WithSchemas(openapi3.ComponentsSchemas{
+		MapOfSchemaOrRefValues: map[string]openapi3.SchemaOrRef{
+			errorSchemaName: {Schema: &schema},
+		},
+	})
+	responses := openapi3.ComponentsResponses{}
+	for _, e := range errorResponses {
+		resp := openapi3.Response{
+			Description: e.description,
+			Content: map[string]openapi3.MediaType{
+				"application/json": {
+					Schema: &openapi3.SchemaOrRef{SchemaReference: &openapi3.SchemaReference{
+						Ref: "#/components/schemas/" + errorSchemaName,
+					}},
+				},
+				"text/plain": {
+					Schema: &openapi3.SchemaOrRef{Schema: &openapi3.Schema{Type: &str}},
+				},
 			},
 		}
+		responses.WithMapOfResponseOrRefValuesItem(e.name, openapi3.ResponseOrRef{Response: &resp})
 	}
-	if arg.Default != "" {
-		var d any
-		var err any
-		switch t {
-		case openapi3.SchemaTypeBoolean:
-			d, err = strconv.ParseBool(arg.Default)
-		case openapi3.SchemaTypeInteger:
-			d, err = strconv.ParseInt(arg.Default, 10, 32)
-		default:
-			d = arg.Default
-			err = nil
+	components.WithResponses(responses)
+	return components
+}
+
+// addErrorResponses references the standard error responses from op.
+func addErrorResponses(op *openapi3.Operation) {
+	// AddOperation only adds its "No Content" fallback for operations
+	// without any response, which won't be the case anymore.
+	if len(op.Responses.MapOfResponseOrRefValues) == 0 {
+		op.Responses.WithMapOfResponseOrRefValuesItem(strconv.Itoa(http.StatusNoContent), openapi3.ResponseOrRef{
+			Response: &openapi3.Response{
+				Description: http.StatusText(http.StatusNoContent),
+			},
+		})
+	}
+	for _, e := range errorResponses {
+		op.Responses.WithMapOfResponseOrRefValuesItem(strconv.Itoa(e.status), openapi3.ResponseOrRef{
+			ResponseReference: &openapi3.ResponseReference{
+				Ref: "#/components/responses/" + e.name,
+			},
+		})
+	}
+}
+
+// kindSchemas are the schemas of the values of the cmds option types.
+var kindSchemas = map[reflect.Kind]func() *openapi3.Schema{
+	cmds.Bool: func() *openapi3.Schema { return (&openapi3.Schema{}).WithType(openapi3.SchemaTypeBoolean) },
+	cmds.Int:  func() *openapi3.Schema { return (&openapi3.Schema{}).WithType(openapi3.SchemaTypeInteger) },
+	cmds.Uint: func() *openapi3.Schema {
+		return (&openapi3.Schema{}).WithType(openapi3.SchemaTypeInteger).WithMinimum(0)
+	},
+	cmds.Int64: func() *openapi3.Schema {
+		return (&openapi3.Schema{}).WithType(openapi3.SchemaTypeInteger).WithFormat("int64")
+	},
+	cmds.Uint64: func() *openapi3.Schema {
+		return (&openapi3.Schema{}).WithType(openapi3.SchemaTypeInteger).WithFormat("int64").WithMinimum(0)
+	},
+	cmds.Float: func() *openapi3.Schema {
+		return (&openapi3.Schema{}).WithType(openapi3.SchemaTypeNumber).WithFormat("double")
+	},
+	cmds.String: func() *openapi3.Schema { return (&openapi3.Schema{}).WithType(openapi3.SchemaTypeString) },
+	cmds.Strings: func() *openapi3.Schema {
+		return (&openapi3.Schema{}).WithType(openapi3.SchemaTypeArray).
+			WithItems(openapi3.SchemaOrRef{Schema: (&openapi3.Schema{}).WithType(openapi3.SchemaTypeString)})
+	},
+}
+
+// parseDefault parses the default value of an argument without
+// DefaultValue, e.g. built by hand, according to its kind.
+func parseDefault(kind reflect.Kind, def string) (any, error) {
+	switch kind {
+	case cmds.Bool:
+		return strconv.ParseBool(def)
+	case cmds.Int, cmds.Int64:
+		return strconv.ParseInt(def, 10, 64)
+	case cmds.Uint, cmds.Uint64:
+		return strconv.ParseUint(def, 10, 64)
+	case cmds.Float:
+		return strconv.ParseFloat(def, 64)
+	default:
+		return def, nil
+	}
+}
+
+func genParameterForArgument(w *warnings, arg *Argument, aliasToArg bool) *openapi3.Parameter {
+	if arg.Type == "file" {
+		// This will be the request body.
+		return nil
+	}
+	kind := argumentKind(arg)
+	newSchema, ok := kindSchemas[kind]
+	if !ok {
+		w.warnf("Unsupported type for argument %s: %s", arg.Name, arg.Type)
+		newSchema = kindSchemas[cmds.String]
+	}
+	schema := *newSchema()
+	t := *schema.Type
+	if len(arg.Enum) > 0 {
+		enumSchema := &schema
+		if schema.Items != nil {
+			enumSchema = schema.Items.Schema
 		}
+		for _, v := range arg.Enum {
+			var value any = v
+			if *enumSchema.Type == openapi3.SchemaTypeInteger {
+				if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+					value = i
+				}
+			}
+			enumSchema.Enum = append(enumSchema.Enum, value)
+		}
+	}
+	if arg.DefaultValue != nil {
+		schema.WithDefault(arg.DefaultValue)
+	} else if arg.Default != "" {
+		d, err := parseDefault(kind, arg.Default)
 		if err != nil {
-			fmt.Println("WARN: Couldn't parse default value for " + arg.Name)
+			w.warnf("Couldn't parse default value for %s: %s", arg.Name, arg.Default)
 			d = arg.Default
 		}
 		schema.WithDefault(d)
 	}
+	if unit := optionUnit(arg); unit != "" {
+		withUnit(&schema, unit)
+	}
+	schemaOrRef := &openapi3.SchemaOrRef{Schema: &schema}
+	if name := sharedArgumentSchema(arg); name != "" && len(arg.Enum) == 0 && arg.Default == "" {
+		switch t {
+		case openapi3.SchemaTypeString:
+			schemaOrRef = schemaRef(name)
+		case openapi3.SchemaTypeArray:
+			schema.Items = schemaRef(name)
+		}
+	}
 	alias := arg.Name
 	if aliasToArg {
 		alias = "arg"
 	}
 	description := strings.TrimSuffix(arg.Description, " Default: "+arg.Default+".")
+	if human := humanizeDefault(arg); human != "" {
+		// The raw value is in the schema, so only add what it means.
+		description += " Default: " + human + "."
+	}
 	p := openapi3.Parameter{
 		Name:        alias,
 		In:          openapi3.ParameterInQuery,
 		Description: &description,
-		Schema:      &openapi3.SchemaOrRef{Schema: &schema},
+		Schema:      schemaOrRef,
 		Content:     nil,
 		//Required: &arg.Required,
 	}
 	if arg.Required {
 		p.Required = &arg.Required
 	}
-	if strings.Contains(arg.Description, "(experimental)") {
-		if p.MapOfAnything == nil {
-			p.MapOfAnything = make(map[string]interface{})
+	if arg.Group != "" {
+		p.WithMapOfAnythingItem("x-parameter-group", arg.Group)
+	}
+	if deprecated, ext := statusMetadata(arg.Status); ext != nil {
+		p.Deprecated = deprecated
+		for k, v := range ext {
+			p.WithMapOfAnythingItem(k, v)
 		}
-		p.MapOfAnything["x-experimental"] = true
 	}
-	if strings.Contains(arg.Description, "(DEPRECATED)") || strings.HasPrefix(arg.Description, "Removed, ") {
-		d := true
-		p.Deprecated = &d
+	if lifecycle, ok := optionLifecycles[arg.Endpoint][arg.Name]; ok && (arg.Status == cmds.Deprecated || arg.Status == cmds.Removed) {
+		if lifecycle.DeprecatedSince != "" {
+			p.WithMapOfAnythingItem("x-deprecated-since", lifecycle.DeprecatedSince)
+		}
+		if lifecycle.RemovedIn != "" {
+			p.WithMapOfAnythingItem("x-removed-in", lifecycle.RemovedIn)
+		}
 	}
 	return &p
 }
 
-func genParameterForMultiArgument(args []*Argument) *openapi3.Parameter {
+// statusMetadata returns the metadata of operations and parameters having
+// the given status: whether they are deprecated, and the x-status,
+// x-experimental and x-removed extensions. x-removed tells deprecated
+// commands, still served, from removed ones. Both are nil for active ones.
+func statusMetadata(status cmds.Status) (deprecated *bool, extensions map[string]any) {
+	if status == cmds.Active {
+		return nil, nil
+	}
+	extensions = map[string]any{"x-status": strings.ToLower(statusName(status))}
+	switch status {
+	case cmds.Experimental:
+		extensions["x-experimental"] = true
+	case cmds.Deprecated, cmds.Removed:
+		deprecated = ptr(true)
+		extensions["x-removed"] = status == cmds.Removed
+	}
+	return deprecated, extensions
+}
+
+func genParameterForMultiArgument(w *warnings, args []*Argument) *openapi3.Parameter {
 	params := []*openapi3.Parameter{}
 	defaults := []any{}
 	anyDefault := false
@@ -123,29 +519,28 @@ func genParameterForMultiArgument(args []*Argument) *openapi3.Parameter {
 	deprecated := false
 	required := false
 	for i, arg := range args {
-		p := genParameterForArgument(arg, false)
+		p := genParameterForArgument(w, arg, false)
 		d := "arg" + strconv.Itoa(i) + " (" + p.Name + "): " + strings.TrimSpace(*p.Description)
 		p.Description = &d
 		descriptions = append(descriptions, d)
 		params = append(params, p)
-		defaults = append(defaults, p.Schema.Schema.Default)
-		anyDefault = anyDefault || p.Schema.Schema.Default != nil
+		var def *any
+		if p.Schema.Schema != nil {
+			def = p.Schema.Schema.Default
+		}
+		defaults = append(defaults, def)
+		anyDefault = anyDefault || def != nil
 		deprecated = deprecated || (p.Deprecated != nil && *p.Deprecated)
 		required = p.Required != nil && *p.Required
 	}
 
 	t := openapi3.SchemaTypeArray
-	t2 := openapi3.SchemaTypeString //FIXME use actual types of params
 	num := int64(len(params))
 	schema := openapi3.Schema{
 		Type:     &t,
 		MinItems: &num,
 		MaxItems: &num,
-		Items: &openapi3.SchemaOrRef{
-			Schema: &openapi3.Schema{
-				Type: &t2,
-			},
-		},
+		Items:    genMultiArgumentItems(params),
 	}
 	if anyDefault {
 		schema.WithDefault(defaults)
@@ -168,49 +563,85 @@ func genParameterForMultiArgument(args []*Argument) *openapi3.Parameter {
 	if deprecated {
 		p.Deprecated = &deprecated
 	}
+	names := make([]string, len(args))
+	for i, arg := range args {
+		names[i] = arg.Name
+	}
+	// The position of each argument, which the description only has as
+	// text.
+	p.WithMapOfAnythingItem("x-arg-names", names)
 	return &p
 }
 
-func genSchemaForResponse(x any) *openapi3.Schema {
+// genMultiArgumentItems returns the schema of the items of the array of
+// several arguments: the schema of the arguments if they share it, or else
+// any of them. OpenAPI 3.0 can't give a schema per position (prefixItems),
+// see x-arg-names for the position of each argument.
+func genMultiArgumentItems(params []*openapi3.Parameter) *openapi3.SchemaOrRef {
+	var items []openapi3.SchemaOrRef
+	seen := make(map[string]bool)
+	for _, p := range params {
+		item := *p.Schema
+		if item.Schema != nil {
+			// Defaults are listed in the default of the array.
+			s := *item.Schema
+			s.Default = nil
+			item.Schema = &s
+		}
+		key, err := json.Marshal(item)
+		if err != nil || seen[string(key)] {
+			continue
+		}
+		seen[string(key)] = true
+		items = append(items, item)
+	}
+	if len(items) == 1 {
+		return &items[0]
+	}
+	return &openapi3.SchemaOrRef{Schema: &openapi3.Schema{AnyOf: items}}
+}
+
+func genSchemaForResponse(w *warnings, types TypeMap, x any) *openapi3.Schema {
+	return inlineSchema(genSchemaOrRefForResponse(w, types, x, false))
+}
+
+// genSchemaOrRefForResponse returns the schema of a documented response,
+// with the placeholders of types overriding the built-in ones and the time
+// formats. With shared, the placeholders of identifiers reference the shared
+// component schemas (see PlaceholderType.Schema), which the document must
+// then have.
+func genSchemaOrRefForResponse(w *warnings, types TypeMap, x any, shared bool) *openapi3.SchemaOrRef {
 	switch v := x.(type) {
 	case string:
-		var t openapi3.SchemaType
-		switch v {
-		case "<bool>":
-			t = openapi3.SchemaTypeBoolean
-		case "<int>", "<uint>", "<int32>", "<uint32>", "<int64>", "<uint64>", "<duration-ns>", "<timestamp>":
-			t = openapi3.SchemaTypeInteger
-		case "<float32>", "<float64>":
-			t = openapi3.SchemaTypeNumber
-		case "<string>", "<peer-id>", "peer-id", "<cid-string>", "<multiaddr-string>":
-			t = openapi3.SchemaTypeString
-		case "<array>":
-			t = openapi3.SchemaTypeArray
-		case "<object>":
-			t = openapi3.SchemaTypeObject
-		default:
-			log.Printf("WARN: Unsupported type for response: %s\n", v)
-			return nil
+		if _, overridden := types[v]; !overridden {
+			if format, ok := DefaultTimeFormats[v]; ok {
+				return &openapi3.SchemaOrRef{Schema: timeSchema(format)}
+			}
 		}
-		schema := openapi3.Schema{
-			Type: &t,
+		t, ok := types.lookup(v)
+		if ok && t.Schema != "" && shared {
+			return schemaRef(t.Schema)
 		}
-		return &schema
+		if !ok {
+			w.warnf("Unsupported type for response: %s", v)
+			return nil
+		}
+		return &openapi3.SchemaOrRef{Schema: t.schema()}
 	case []any:
-		var itemType *openapi3.Schema
+		var itemType *openapi3.SchemaOrRef
 		if len(v) == 1 {
-			itemType = genSchemaForResponse(v[0])
+			itemType = genSchemaOrRefForResponse(w, types, v[0], shared)
 		}
 		if itemType == nil {
-			log.Println("WARN: Couldn't determine item type of array")
-			itemType = &openapi3.Schema{} // allow any
+			w.warnf("Couldn't determine item type of array")
+			itemType = &openapi3.SchemaOrRef{Schema: &openapi3.Schema{}} // allow any
 		}
 		t := openapi3.SchemaTypeArray
 		schema := openapi3.Schema{
 			Type:  &t,
-			Items: &openapi3.SchemaOrRef{Schema: itemType},
+			Items: itemType,
 		}
-		return &schema
+		return &openapi3.SchemaOrRef{Schema: &schema}
 	case map[string]any:
 		var firstKey string
 		var firstValue any
@@ -221,55 +652,126 @@ func genSchemaForResponse(x any) *openapi3.Schema {
 		}
 
 		if len(v) == 1 && firstKey == "<string>" {
-			var itemType *openapi3.Schema
+			var itemType *openapi3.SchemaOrRef
 			if len(v) == 1 {
-				itemType = genSchemaForResponse(firstValue)
+				itemType = genSchemaOrRefForResponse(w, types, firstValue, shared)
 			}
 			if itemType == nil {
-				log.Println("WARN: Couldn't determine item type of object")
-				itemType = &openapi3.Schema{} // allow any
+				w.warnf("Couldn't determine item type of object")
+				itemType = &openapi3.SchemaOrRef{Schema: &openapi3.Schema{}} // allow any
 			}
 
 			t := openapi3.SchemaTypeObject
 			schema := openapi3.Schema{
 				Type: &t,
 				AdditionalProperties: &openapi3.SchemaAdditionalProperties{
-					SchemaOrRef: &openapi3.SchemaOrRef{Schema: itemType},
+					SchemaOrRef: itemType,
 				},
 			}
-			return &schema
+			return &openapi3.SchemaOrRef{Schema: &schema}
 		} else {
+			// Visit the properties in order, so that warnings are
+			// reported in the same order on every run.
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
 			ps := map[string]openapi3.SchemaOrRef{}
-			for k, v := range v {
-				s := genSchemaForResponse(v)
+			for _, k := range keys {
+				s := genSchemaOrRefForResponse(w, types, v[k], shared)
 				if s == nil {
-					s = &openapi3.Schema{} // allow any
+					s = &openapi3.SchemaOrRef{Schema: &openapi3.Schema{}} // allow any
 				}
-				ps[k] = openapi3.SchemaOrRef{Schema: s}
+				ps[k] = *s
 			}
 			t := openapi3.SchemaTypeObject
 			schema := openapi3.Schema{
 				Type:       &t,
 				Properties: ps,
 			}
-			return &schema
+			return &openapi3.SchemaOrRef{Schema: &schema}
 		}
 	default:
-		log.Printf("WARN: Unsupported type for argument: %s\n", v)
+		w.warnf("Unsupported type for response: %v", v)
 		return nil
 	}
 }
 
-func (myself *OpenAPIFormatter) GenerateEndpoint(endp *Endpoint) error {
-	id := strings.TrimPrefix(endp.Name, "/api/v0/")
-	refname := strings.Replace(strings.TrimPrefix(endp.Name, "/"), "/", "-", -1)
+// mimeNDJSON is the media type of streamed responses: newline-delimited
+// JSON objects, each matching the response schema.
+const mimeNDJSON = "application/x-ndjson"
+
+func successDescription(endp *Endpoint) string {
+	if endp.LongPoll != nil && endp.LongPoll.Option == "" {
+		return "Successful response, streamed as newline-delimited JSON objects until " + endp.LongPoll.Until
+	}
+	if endp.Streaming {
+		return "Successful response, streamed as newline-delimited JSON objects"
+	}
+	if endp.StreamingOption != "" {
+		return fmt.Sprintf("Successful response: a single JSON object by default, or newline-delimited JSON objects streamed with the `%s` option", endp.StreamingOption)
+	}
+	return "Successful response"
+}
+
+// GenerateEndpoint adds the operation of an endpoint to the spec.
+func (myself *OpenAPIFormatter) GenerateEndpoint(ctx context.Context, endp *Endpoint) error {
+	w := &warnings{endpoint: endp.Name}
+	defer func() { myself.Warnings = append(myself.Warnings, w.list...) }()
+	op, err := myself.genOperation(ctx, w, endp)
+	if err != nil {
+		return err
+	}
+	return myself.addOperation(op)
+}
+
+// endpointOperation is the operation of an endpoint, with the component
+// schemas it references, which addOperation adds to the spec.
+type endpointOperation struct {
+	name string
+	op   openapi3.Operation
+	// schemas are the component schemas of the response, by name (see
+	// namedSchema).
+	schemas map[string]*openapi3.Schema
+}
+
+// genOperation generates the operation of an endpoint. It doesn't modify
+// the spec, so that Build can generate several endpoints concurrently.
+func (myself *OpenAPIFormatter) genOperation(ctx context.Context, w *warnings, endp *Endpoint) (*endpointOperation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	schemas := make(map[string]*openapi3.Schema)
+
+	id := myself.operationID(endp.Name)
 	op := openapi3.Operation{
 		ID: &id,
 		ExternalDocs: &openapi3.ExternalDocumentation{
-			URL: "https://docs.ipfs.tech/reference/kubo/rpc/#" + refname,
+			URL: myself.docsURL(endp.Name),
 		},
+		Summary:     &endp.Description,
 		Description: &endp.Description,
 	}
+	if endp.LongDescription != "" {
+		op.Description = ptr(helptextMarkdown(endp.LongDescription))
+	}
+	if help := parentHelpMarkdown(endp, myself.ParentHelp); help != "" {
+		if myself.ParentHelp == ParentHelpPrepend {
+			op.Description = ptr(help + "\n\n" + *op.Description)
+		} else {
+			op.Description = ptr(*op.Description + "\n\n" + help)
+		}
+	}
+	if deprecated, ext := statusMetadata(endp.Status); ext != nil {
+		op.Deprecated = deprecated
+		for k, v := range ext {
+			op.WithMapOfAnythingItem(k, v)
+		}
+	}
+	if v := myself.minVersion(endp.Name, ""); v != "" {
+		op.WithMapOfAnythingItem("x-kubo-min-version", v)
+	}
 
 	bodyArgs := []*Argument{}
 	otherArgs := []*Argument{}
@@ -281,144 +783,483 @@ func (myself *OpenAPIFormatter) GenerateEndpoint(endp *Endpoint) error {
 		}
 	}
 	if len(otherArgs) > 1 {
-		p := genParameterForMultiArgument(otherArgs)
+		p := genParameterForMultiArgument(w, otherArgs)
 		op.Parameters = append(op.Parameters, p.ToParameterOrRef())
 	} else {
 		//log.Println("FIXME: Special case for " + endp.Name + ": Multiple arguments `arg`. This should become an array.")
 		for _, arg := range otherArgs {
-			p := genParameterForArgument(arg, len(otherArgs) <= 1)
+			p := genParameterForArgument(w, arg, len(otherArgs) <= 1)
 			op.Parameters = append(op.Parameters, p.ToParameterOrRef())
 		}
 	}
 	for _, arg := range endp.Options {
-		p := genParameterForArgument(arg, false)
+		p := genParameterForArgument(w, arg, false)
+		if v := myself.minVersion(endp.Name, arg.Name); v != "" {
+			p.WithMapOfAnythingItem("x-kubo-min-version", v)
+		}
 		op.Parameters = append(op.Parameters, p.ToParameterOrRef())
 	}
 
-	if len(bodyArgs) > 0 {
-		rb := openapi3.RequestBody{}
-
-		// This spec uses the generated description, so let's do the same, for now.
-		// https://app.swaggerhub.com/apis/powerpeaks/ipfs/1
-		description := myself.md.GenerateBodyBlock(bodyArgs)
-		description = strings.TrimSpace(description)
-		description = strings.TrimPrefix(description, "### Request Body\n\n")
-		rb.Description = &description
+	for _, p := range op.Parameters {
+		myself.setProvenance(p.Parameter.Schema.Schema, ProvenanceOption)
+	}
+	op.Parameters = append(op.Parameters, myself.globalParameterRefs(endp)...)
 
-		object := openapi3.SchemaTypeObject
-		array := openapi3.SchemaTypeArray
-		string_t := openapi3.SchemaTypeString
-		binary := "binary"
-		rb.WithContentItem("multipart/form-data", openapi3.MediaType{
-			// see https://swagger.io/docs/specification/describing-request-body/file-upload/
-			Schema: &openapi3.SchemaOrRef{Schema: &openapi3.Schema{
-				Type: &object,
-				Properties: map[string]openapi3.SchemaOrRef{
-					bodyArgs[0].Name: openapi3.SchemaOrRef{
-						Schema: &openapi3.Schema{
-							Type: &array,
-							Items: &openapi3.SchemaOrRef{
-								Schema: &openapi3.Schema{
-									Type:   &string_t,
-									Format: &binary,
-								},
-							},
-						},
-					},
-				},
-			}},
-		})
+	if len(bodyArgs) > 0 {
+		op.WithRequestBody(openapi3.RequestBodyOrRef{RequestBody: myself.genRequestBody(endp, bodyArgs)})
+	}
 
-		for _, arg := range bodyArgs {
-			if arg.Required {
-				rb.Required = &arg.Required
-			}
-		}
-		op.WithRequestBody(openapi3.RequestBodyOrRef{RequestBody: &rb})
+	if myself.CodeSamples {
+		op.WithMapOfAnythingItem("x-codeSamples", codeSamples(endp, myself.exampleOptions(endp.Name)))
+	}
+	if cmd := cliCommand(endp.Name); cmd != "" {
+		op.WithMapOfAnythingItem("x-cli-command", cmd)
+	}
+	if endp.Streaming {
+		op.WithMapOfAnythingItem("x-ipfs-streaming", true)
+	}
+	if len(endp.AsyncEffects) > 0 {
+		op.WithMapOfAnythingItem("x-async-effects", myself.genAsyncEffects(endp.AsyncEffects))
+	}
+	if endp.LongPoll != nil {
+		op.WithMapOfAnythingItem("x-long-poll", myself.genLongPoll(w, schemas, endp))
+	}
+	if _, ok := eventStreams[endp.Name]; ok {
+		// The events are described by the AsyncAPI document.
+		op.WithMapOfAnythingItem("x-asyncapi-channel", asyncAPIChannel(endp.Name))
 	}
 
-	if endp.Response == "This endpoint returns a `text/plain` response body." {
+	if endp.Response == textResponse {
 		textBody := openapi3.MediaType{}
+		mimeText := "text/plain"
+		if endp.Streaming {
+			// Streams of JSON objects without a declared type.
+			mimeText = mimeNDJSON
+			textBody.WithSchema(openapi3.SchemaOrRef{Schema: &openapi3.Schema{}})
+		}
+		if example, ok := myself.Examples[endp.Name]; ok {
+			textBody.Examples = myself.recordedExamples(example)
+		}
 		resp := openapi3.Response{
-			Description: "Successful response",
+			Description: successDescription(endp),
 			Content: map[string]openapi3.MediaType{
-				"text/plain": textBody,
+				mimeText: textBody,
 			},
 		}
+		addCodecMediaTypes(&resp, endp)
+		addResponseHeaders(&resp, endp)
 		op.Responses.WithMapOfResponseOrRefValues(map[string]openapi3.ResponseOrRef{
 			"200": {Response: &resp},
 		})
 	} else if endp.Response != "" {
 		mimeJSON := "application/json"
+		if endp.Streaming {
+			mimeJSON = mimeNDJSON
+		}
 		//var responseJson map[string]any
 		var responseJson any
 		err := json.Unmarshal([]byte(endp.Response), &responseJson)
 		if err != nil {
-			log.Println("Couldn't parse JSON for Response:", err, "; JSON:", endp.Response)
+			w.warnf("Couldn't parse JSON for Response: %s; JSON: %s", err, endp.Response)
 		} else {
 			//log.Println("Response:", endp.Response)
 			//example := map[string]string{}
 			//example["bla"] = "blub"
 			jsonBody := openapi3.MediaType{}
-			jsonBody.WithExample(responseJson)
+			schema := applyPresence(endp.ResponseFields, "", responseJson, applyUnits(endp.Name, "", responseJson, myself.applyTimeFormats(endp.Name, "", responseJson, genSchemaOrRefForResponse(w, myself.TypeMap, responseJson, true))))
+			if example, ok := myself.Examples[endp.Name]; ok {
+				jsonBody.Examples = myself.recordedExamples(example)
+				checkExample(w, "recorded", schema, example.Response)
+			} else {
+				// The placeholders of the documented response would
+				// contradict the schema, e.g. "<int64>" for an integer.
+				value := exampleValue(myself.TypeMap, responseJson, schema)
+				if external, ok := myself.externalExample(endp.Name, "", value); ok {
+					jsonBody.Examples = map[string]openapi3.ExampleOrRef{"documented": external}
+				} else {
+					jsonBody.WithExample(value)
+				}
+				checkExample(w, "documented", schema, value)
+			}
 
-			schema := genSchemaForResponse(responseJson)
-			if schema != nil {
-				jsonBody.WithSchema(openapi3.SchemaOrRef{Schema: schema})
+			provenance := ProvenancePlaceholder
+			if variants := myself.genResponseVariants(w, endp); variants != nil {
+				schema, provenance = variants, ProvenanceManual
+			} else if events := myself.genProgressEvents(w, schemas, endp); events != nil {
+				schema, provenance = events, ProvenanceManual
+			}
+			if schema != nil && schema.Schema != nil {
+				myself.setProvenance(schema.Schema, provenance)
+				jsonBody.WithSchema(myself.namedSchema(schemas, endp, schema.Schema))
+			} else if schema != nil {
+				jsonBody.WithSchema(*schema)
 			}
 
 			resp := openapi3.Response{
-				Description: "Successful response",
+				Description: successDescription(endp),
 				Content: map[string]openapi3.MediaType{
 					mimeJSON: jsonBody,
 				},
 			}
+			addEncodingMediaTypes(&resp, endp)
+			addResponseHeaders(&resp, endp)
+			myself.addLinks(&resp, endp)
 			op.Responses.WithMapOfResponseOrRefValues(map[string]openapi3.ResponseOrRef{
 				"200": {Response: &resp},
 			})
 		}
 	}
 
-	return myself.spec.AddOperation(http.MethodPost, endp.Name, op)
+	addErrorResponses(&op)
+	if myself.Security {
+		// Any of the schemes is accepted.
+		for _, scheme := range securitySchemes {
+			op.Security = append(op.Security, map[string][]string{scheme: {}})
+		}
+	}
+
+	return &endpointOperation{name: endp.Name, op: op, schemas: schemas}, nil
 }
 
-func (myself *OpenAPIFormatter) Generate(api []*Endpoint) error {
-	myself.GenerateMetadata()
+// addOperation adds an operation generated by genOperation to the spec,
+// with its component schemas. A schema already added by another operation
+// is kept.
+func (myself *OpenAPIFormatter) addOperation(o *endpointOperation) error {
+	if len(o.schemas) > 0 {
+		components := myself.spec.ComponentsEns().SchemasEns()
+		for name, schema := range o.schemas {
+			if _, exists := components.MapOfSchemaOrRefValues[name]; !exists {
+				components.WithMapOfSchemaOrRefValuesItem(name, openapi3.SchemaOrRef{Schema: schema})
+			}
+		}
+	}
+	return myself.spec.AddOperation(http.MethodPost, o.name, o.op)
+}
+
+// addEncodingMediaTypes adds to a JSON response the other media types it
+// can be requested in with the encoding parameter.
+func addEncodingMediaTypes(resp *openapi3.Response, endp *Endpoint) {
+	var encodings []string
+	for _, enc := range endp.Encodings {
+		mime := encodingMIMEType(enc)
+		if _, ok := resp.Content[mime]; ok || enc == cmds.JSON {
+			// JSON is the default, documented by the caller.
+			continue
+		}
+		encodings = append(encodings, "`"+enc+"`")
+		resp.Content[mime] = openapi3.MediaType{
+			Schema: &openapi3.SchemaOrRef{Schema: (&openapi3.Schema{}).WithType(openapi3.SchemaTypeString)},
+		}
+	}
+	if len(encodings) > 0 {
+		resp.Description += fmt.Sprintf(". Other encodings (%s) are requested with the `encoding` parameter", strings.Join(encodings, ", "))
+	}
+}
 
-	for _, status := range []cmds.Status{cmds.Active, cmds.Experimental, cmds.Deprecated, cmds.Removed} {
-		endpoints := InStatus(api, status)
-		if len(endpoints) == 0 {
+// addCodecMediaTypes adds to a text response the media type of its format
+// (see binaryResponses), or the media types of the codecs that can be
+// selected with an option, one for each value of its enum (see
+// codecOptions).
+func addCodecMediaTypes(resp *openapi3.Response, endp *Endpoint) {
+	if mime, ok := binaryResponses[endp.Name]; ok {
+		resp.Content[mime] = openapi3.MediaType{Schema: &openapi3.SchemaOrRef{Schema: binarySchema()}}
+		resp.Description += fmt.Sprintf(". The body is in the %s format, although the Content-Type header is `text/plain`", mime)
+		return
+	}
+	codec, ok := codecOptions[endp.Name]
+	if !ok {
+		return
+	}
+	names := codec.names()
+	for _, opt := range endp.Options {
+		if opt.Name == codec.Option && len(opt.Enum) > 0 {
+			names = opt.Enum
+		}
+	}
+	var listed []string
+	for _, name := range names {
+		mime, ok := codec.MIMETypes[name]
+		if !ok {
 			continue
 		}
-		for _, endp := range endpoints {
-			err := myself.GenerateEndpoint(endp)
-			if err != nil {
-				return err
+		schema := binarySchema()
+		if strings.HasSuffix(mime, "json") {
+			// Any value, as for streams of JSON objects without a
+			// declared type.
+			schema = &openapi3.Schema{}
+		}
+		resp.Content[mime] = openapi3.MediaType{Schema: &openapi3.SchemaOrRef{Schema: schema}}
+		listed = append(listed, fmt.Sprintf("`%s` (%s)", name, mime))
+	}
+	resp.Description += fmt.Sprintf(". The body is encoded with the codec selected by `%s`: %s. The Content-Type header is `text/plain` for all of them", codec.Option, strings.Join(listed, ", "))
+}
+
+// names returns the names of the codecs, sorted.
+func (codec codecOption) names() []string {
+	names := make([]string, 0, len(codec.MIMETypes))
+	for name := range codec.MIMETypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// binarySchema is the schema of bytes.
+func binarySchema() *openapi3.Schema {
+	return (&openapi3.Schema{}).WithType(openapi3.SchemaTypeString).WithFormat("binary")
+}
+
+// genRequestBody returns the multipart/form-data body carrying the file
+// arguments of an endpoint. Whatever the name of the argument, the parts are
+// named "file", as the ones sent by kubo-rpc-client, with one part per file
+// for variadic arguments.
+func (myself *OpenAPIFormatter) genRequestBody(endp *Endpoint, bodyArgs []*Argument) *openapi3.RequestBody {
+	rb := openapi3.RequestBody{}
+
+	// This spec uses the generated description, so let's do the same.
+	// https://app.swaggerhub.com/apis/powerpeaks/ipfs/1
+	if arg := fileArgument(bodyArgs); arg != nil {
+		description := bodyDescription(arg)
+		rb.Description = &description
+	}
+
+	object := openapi3.SchemaTypeObject
+	array := openapi3.SchemaTypeArray
+	string_t := openapi3.SchemaTypeString
+	binary := "binary"
+	partDescription := "Parts are sent with `Content-Disposition: form-data; name=\"file\"; filename=\"<url-escaped path>\"`."
+	if directoryUploadEndpoints[endp.Name] {
+		partDescription += " Directories are parts with `Content-Type: application/x-directory` and no content."
+	}
+
+	// see https://swagger.io/docs/specification/describing-request-body/file-upload/
+	schema := &openapi3.Schema{
+		Type:       &object,
+		Properties: map[string]openapi3.SchemaOrRef{},
+	}
+	encoding := map[string]openapi3.Encoding{}
+	raw, isRaw := rawBodyEndpoints[endp.Name]
+	if isRaw {
+		partDescription = raw.Description + " The part is sent with `Content-Disposition: form-data; name=\"file\"`; its filename is ignored."
+	}
+	for _, arg := range bodyArgs {
+		file := &openapi3.Schema{
+			Type:   &string_t,
+			Format: &binary,
+		}
+		const part = "file"
+		if isRaw {
+			encoding[part] = openapi3.Encoding{ContentType: &raw.MediaType}
+		}
+		prop := file
+		if arg.Variadic {
+			prop = &openapi3.Schema{
+				Type:  &array,
+				Items: &openapi3.SchemaOrRef{Schema: file},
+			}
+		}
+		argDescription := strings.TrimSpace(arg.Description)
+		if argDescription != "" && !strings.HasSuffix(argDescription, ".") {
+			argDescription += "."
+		}
+		argDescription = strings.TrimSpace(argDescription + " " + partDescription)
+		prop.Description = &argDescription
+		schema.Properties[part] = openapi3.SchemaOrRef{Schema: prop}
+		if arg.Required {
+			schema.Required = append(schema.Required, part)
+			rb.Required = &arg.Required
+		}
+
+		if directoryUploadEndpoints[endp.Name] {
+			abspath := "Location of the file in the filesystem (within the IPFS root), or its full web URL, for the filestore and urlstore (`nocopy` option)."
+			encoding[part] = openapi3.Encoding{
+				ContentType: ptr("application/octet-stream, application/x-directory"),
+				Headers: map[string]openapi3.Header{
+					"Abspath": {
+						Description: &abspath,
+						Schema:      &openapi3.SchemaOrRef{Schema: &openapi3.Schema{Type: &string_t}},
+					},
+				},
 			}
 		}
 	}
 
-	return nil
+	media := openapi3.MediaType{Schema: &openapi3.SchemaOrRef{Schema: schema}}
+	if len(encoding) > 0 {
+		media.Encoding = encoding
+	}
+	media.Examples = genMultipartExamples(endp.Name)
+	rb.WithContentItem("multipart/form-data", media)
+	return &rb
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// genLongPoll describes a long-polling endpoint for x-long-poll: when the
+// connection is closed and the schema of the messages sent until then.
+func (myself *OpenAPIFormatter) genLongPoll(w *warnings, schemas map[string]*openapi3.Schema, endp *Endpoint) map[string]any {
+	longPoll := map[string]any{
+		"heldOpen":   true,
+		"closedWhen": endp.LongPoll.Until,
+	}
+	if endp.LongPoll.Option != "" {
+		longPoll["option"] = endp.LongPoll.Option
+	}
+	payload := eventStreams[endp.Name].Payload
+	if payload == "" && endp.Response != textResponse {
+		payload = endp.Response
+	}
+	var message any
+	if payload == "" || json.Unmarshal([]byte(payload), &message) != nil {
+		return longPoll
+	}
+	if schema := applyUnits(endp.Name, "", message, myself.applyTimeFormats(endp.Name, "", message, genSchemaOrRefForResponse(w, myself.TypeMap, message, true))); schema != nil && schema.Schema != nil {
+		longPoll["messageSchema"] = myself.namedSchema(schemas, endp, schema.Schema)
+	} else if schema != nil {
+		longPoll["messageSchema"] = schema
+	}
+	return longPoll
+}
+
+// genAsyncEffects returns the value of the x-async-effects extension. Each
+// follow-up call links to its operation, like OpenAPI links do.
+func (myself *OpenAPIFormatter) genAsyncEffects(effects []AsyncEffect) []map[string]any {
+	var out []map[string]any
+	for _, effect := range effects {
+		var next []map[string]any
+		for _, f := range effect.Next {
+			next = append(next, map[string]any{
+				"action":       f.Action,
+				"operationId":  myself.operationID(f.Endpoint),
+				"operationRef": "#/paths/" + strings.ReplaceAll(f.Endpoint, "/", "~1") + "/post",
+				"description":  f.Description,
+			})
+		}
+		out = append(out, map[string]any{
+			"description": effect.Description,
+			"next":        next,
+		})
+	}
+	return out
+}
+
+// operationID returns the operation ID of an endpoint, computed by Build
+// for all the endpoints at once to make them unique.
+func (myself *OpenAPIFormatter) operationID(name string) string {
+	if id, ok := myself.operationIDs[name]; ok {
+		return id
+	}
+	id, err := operationID(name, myself.OperationIDStyle)
+	if err != nil {
+		id, _ = operationID(name, OperationIDSlash)
+	}
+	return id
 }
 
-// GenerateDocs uses a formatter to generate documentation for every endpoint
-func GenerateOpenAPI(api []*Endpoint, formatter OpenAPIFormatter) string {
-	err := formatter.Generate(api)
+// namedSchema adds the response schema of an endpoint to schemas, as a
+// component schema named after its Go type, and returns a reference to it.
+// Schemas of responses without a named type are returned as they are.
+func (myself *OpenAPIFormatter) namedSchema(schemas map[string]*openapi3.Schema, endp *Endpoint, schema *openapi3.Schema) openapi3.SchemaOrRef {
+	name, ok := myself.schemaNames[endp.ResponseType]
+	if !ok {
+		return openapi3.SchemaOrRef{Schema: schema}
+	}
+	if _, exists := schemas[name]; !exists {
+		schemas[name] = schema
+	}
+	return openapi3.SchemaOrRef{SchemaReference: &openapi3.SchemaReference{
+		Ref: "#/components/schemas/" + name,
+	}}
+}
+
+// Build adds all the given endpoints to the spec, generating Jobs of them
+// at a time. Endpoints which fail are skipped and reported in Failures,
+// unless in Strict mode, where the first failure is returned, as well as
+// the warnings at the end. Generation stops with the error of the context
+// when it is done.
+func (myself *OpenAPIFormatter) Build(ctx context.Context, api []*Endpoint) error {
+	myself.GenerateMetadata()
+	api, removed, err := splitRemoved(api, myself.Removed)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	if myself.Removed == RemovedAppendix && len(removed) > 0 {
+		myself.spec.Info.WithDescription(*myself.spec.Info.Description + "\n\n" + removedAppendix(removed))
 	}
+	myself.schemaNames = ResponseSchemaNames(api)
+	ids, err := OperationIDs(api, myself.OperationIDStyle)
+	if err != nil {
+		return err
+	}
+	myself.operationIDs = ids
+	if myself.OperationIDStyle != "" && myself.OperationIDStyle != OperationIDSlash {
+		endpoints := make(map[string]string, len(ids))
+		for name, id := range ids {
+			endpoints[id] = name
+		}
+		myself.spec.WithMapOfAnythingItem("x-operation-ids", endpoints)
+	}
+	myself.Failures = nil
+	myself.Warnings = nil
+	myself.globalParameters = myself.genGlobalParameters()
 
-	if false {
-		schema, err := formatter.reflector.Spec.MarshalYAML()
-		if err != nil {
-			log.Fatal(err)
+	var endpoints []*Endpoint
+	for _, status := range AllStatuses {
+		endpoints = append(endpoints, InStatus(api, status)...)
+	}
+	generated := myself.genOperations(ctx, endpoints)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for i, g := range generated {
+		myself.Warnings = append(myself.Warnings, g.warnings...)
+		err := g.err
+		if err == nil {
+			if e := myself.addOperation(g.op); e != nil {
+				err = &EndpointError{Endpoint: endpoints[i].Name, Err: e}
+			}
 		}
-		return string(schema)
-	} else {
-		schema, err := formatter.spec.MarshalYAML()
-		if err != nil {
-			log.Fatal(err)
+		if err == nil {
+			continue
 		}
-		return string(schema)
+		if myself.Strict {
+			return err
+		}
+		log.Printf("WARN: Skipping endpoint %s\n", err)
+		myself.Failures = append(myself.Failures, err)
+	}
+
+	if myself.Overlay != nil {
+		if err := myself.applyOverlay(myself.Overlay); err != nil {
+			return err
+		}
+	}
+
+	if len(myself.Failures) > 0 {
+		log.Printf("WARN: %d endpoints were skipped and are missing from the spec:\n", len(myself.Failures))
+		for _, failure := range myself.Failures {
+			log.Printf("WARN:   %s\n", failure)
+		}
+	}
+	if myself.Strict && len(myself.Warnings) > 0 {
+		return &WarningsError{Warnings: myself.Warnings}
+	}
+	return nil
+}
+
+// SpecYAML returns the spec built by Build as YAML.
+func (myself *OpenAPIFormatter) SpecYAML() (string, error) {
+	schema, err := myself.spec.MarshalYAML()
+	return string(schema), err
+}
+
+// Generate builds the spec of the given endpoints (see Build) and returns
+// it as YAML.
+func (myself *OpenAPIFormatter) Generate(ctx context.Context, api []*Endpoint) (string, error) {
+	if err := myself.Build(ctx, api); err != nil {
+		return "", err
 	}
+	return myself.SpecYAML()
 }
@@ -0,0 +1,323 @@
+package docs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+func TestGenerateSkipsFailingEndpoints(t *testing.T) {
+	api := []*Endpoint{
+		{Name: "/api/v0/good"},
+		// Operations can't be added twice for the same path.
+		{Name: "/api/v0/bad"},
+		{Name: "/api/v0/bad"},
+	}
+
+	formatter := new(OpenAPIFormatter)
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+	if len(formatter.Failures) != 1 || formatter.Failures[0].Endpoint != "/api/v0/bad" {
+		t.Fatalf("expected /api/v0/bad to fail, got %v", formatter.Failures)
+	}
+	if _, ok := formatter.spec.Paths.MapOfPathItemValues["/api/v0/good"]; !ok {
+		t.Errorf("/api/v0/good should still be generated")
+	}
+
+	strict := &OpenAPIFormatter{Strict: true}
+	err := strict.Build(context.Background(), api)
+	var endpErr *EndpointError
+	if !errors.As(err, &endpErr) || endpErr.Endpoint != "/api/v0/bad" {
+		t.Errorf("strict mode should fail with the error of /api/v0/bad, got %v", err)
+	}
+}
+
+func TestGenerateStrictWarnings(t *testing.T) {
+	api := []*Endpoint{
+		{Name: "/api/v0/good", Response: `{"ID": "<string>"}`},
+		{Name: "/api/v0/odd", Response: `{"Things": []}`},
+	}
+	if err := new(OpenAPIFormatter).Build(context.Background(), api); err != nil {
+		t.Fatalf("warnings should only fail in strict mode, got %v", err)
+	}
+
+	strict := &OpenAPIFormatter{Strict: true}
+	err := strict.Build(context.Background(), api)
+	var warnErr *WarningsError
+	if !errors.As(err, &warnErr) || len(warnErr.Warnings) != 1 || warnErr.Warnings[0].Endpoint != "/api/v0/odd" {
+		t.Fatalf("expected a warning for /api/v0/odd, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "1 warning:\n  /api/v0/odd: ") {
+		t.Errorf("unexpected summary %q", err)
+	}
+}
+
+func TestGenerateMetadata(t *testing.T) {
+	formatter := &OpenAPIFormatter{Info: OpenAPIInfo{
+		Version: "1.2.3",
+		Servers: []string{"http://127.0.0.1:5001", "https://rpc.example.com"},
+	}}
+	formatter.GenerateMetadata()
+	spec := formatter.reflector.Spec
+	if spec.Info.Title != "IPFS RPC API" || spec.Info.Version != "1.2.3" {
+		t.Errorf("unexpected info %+v", spec.Info)
+	}
+	if len(spec.Servers) != 2 || spec.Servers[1].URL != "https://rpc.example.com" {
+		t.Errorf("unexpected servers %+v", spec.Servers)
+	}
+}
+
+func TestDefaultServer(t *testing.T) {
+	formatter := &OpenAPIFormatter{Info: OpenAPIInfo{ServerVariables: map[string]string{"port": "5002"}}}
+	formatter.GenerateMetadata()
+	servers := formatter.reflector.Spec.Servers
+	if len(servers) != 1 || servers[0].URL != "http://{host}:{port}" {
+		t.Fatalf("unexpected servers %+v", servers)
+	}
+	if host, port := servers[0].Variables["host"].Default, servers[0].Variables["port"].Default; host != "127.0.0.1" || port != "5002" {
+		t.Errorf("unexpected defaults %s:%s", host, port)
+	}
+}
+
+func TestGenerateCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := new(OpenAPIFormatter).Build(ctx, []*Endpoint{{Name: "/api/v0/id"}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestGenerateSecurity(t *testing.T) {
+	formatter := &OpenAPIFormatter{Security: true}
+	if err := formatter.Build(context.Background(), []*Endpoint{{Name: "/api/v0/id"}}); err != nil {
+		t.Fatal(err)
+	}
+	schemes := formatter.spec.Components.SecuritySchemes.MapOfSecuritySchemeOrRefValues
+	if len(schemes) != 2 || schemes["bearerAuth"].SecurityScheme.HTTPSecurityScheme.Scheme != "bearer" {
+		t.Errorf("unexpected security schemes %+v", schemes)
+	}
+	op := formatter.spec.Paths.MapOfPathItemValues["/api/v0/id"].MapOfOperationValues["post"]
+	if len(op.Security) != 2 {
+		t.Errorf("operations should accept both schemes, got %v", op.Security)
+	}
+}
+
+func TestProvenance(t *testing.T) {
+	api := []*Endpoint{{
+		Name:     "/api/v0/id",
+		Options:  []*Argument{{Name: "format", Type: "string"}},
+		Response: `{"ID": "<string>"}`,
+	}}
+	provenance := func(formatter *OpenAPIFormatter) []any {
+		if err := formatter.Build(context.Background(), api); err != nil {
+			t.Fatal(err)
+		}
+		op := formatter.spec.Paths.MapOfPathItemValues["/api/v0/id"].MapOfOperationValues["post"]
+		return []any{
+			formatter.spec.Components.Schemas.MapOfSchemaOrRefValues[errorSchemaName].Schema.MapOfAnything["x-provenance"],
+			op.Parameters[0].Parameter.Schema.Schema.MapOfAnything["x-provenance"],
+			op.Responses.MapOfResponseOrRefValues["200"].Response.Content["application/json"].Schema.Schema.MapOfAnything["x-provenance"],
+		}
+	}
+
+	got := provenance(new(OpenAPIFormatter))
+	want := []any{"manual", "cmds-option", "doc-placeholder"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got provenance %v, want %v", got, want)
+		}
+	}
+	for _, p := range provenance(&OpenAPIFormatter{StripProvenance: true}) {
+		if p != nil {
+			t.Errorf("provenance should be stripped, got %v", p)
+		}
+	}
+}
+
+func TestSummary(t *testing.T) {
+	api := []*Endpoint{
+		{Name: "/api/v0/id", Description: "Show IPFS node id info.", LongDescription: "Prints out information about the specified peer."},
+		{Name: "/api/v0/cat", Description: "Show IPFS object data."},
+	}
+	formatter := new(OpenAPIFormatter)
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+	id := formatter.spec.Paths.MapOfPathItemValues["/api/v0/id"].MapOfOperationValues["post"]
+	if *id.Summary != "Show IPFS node id info." || *id.Description != "Prints out information about the specified peer." {
+		t.Errorf("unexpected summary %q and description %q", *id.Summary, *id.Description)
+	}
+	cat := formatter.spec.Paths.MapOfPathItemValues["/api/v0/cat"].MapOfOperationValues["post"]
+	if *cat.Summary != "Show IPFS object data." || *cat.Description != "Show IPFS object data." {
+		t.Errorf("the description should fall back to the tagline")
+	}
+}
+
+func TestRequestBody(t *testing.T) {
+	api := []*Endpoint{
+		{Name: "/api/v0/add", Arguments: []*Argument{{Name: "path", Type: "file", Required: true, Variadic: true}}},
+		{Name: "/api/v0/block/put", Arguments: []*Argument{{Name: "data", Type: "file"}}},
+	}
+	formatter := new(OpenAPIFormatter)
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+	body := func(name string) openapi3.MediaType {
+		op := formatter.spec.Paths.MapOfPathItemValues[name].MapOfOperationValues["post"]
+		return op.RequestBody.RequestBody.Content["multipart/form-data"]
+	}
+
+	add := body("/api/v0/add")
+	// The part is named file, as the multipart examples, not after the argument.
+	files := add.Schema.Schema.Properties["file"].Schema
+	if files == nil || *files.Type != openapi3.SchemaTypeArray || !slices.Equal(add.Schema.Schema.Required, []string{"file"}) {
+		t.Errorf("add should take a required array of files named file")
+	}
+	if _, ok := add.Schema.Schema.Properties["path"]; ok {
+		t.Errorf("the part of add should not be named after its argument")
+	}
+	if _, ok := add.Encoding["file"].Headers["Abspath"]; !ok {
+		t.Errorf("add should document the Abspath header")
+	}
+
+	put := body("/api/v0/block/put")
+	if data := put.Schema.Schema.Properties["file"].Schema; data == nil || *data.Type != openapi3.SchemaTypeString || len(put.Schema.Schema.Required) != 0 {
+		t.Errorf("block/put should take a single optional part named file")
+	}
+	if enc := put.Encoding["file"]; enc.ContentType == nil || *enc.ContentType != "application/octet-stream" || enc.Headers != nil {
+		t.Errorf("block/put should take raw bytes, without directories: %+v", enc)
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	api := []*Endpoint{
+		{Name: "/api/v0/b", Response: `{"Z": "<string>", "Odd1": [], "A": {"Odd2": []}}`},
+		{Name: "/api/v0/a", Response: `{"Y": 1, "X": true}`},
+	}
+	var specs, warnings []string
+	for i := 0; i < 5; i++ {
+		formatter := new(OpenAPIFormatter)
+		spec, err := formatter.Generate(context.Background(), api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ws []string
+		for _, w := range formatter.Warnings {
+			ws = append(ws, w.String())
+		}
+		specs = append(specs, spec)
+		warnings = append(warnings, strings.Join(ws, "\n"))
+	}
+	for i := 1; i < len(specs); i++ {
+		if specs[i] != specs[0] {
+			t.Errorf("spec changed between runs:\n%s\n%s", specs[0], specs[i])
+		}
+		if warnings[i] != warnings[0] {
+			t.Errorf("warnings changed between runs:\n%s\n%s", warnings[0], warnings[i])
+		}
+	}
+	// Properties are visited in order: A.Odd2, Odd1 and then X, Y.
+	want := "/api/v0/b: Couldn't determine item type of array\n/api/v0/b: Couldn't determine item type of array\n" +
+		"/api/v0/a: Unsupported type for response: true\n/api/v0/a: Unsupported type for response: 1"
+	if warnings[0] != want {
+		t.Errorf("unexpected warnings %q", warnings[0])
+	}
+}
+
+func TestParameterDefaults(t *testing.T) {
+	for _, tc := range []struct {
+		arg  *Argument
+		want string
+	}{
+		// Extracted options have the Go value of the default.
+		{&Argument{Name: "a", Type: "array", Kind: cmds.Strings, Default: "[a b]", DefaultValue: []string{"a", "b"}}, "[a b]"},
+		{&Argument{Name: "f", Type: "float64", Kind: cmds.Float, Default: "0.5", DefaultValue: 0.5}, "0.5"},
+		// Arguments built by hand are parsed according to their type.
+		{NewOption("u", "uint64", "", "18446744073709551615"), "18446744073709551615"},
+		{NewOption("b", "bool", "", "true"), "true"},
+	} {
+		var w warnings
+		p := genParameterForArgument(&w, tc.arg, false)
+		if d := p.Schema.Schema.Default; d == nil || fmt.Sprint(*d) != tc.want {
+			t.Errorf("%s: unexpected default %v", tc.arg.Name, d)
+		}
+		if _, isString := (*p.Schema.Schema.Default).(string); isString {
+			t.Errorf("%s: default is a string", tc.arg.Name)
+		}
+		if len(w.list) > 0 {
+			t.Errorf("%s: unexpected warnings %v", tc.arg.Name, w.list)
+		}
+	}
+}
+
+func TestMultiArgumentItems(t *testing.T) {
+	p := genParameterForMultiArgument(nil, []*Argument{
+		NewArgument("cid", "string", "The CID.", true),
+		NewArgument("name", "string", "A name.", true),
+		NewArgument("other", "string", "Another name.", false),
+	})
+	items := p.Schema.Schema.Items.Schema
+	if items == nil || len(items.AnyOf) != 2 || items.AnyOf[0].SchemaReference == nil || items.AnyOf[1].Schema == nil {
+		t.Errorf("unexpected items %+v", p.Schema.Schema.Items)
+	}
+	if names := p.MapOfAnything["x-arg-names"]; fmt.Sprint(names) != "[cid name other]" {
+		t.Errorf("unexpected x-arg-names %v", names)
+	}
+
+	p = genParameterForMultiArgument(nil, []*Argument{
+		NewArgument("from", "string", "From.", true),
+		NewArgument("to", "string", "To.", true),
+	})
+	if items := p.Schema.Schema.Items.Schema; items == nil || items.Type == nil || *items.Type != openapi3.SchemaTypeString {
+		t.Errorf("arguments of the same type should share the item schema, got %+v", p.Schema.Schema.Items)
+	}
+}
+
+func TestStatusMetadata(t *testing.T) {
+	var w warnings
+	for status, want := range map[cmds.Status]string{
+		cmds.Active:       "",
+		cmds.Experimental: "experimental",
+		cmds.Deprecated:   "deprecated",
+		cmds.Removed:      "removed",
+	} {
+		p := genParameterForArgument(&w, &Argument{Name: "o", Type: "bool", Status: status}, false)
+		if got, _ := p.MapOfAnything["x-status"].(string); got != want {
+			t.Errorf("%v: x-status %q, want %q", status, got, want)
+		}
+		deprecated := p.Deprecated != nil && *p.Deprecated
+		if deprecated != (status == cmds.Deprecated || status == cmds.Removed) {
+			t.Errorf("%v: deprecated %v", status, deprecated)
+		}
+		if _, experimental := p.MapOfAnything["x-experimental"]; experimental != (status == cmds.Experimental) {
+			t.Errorf("%v: x-experimental %v", status, experimental)
+		}
+		if removed, ok := p.MapOfAnything["x-removed"]; ok != deprecated || (ok && removed != (status == cmds.Removed)) {
+			t.Errorf("%v: x-removed %v", status, removed)
+		}
+	}
+}
+
+func TestOptionLifecycle(t *testing.T) {
+	var w warnings
+	opt := &Argument{Endpoint: "/api/v0/block/put", Name: "format", Type: "string", Status: cmds.Deprecated}
+	p := genParameterForArgument(&w, opt, false)
+	if since := p.MapOfAnything["x-deprecated-since"]; since != "0.13.0" {
+		t.Errorf("x-deprecated-since %v", since)
+	}
+	if _, ok := p.MapOfAnything["x-removed-in"]; ok {
+		t.Error("x-removed-in for an option which is not removed")
+	}
+	opt.Status = cmds.Active
+	if p := genParameterForArgument(&w, opt, false); p.MapOfAnything["x-deprecated-since"] != nil {
+		t.Error("x-deprecated-since for an active option")
+	}
+}
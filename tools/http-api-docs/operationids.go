@@ -0,0 +1,70 @@
+package docs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Styles of the operation IDs of the OpenAPI spec (see
+// OpenAPIFormatter.OperationIDStyle). The default keeps the path of the
+// endpoint, which many code generators can't turn into method names.
+const (
+	OperationIDSlash = "slash" // pin/add
+	OperationIDCamel = "camel" // pinAdd
+	OperationIDSnake = "snake" // pin_add
+)
+
+// operationID returns the operation ID of an endpoint in the given style.
+func operationID(name, style string) (string, error) {
+	switch style {
+	case "", OperationIDSlash:
+		return strings.TrimPrefix(name, APIPrefix+"/"), nil
+	case OperationIDCamel:
+		return asyncAPIChannel(name), nil
+	case OperationIDSnake:
+		var parts []string
+		for _, part := range strings.Split(strings.TrimPrefix(name, APIPrefix), "/") {
+			words := strings.FieldsFunc(part, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+			if len(words) > 0 {
+				parts = append(parts, strings.ToLower(strings.Join(words, "_")))
+			}
+		}
+		return strings.Join(parts, "_"), nil
+	default:
+		return "", fmt.Errorf("unknown operation ID style %q (want %s, %s or %s)", style, OperationIDSlash, OperationIDCamel, OperationIDSnake)
+	}
+}
+
+// OperationIDs returns the operation IDs of the given endpoints in the
+// given style, by endpoint name. IDs are unique: when the style maps two
+// endpoints to the same ID (e.g. "a/b-c" and "a/bC" in camel case), the
+// endpoints after the first one, by name, get a numeric suffix.
+func OperationIDs(api []*Endpoint, style string) (map[string]string, error) {
+	names := make([]string, 0, len(api))
+	for _, endp := range api {
+		names = append(names, endp.Name)
+	}
+	sort.Strings(names)
+
+	ids := make(map[string]string, len(names))
+	used := make(map[string]bool, len(names))
+	for _, name := range names {
+		if _, ok := ids[name]; ok {
+			continue
+		}
+		id, err := operationID(name, style)
+		if err != nil {
+			return nil, err
+		}
+		unique := id
+		for i := 2; used[unique]; i++ {
+			unique = id + strconv.Itoa(i)
+		}
+		used[unique] = true
+		ids[name] = unique
+	}
+	return ids, nil
+}
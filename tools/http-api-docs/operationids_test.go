@@ -0,0 +1,51 @@
+package docs
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestOperationIDs(t *testing.T) {
+	api := []*Endpoint{
+		{Name: "/api/v0/pin/add"},
+		{Name: "/api/v0/diag/cmds/set-time"},
+		{Name: "/api/v0/a/b-c"},
+		{Name: "/api/v0/a/bC"},
+	}
+	for style, want := range map[string][]string{
+		OperationIDSlash: {"pin/add", "diag/cmds/set-time", "a/b-c", "a/bC"},
+		OperationIDCamel: {"pinAdd", "diagCmdsSetTime", "aBC", "aBC2"},
+		OperationIDSnake: {"pin_add", "diag_cmds_set_time", "a_b_c", "a_bc"},
+	} {
+		ids, err := OperationIDs(api, style)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, endp := range api {
+			if ids[endp.Name] != want[i] {
+				t.Errorf("%s: got %q for %s, want %q", style, ids[endp.Name], endp.Name, want[i])
+			}
+		}
+	}
+	if _, err := OperationIDs(api, "kebab"); err == nil {
+		t.Error("no error for an unknown style")
+	}
+}
+
+func TestOperationIDStyle(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	formatter := OpenAPIFormatter{OperationIDStyle: OperationIDCamel}
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := formatter.SpecYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"operationId: fixtureV0ParentChild", "x-operation-ids:", "fixtureV0ParentChild: /fixture/v0/parent/child"} {
+		if !strings.Contains(spec, want) {
+			t.Errorf("spec doesn't contain %q", want)
+		}
+	}
+}
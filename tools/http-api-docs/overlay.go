@@ -0,0 +1,226 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/swaggest/openapi-go/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+// Overlay patches the generated operations. It is the escape hatch for
+// helptext-derived docs which are wrong, and survives regeneration. Example:
+//
+//	operations:
+//	  /api/v0/cat:
+//	    description: Show IPFS object data.
+//	    parameters:
+//	      offset:
+//	        description: Byte offset to begin reading from.
+//	        example: 0
+//	  /api/v0/diag/profile:
+//	    internal: true
+//	  /api/v0/webui:
+//	    servers:
+//	      - url: http://127.0.0.1:8080
+//	        description: Kubo gateway at Addresses.Gateway
+//	  /api/v0/id:
+//	    response:
+//	      schema:
+//	        type: object
+//	        properties:
+//	          ID: {type: string}
+//	      example: {ID: "12D3KooW..."}
+type Overlay struct {
+	Operations map[string]*OperationOverlay `yaml:"operations"`
+}
+
+// OperationOverlay patches an operation. Unset fields are left as generated.
+type OperationOverlay struct {
+	Summary     *string `yaml:"summary"`
+	Description *string `yaml:"description"`
+	Deprecated  *bool   `yaml:"deprecated"`
+	// Internal marks the operation with x-internal, for renderers to hide
+	// it.
+	Internal   bool                         `yaml:"internal"`
+	Parameters map[string]*ParameterOverlay `yaml:"parameters"`
+	Response   *ResponseOverlay             `yaml:"response"`
+	// Servers replace the servers of the spec for the operation, for the
+	// functionality served elsewhere than the RPC API, e.g. by the gateway
+	// on port 8080, so that a combined spec calls the right base URL.
+	Servers []ServerOverlay `yaml:"servers"`
+}
+
+// ServerOverlay is a server of an operation.
+type ServerOverlay struct {
+	URL         string `yaml:"url"`
+	Description string `yaml:"description"`
+}
+
+// ParameterOverlay patches a parameter of an operation.
+type ParameterOverlay struct {
+	Description *string `yaml:"description"`
+	Example     any     `yaml:"example"`
+}
+
+// ResponseOverlay patches the successful response of an operation.
+type ResponseOverlay struct {
+	Description *string `yaml:"description"`
+	// Schema replaces the response schema.
+	Schema  map[string]any `yaml:"schema"`
+	Example any            `yaml:"example"`
+}
+
+// ReadOverlay parses an overlay in YAML.
+func ReadOverlay(r io.Reader) (*Overlay, error) {
+	var overlay Overlay
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&overlay); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return &overlay, nil
+}
+
+// LoadOverlay reads an overlay file.
+func LoadOverlay(path string) (*Overlay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	overlay, err := ReadOverlay(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return overlay, nil
+}
+
+// applyOverlay patches the generated operations. Patches of operations or
+// parameters which don't exist are reported as warnings, as they are
+// probably stale.
+func (myself *OpenAPIFormatter) applyOverlay(overlay *Overlay) error {
+	names := make([]string, 0, len(overlay.Operations))
+	for name := range overlay.Operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		w := &warnings{endpoint: name}
+		patch := overlay.Operations[name]
+		item, ok := myself.spec.Paths.MapOfPathItemValues[name]
+		op, found := item.MapOfOperationValues["post"]
+		if !ok || !found {
+			w.warnf("Overlay for an operation which doesn't exist")
+			myself.Warnings = append(myself.Warnings, w.list...)
+			continue
+		}
+		if err := myself.patchOperation(w, &op, patch); err != nil {
+			return &EndpointError{Endpoint: name, Err: err}
+		}
+		item.MapOfOperationValues["post"] = op
+		myself.Warnings = append(myself.Warnings, w.list...)
+	}
+	return nil
+}
+
+func (myself *OpenAPIFormatter) patchOperation(w *warnings, op *openapi3.Operation, patch *OperationOverlay) error {
+	if patch.Summary != nil {
+		op.Summary = patch.Summary
+	}
+	if patch.Description != nil {
+		op.Description = patch.Description
+	}
+	if patch.Deprecated != nil {
+		op.Deprecated = patch.Deprecated
+	}
+	if patch.Internal {
+		op.WithMapOfAnythingItem("x-internal", true)
+	}
+	if len(patch.Servers) > 0 {
+		op.Servers = make([]openapi3.Server, 0, len(patch.Servers))
+		for _, s := range patch.Servers {
+			if s.URL == "" {
+				return fmt.Errorf("server without url in overlay")
+			}
+			server := openapi3.Server{URL: s.URL}
+			if s.Description != "" {
+				server.Description = ptr(s.Description)
+			}
+			op.Servers = append(op.Servers, server)
+		}
+	}
+
+	names := make([]string, 0, len(patch.Parameters))
+	for name := range patch.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pp := patch.Parameters[name]
+		found := false
+		for _, p := range op.Parameters {
+			if p.Parameter == nil || p.Parameter.Name != name {
+				continue
+			}
+			found = true
+			if pp.Description != nil {
+				p.Parameter.Description = pp.Description
+			}
+			if pp.Example != nil {
+				p.Parameter.WithExample(pp.Example)
+			}
+		}
+		if !found {
+			w.warnf("Overlay for parameter %s which doesn't exist", name)
+		}
+	}
+
+	if rp := patch.Response; rp != nil {
+		resp := op.Responses.MapOfResponseOrRefValues["200"].Response
+		if resp == nil {
+			resp = &openapi3.Response{Description: "Successful response"}
+			op.Responses.WithMapOfResponseOrRefValuesItem("200", openapi3.ResponseOrRef{Response: resp})
+		}
+		if rp.Description != nil {
+			resp.Description = *rp.Description
+		}
+		if len(resp.Content) == 0 {
+			resp.WithContentItem("application/json", openapi3.MediaType{})
+		}
+		for mime, media := range resp.Content {
+			if rp.Schema != nil {
+				schema, err := overlaySchema(rp.Schema)
+				if err != nil {
+					return err
+				}
+				myself.setProvenance(schema.Schema, ProvenanceOverlay)
+				media.WithSchema(schema)
+			}
+			if rp.Example != nil {
+				// The example of the overlay wins over recorded ones.
+				media.WithExample(rp.Example)
+				media.Examples = nil
+			}
+			resp.Content[mime] = media
+		}
+	}
+	return nil
+}
+
+// overlaySchema converts a schema written in the overlay.
+func overlaySchema(schema map[string]any) (openapi3.SchemaOrRef, error) {
+	var s openapi3.SchemaOrRef
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("invalid response schema in overlay: %w", err)
+	}
+	return s, nil
+}
@@ -0,0 +1,82 @@
+package docs
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	overlay, err := ReadOverlay(strings.NewReader(`
+operations:
+  /api/v0/cat:
+    description: Show IPFS object data.
+    internal: true
+    parameters:
+      offset:
+        description: Byte offset to begin reading from.
+        example: 10
+      missing:
+        description: Stale.
+  /api/v0/id:
+    servers:
+      - url: http://127.0.0.1:8080
+        description: Gateway
+    response:
+      schema:
+        type: object
+        properties:
+          ID: {type: string}
+      example: {ID: "12D3KooW"}
+  /api/v0/gone:
+    internal: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := []*Endpoint{
+		{Name: "/api/v0/cat", Options: []*Argument{{Name: "offset", Type: "int64"}}, Response: "This endpoint returns a `text/plain` response body."},
+		{Name: "/api/v0/id"},
+	}
+	formatter := &OpenAPIFormatter{Overlay: overlay}
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+
+	cat := formatter.spec.Paths.MapOfPathItemValues["/api/v0/cat"].MapOfOperationValues["post"]
+	if *cat.Description != "Show IPFS object data." || cat.MapOfAnything["x-internal"] != true {
+		t.Errorf("cat was not patched")
+	}
+	if p := cat.Parameters[0].Parameter; *p.Description != "Byte offset to begin reading from." || *p.Example != 10 {
+		t.Errorf("offset was not patched: %+v", p)
+	}
+
+	id := formatter.spec.Paths.MapOfPathItemValues["/api/v0/id"].MapOfOperationValues["post"]
+	media := id.Responses.MapOfResponseOrRefValues["200"].Response.Content["application/json"]
+	if media.Schema == nil || media.Schema.Schema.MapOfAnything["x-provenance"] != "overlay" || media.Example == nil {
+		t.Errorf("id response was not patched: %+v", media)
+	}
+
+	if len(id.Servers) != 1 || id.Servers[0].URL != "http://127.0.0.1:8080" || *id.Servers[0].Description != "Gateway" {
+		t.Errorf("id servers were not patched: %+v", id.Servers)
+	}
+	if cat.Servers != nil {
+		t.Errorf("cat should use the servers of the spec, got %+v", cat.Servers)
+	}
+
+	var stale []string
+	for _, w := range formatter.Warnings {
+		stale = append(stale, w.String())
+	}
+	want := "/api/v0/cat: Overlay for parameter missing which doesn't exist|/api/v0/gone: Overlay for an operation which doesn't exist"
+	if got := strings.Join(stale, "|"); got != want {
+		t.Errorf("got warnings %q, want %q", got, want)
+	}
+}
+
+func TestReadOverlayUnknownField(t *testing.T) {
+	if _, err := ReadOverlay(strings.NewReader("operations:\n  /api/v0/id:\n    descripton: typo\n")); err == nil {
+		t.Errorf("expected an error for an unknown field")
+	}
+}
@@ -0,0 +1,333 @@
+package docs
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+func TestGroupOptions(t *testing.T) {
+	var opts []*Argument
+	for _, name := range []string{"pin", "quiet", "unknown", "chunker"} {
+		opts = append(opts, &Argument{Name: name})
+	}
+
+	grouped := groupOptions("/api/v0/add", opts)
+	var got []string
+	for _, opt := range grouped {
+		got = append(got, opt.Group+":"+opt.Name)
+	}
+	want := []string{"Output control:quiet", "Chunking:chunker", "Storage:pin", "Other:unknown"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+
+	if ungrouped := groupOptions("/api/v0/cat", []*Argument{{Name: "offset"}}); ungrouped[0].Group != "" {
+		t.Errorf("options of ungrouped endpoints should not get a group")
+	}
+}
+
+func TestStreamingEndpointsExist(t *testing.T) {
+	endpoints := make(map[string]*Endpoint)
+	for _, endp := range AllEndpoints() {
+		endpoints[endp.Name] = endp
+	}
+	for name, s := range streamingEndpoints {
+		endp, ok := endpoints[name]
+		if !ok {
+			t.Errorf("streaming endpoint %s does not exist", name)
+			continue
+		}
+		if s.Option != "" {
+			if endp.Streaming || endp.StreamingOption != s.Option {
+				t.Errorf("endpoint %s should only stream with %s", name, s.Option)
+			}
+			if !slices.ContainsFunc(endp.Options, func(opt *Argument) bool { return opt.Name == s.Option }) {
+				t.Errorf("%s: the streaming option %s does not exist", name, s.Option)
+			}
+		} else if !endp.Streaming {
+			t.Errorf("endpoint %s should be marked as streaming", name)
+		}
+	}
+}
+
+func TestAsyncEffectsLinkToExistingEndpoints(t *testing.T) {
+	endpoints := make(map[string]bool)
+	for _, endp := range AllEndpoints() {
+		endpoints[endp.Name] = true
+	}
+	for name, effects := range asyncEffectsPerEndpoint {
+		if !endpoints[name] {
+			t.Errorf("endpoint %s with async effects does not exist", name)
+		}
+		for _, effect := range effects {
+			for _, f := range effect.Next {
+				if !endpoints[f.Endpoint] {
+					t.Errorf("%s: follow-up endpoint %s does not exist", name, f.Endpoint)
+				}
+				if f.Action != "poll" && f.Action != "subscribe" {
+					t.Errorf("%s: unknown follow-up action %q", name, f.Action)
+				}
+			}
+		}
+	}
+}
+
+func TestLongPollEndpointsStream(t *testing.T) {
+	endpoints := make(map[string]*Endpoint)
+	for _, endp := range AllEndpoints() {
+		endpoints[endp.Name] = endp
+	}
+	for name := range longPollEndpoints {
+		endp, ok := endpoints[name]
+		if !ok {
+			t.Errorf("long-polling endpoint %s does not exist", name)
+			continue
+		}
+		if endp.LongPoll == nil || !(endp.Streaming || endp.StreamingOption == endp.LongPoll.Option && endp.StreamingOption != "") {
+			t.Errorf("endpoint %s should be marked as streaming and long-polling", name)
+		}
+	}
+
+	formatter := new(OpenAPIFormatter)
+	sub := endpoints["/api/v0/pubsub/sub"]
+	longPoll := formatter.genLongPoll(nil, nil, sub)
+	if longPoll["heldOpen"] != true || longPoll["messageSchema"] == nil {
+		t.Errorf("unexpected x-long-poll for pubsub/sub: %v", longPoll)
+	}
+}
+
+func TestResponseVariantsOptionsExist(t *testing.T) {
+	endpoints := make(map[string]*Endpoint)
+	for _, endp := range AllEndpoints() {
+		endpoints[endp.Name] = endp
+	}
+	for name, variants := range responseVariants {
+		endp, ok := endpoints[name]
+		if !ok {
+			t.Errorf("endpoint %s with response variants does not exist", name)
+			continue
+		}
+		for _, v := range variants {
+			for option := range v.When {
+				if !slices.ContainsFunc(endp.Options, func(opt *Argument) bool { return opt.Name == option }) {
+					t.Errorf("%s: the option %s of a response variant does not exist", name, option)
+				}
+			}
+		}
+	}
+}
+
+func TestArgumentSchemasExist(t *testing.T) {
+	endpoints := make(map[string]*Endpoint)
+	for _, endp := range AllEndpoints() {
+		endpoints[endp.Name] = endp
+	}
+	for name, args := range argumentSchemas {
+		endp, ok := endpoints[name]
+		if !ok {
+			t.Errorf("endpoint %s with argument schemas does not exist", name)
+			continue
+		}
+		for arg := range args {
+			exists := func(a *Argument) bool { return a.Name == arg }
+			if !slices.ContainsFunc(endp.Arguments, exists) && !slices.ContainsFunc(endp.Options, exists) {
+				t.Errorf("%s: the argument %s with a schema does not exist", name, arg)
+			}
+		}
+	}
+}
+
+func TestResponseHeadersEndpointsExist(t *testing.T) {
+	endpoints := make(map[string]bool)
+	for _, endp := range AllEndpoints() {
+		endpoints[endp.Name] = true
+	}
+	for name := range responseHeaders {
+		if !endpoints[name] {
+			t.Errorf("endpoint %s with response headers does not exist", name)
+		}
+	}
+}
+
+func TestOperationLinksExist(t *testing.T) {
+	endpoints := make(map[string]*Endpoint)
+	for _, endp := range AllEndpoints() {
+		endpoints[endp.Name] = endp
+	}
+	for name, links := range operationLinks {
+		endp, ok := endpoints[name]
+		if !ok {
+			t.Errorf("endpoint %s with links does not exist", name)
+			continue
+		}
+		for _, link := range links {
+			if !strings.Contains(endp.Response, strconv.Quote(strings.Split(link.Field, ".")[0])+":") {
+				t.Errorf("%s: field %s is not in the response %s", name, link.Field, endp.Response)
+			}
+			target, ok := endpoints[link.Target]
+			if !ok {
+				t.Errorf("%s: link target %s does not exist", name, link.Target)
+				continue
+			}
+			if link.Parameter == "arg" {
+				if len(target.Arguments) == 0 {
+					t.Errorf("%s: link target %s has no arguments", name, link.Target)
+				}
+			} else if !slices.ContainsFunc(target.Options, func(opt *Argument) bool { return opt.Name == link.Parameter }) {
+				t.Errorf("%s: option %s of %s does not exist", name, link.Parameter, link.Target)
+			}
+		}
+	}
+}
+
+func TestUnitOverridesExist(t *testing.T) {
+	endpoints := make(map[string]*Endpoint)
+	for _, endp := range AllEndpoints() {
+		endpoints[endp.Name] = endp
+	}
+	for name, options := range optionUnits {
+		endp, ok := endpoints[name]
+		if !ok {
+			t.Errorf("endpoint %s with option units does not exist", name)
+			continue
+		}
+		for option := range options {
+			if !slices.ContainsFunc(endp.Options, func(opt *Argument) bool { return opt.Name == option }) {
+				t.Errorf("%s: the option %s with a unit does not exist", name, option)
+			}
+		}
+	}
+	for name, fields := range responseFieldUnits {
+		endp, ok := endpoints[name]
+		if !ok {
+			t.Errorf("endpoint %s with field units does not exist", name)
+			continue
+		}
+		for path := range fields {
+			field := path[strings.LastIndex(path, ".")+1:]
+			if !strings.Contains(endp.Response, strconv.Quote(field)+":") {
+				t.Errorf("%s: field %s is not in the response %s", name, path, endp.Response)
+			}
+		}
+	}
+}
+
+func TestFieldPresenceOverridesExist(t *testing.T) {
+	endpoints := make(map[string]*Endpoint)
+	for _, endp := range AllEndpoints() {
+		endpoints[endp.Name] = endp
+	}
+	for name, fields := range responseFieldPresence {
+		endp, ok := endpoints[name]
+		if !ok {
+			t.Errorf("endpoint %s with field presence does not exist", name)
+			continue
+		}
+		for path := range fields {
+			field := path[strings.LastIndex(path, ".")+1:]
+			if !strings.Contains(endp.Response, strconv.Quote(field)+":") {
+				t.Errorf("%s: field %s is not in the response %s", name, path, endp.Response)
+			}
+		}
+	}
+}
+
+func TestOptionLifecyclesCoverStatus(t *testing.T) {
+	endpoints := make(map[string]*Endpoint)
+	for _, endp := range AllEndpoints() {
+		endpoints[endp.Name] = endp
+		for _, opt := range endp.Options {
+			if opt.Status != cmds.Deprecated && opt.Status != cmds.Removed {
+				continue
+			}
+			lifecycle, ok := optionLifecycles[endp.Name][opt.Name]
+			if !ok || lifecycle.DeprecatedSince == "" {
+				t.Errorf("%s: the deprecated option %s has no x-deprecated-since", endp.Name, opt.Name)
+			}
+			if opt.Status == cmds.Removed && lifecycle.RemovedIn == "" {
+				t.Errorf("%s: the removed option %s has no x-removed-in", endp.Name, opt.Name)
+			}
+		}
+	}
+	for name, options := range optionLifecycles {
+		endp, ok := endpoints[name]
+		if !ok {
+			t.Errorf("endpoint %s with option lifecycles does not exist", name)
+			continue
+		}
+		for option := range options {
+			if !slices.ContainsFunc(endp.Options, func(opt *Argument) bool {
+				return opt.Name == option && (opt.Status == cmds.Deprecated || opt.Status == cmds.Removed)
+			}) {
+				t.Errorf("%s: the option %s with a lifecycle is not deprecated", name, option)
+			}
+		}
+	}
+}
+
+func TestMultipartExamplesTakeFiles(t *testing.T) {
+	endpoints := make(map[string]*Endpoint)
+	for _, endp := range AllEndpoints() {
+		endpoints[endp.Name] = endp
+	}
+	for name := range multipartExamples {
+		endp, ok := endpoints[name]
+		if !ok {
+			t.Errorf("endpoint %s with multipart examples does not exist", name)
+		} else if fileArgument(endp.Arguments) == nil {
+			t.Errorf("endpoint %s with multipart examples takes no files", name)
+		}
+		if _, ok := rawBodyEndpoints[name]; ok {
+			t.Errorf("the examples of %s, which takes raw data, are generated", name)
+		}
+	}
+}
+
+func TestProgressEventsMatchResponses(t *testing.T) {
+	endpoints := make(map[string]*Endpoint)
+	for _, endp := range AllEndpoints() {
+		endpoints[endp.Name] = endp
+	}
+	schemaNames := make(map[string]bool)
+	for name, events := range progressEvents {
+		endp, ok := endpoints[name]
+		if !ok {
+			t.Errorf("endpoint %s with progress events does not exist", name)
+			continue
+		}
+		if !endp.Streaming {
+			t.Errorf("endpoint %s with progress events does not stream", name)
+		}
+		for _, ev := range events {
+			if schemaNames[ev.Schema] {
+				t.Errorf("%s: the event schema %s is used twice", name, ev.Schema)
+			}
+			schemaNames[ev.Schema] = true
+			var doc map[string]any
+			if err := json.Unmarshal([]byte(ev.Response), &doc); err != nil {
+				t.Errorf("%s: event %s: %s", name, ev.Schema, err)
+				continue
+			}
+			for field := range doc {
+				if !strings.Contains(endp.Response, strconv.Quote(field)+":") {
+					t.Errorf("%s: field %s of the event %s is not in the response %s", name, field, ev.Schema, endp.Response)
+				}
+			}
+			for _, field := range ev.Required {
+				if _, ok := doc[field]; !ok {
+					t.Errorf("%s: the required field %s is not in the event %s", name, field, ev.Schema)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package docs
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// generatedOperation is the outcome of genOperation for an endpoint.
+type generatedOperation struct {
+	op       *endpointOperation
+	warnings []Warning
+	err      *EndpointError
+}
+
+// genOperations generates the operations of the endpoints with Jobs
+// goroutines. They are returned in the order of the endpoints, for Build to
+// add them to the spec in that order, so that the spec doesn't depend on
+// the scheduling. Endpoints are left out once the context is done.
+func (myself *OpenAPIFormatter) genOperations(ctx context.Context, api []*Endpoint) []generatedOperation {
+	generated := make([]generatedOperation, len(api))
+	jobs := myself.Jobs
+	if jobs <= 0 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+	jobs = min(jobs, len(api))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				generated[i] = myself.genOperationIsolated(ctx, api[i])
+			}
+		}()
+	}
+	for i := range api {
+		if ctx.Err() != nil {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return generated
+}
+
+// genOperationIsolated generates the operation of an endpoint, turning
+// errors and panics into an EndpointError so that one broken command
+// doesn't take down the whole spec.
+func (myself *OpenAPIFormatter) genOperationIsolated(ctx context.Context, endp *Endpoint) (g generatedOperation) {
+	w := &warnings{endpoint: endp.Name}
+	defer func() {
+		if r := recover(); r != nil {
+			g.err = &EndpointError{Endpoint: endp.Name, Err: fmt.Errorf("panic: %v", r)}
+		}
+		g.warnings = w.list
+	}()
+	op, err := myself.genOperation(ctx, w, endp)
+	if err != nil {
+		return generatedOperation{err: &EndpointError{Endpoint: endp.Name, Err: err}}
+	}
+	return generatedOperation{op: op}
+}
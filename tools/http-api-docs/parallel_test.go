@@ -0,0 +1,28 @@
+package docs
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBuildJobs(t *testing.T) {
+	api := AllEndpoints()
+	var specs []string
+	for _, jobs := range []int{1, 8} {
+		spec, err := (&OpenAPIFormatter{Jobs: jobs}).Generate(context.Background(), api)
+		if err != nil {
+			t.Fatal(err)
+		}
+		specs = append(specs, spec)
+	}
+	if specs[0] != specs[1] {
+		t.Errorf("the spec generated with 8 jobs differs from the one generated with 1")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := (&OpenAPIFormatter{Jobs: 8}).Build(ctx, api); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the error of the context, got %v", err)
+	}
+}
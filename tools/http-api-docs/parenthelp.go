@@ -0,0 +1,50 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Modes of surfacing the help of the parent commands in the description of
+// an endpoint (see Endpoint.ParentHelp).
+const (
+	// ParentHelpNone leaves it out, as before.
+	ParentHelpNone = ""
+	// ParentHelpLink links to the help of the parent commands in the CLI
+	// reference.
+	ParentHelpLink = "link"
+	// ParentHelpPrepend copies it before the description of the endpoint.
+	ParentHelpPrepend = "prepend"
+)
+
+// ParentHelpModes lists the accepted modes.
+var ParentHelpModes = []string{ParentHelpNone, ParentHelpLink, ParentHelpPrepend}
+
+// cliDocsURL returns the URL of the help of a command in the CLI reference.
+func cliDocsURL(name string) string {
+	return "https://docs.ipfs.tech/reference/kubo/cli/#" + strings.ReplaceAll(cliCommand(name), " ", "-")
+}
+
+// parentHelpMarkdown renders the help of the parents of an endpoint in the
+// given mode, as CommonMark.
+func parentHelpMarkdown(endp *Endpoint, mode string) string {
+	if len(endp.ParentHelp) == 0 {
+		return ""
+	}
+	switch mode {
+	case ParentHelpLink:
+		links := make([]string, len(endp.ParentHelp))
+		for i, parent := range endp.ParentHelp {
+			links[i] = fmt.Sprintf("[`%s`](%s)", cliCommand(parent.Command), cliDocsURL(parent.Command))
+		}
+		return "See also the help of " + strings.Join(links, ", ") + "."
+	case ParentHelpPrepend:
+		blocks := make([]string, len(endp.ParentHelp))
+		for i, parent := range endp.ParentHelp {
+			blocks[i] = fmt.Sprintf("**From the help of `%s`:**\n\n%s", cliCommand(parent.Command), helptextMarkdown(parent.Text))
+		}
+		return strings.Join(blocks, "\n\n")
+	default:
+		return ""
+	}
+}
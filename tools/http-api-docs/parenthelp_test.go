@@ -0,0 +1,55 @@
+package docs
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+func TestParentHelp(t *testing.T) {
+	root := &cmds.Command{
+		Helptext: cmds.HelpText{ShortDescription: "The root help is not inherited."},
+		Subcommands: map[string]*cmds.Command{
+			"files": {
+				Helptext: cmds.HelpText{
+					ShortDescription: "Short help of files.",
+					LongDescription:  "Long help of files.\n\nChanges are flushed by default.",
+				},
+				Subcommands: map[string]*cmds.Command{
+					"write": {Helptext: cmds.HelpText{Tagline: "Write to a file."}, Run: fixtureRun},
+				},
+			},
+		},
+	}
+	api := Endpoints(APIPrefix, root)
+	if len(api) != 1 {
+		t.Fatalf("expected files/write only, got %d endpoints", len(api))
+	}
+	write := api[0]
+	if want := []ParentHelp{{Command: APIPrefix + "/files", Text: "Long help of files.\n\nChanges are flushed by default."}}; len(write.ParentHelp) != 1 || write.ParentHelp[0] != want[0] {
+		t.Fatalf("got parent help %v, want %v", write.ParentHelp, want)
+	}
+
+	if md := new(MarkdownFormatter).GenerateEndpointBlock(write); strings.Contains(md, "flushed") || strings.Contains(md, "ipfs files") {
+		t.Errorf("the parent help should be left out by default:\n%s", md)
+	}
+	link := (&MarkdownFormatter{ParentHelp: ParentHelpLink}).GenerateEndpointBlock(write)
+	if !strings.Contains(link, "Write to a file.\n\nSee also the help of [`ipfs files`](https://docs.ipfs.tech/reference/kubo/cli/#ipfs-files).") {
+		t.Errorf("missing link to the parent help:\n%s", link)
+	}
+	prepend := (&MarkdownFormatter{ParentHelp: ParentHelpPrepend}).GenerateEndpointBlock(write)
+	if !strings.Contains(prepend, "**From the help of `ipfs files`:**\n\nLong help of files.\n\nChanges are flushed by default.") {
+		t.Errorf("missing parent help:\n%s", prepend)
+	}
+
+	formatter := &OpenAPIFormatter{ParentHelp: ParentHelpPrepend}
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+	op := formatter.spec.Paths.MapOfPathItemValues[write.Name].MapOfOperationValues["post"]
+	if d := *op.Description; !strings.HasPrefix(d, "**From the help of `ipfs files`:**") || !strings.HasSuffix(d, "Write to a file.") {
+		t.Errorf("unexpected description %q", d)
+	}
+}
@@ -0,0 +1,94 @@
+package docs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// PlannedOperation summarizes what is generated for an endpoint.
+type PlannedOperation struct {
+	Endpoint string
+	// Parameters is the number of parameters of the endpoint, without the
+	// global ones.
+	Parameters int
+	Body       bool
+	// ResponseSource tells where the response schema comes from.
+	ResponseSource string
+	Warnings       []string
+	// Failed is set when the endpoint would be skipped.
+	Failed string
+}
+
+// DryRun generates the operations of the given endpoints, without
+// marshaling the spec, and returns a summary of each of them. It is a quick
+// way to preview the effect of changes to the overrides.
+func (myself *OpenAPIFormatter) DryRun(ctx context.Context, api []*Endpoint) ([]*PlannedOperation, error) {
+	if err := myself.Build(ctx, api); err != nil {
+		return nil, err
+	}
+
+	warningsOf := make(map[string][]string)
+	for _, w := range myself.Warnings {
+		warningsOf[w.Endpoint] = append(warningsOf[w.Endpoint], w.Message)
+	}
+	failures := make(map[string]string)
+	for _, f := range myself.Failures {
+		failures[f.Endpoint] = f.Err.Error()
+	}
+
+	var plan []*PlannedOperation
+	for _, endp := range api {
+		planned := &PlannedOperation{
+			Endpoint: endp.Name,
+			Warnings: warningsOf[endp.Name],
+			Failed:   failures[endp.Name],
+		}
+		plan = append(plan, planned)
+		op, ok := myself.spec.Paths.MapOfPathItemValues[endp.Name].MapOfOperationValues["post"]
+		if !ok {
+			continue
+		}
+		for _, p := range op.Parameters {
+			if p.Parameter != nil {
+				planned.Parameters++
+			}
+		}
+		planned.Body = op.RequestBody != nil
+		planned.ResponseSource = responseSource(myself.schemaNames, endp, op.Responses.MapOfResponseOrRefValues["200"].Response != nil)
+	}
+	return plan, nil
+}
+
+func responseSource(schemaNames map[string]string, endp *Endpoint, documented bool) string {
+	switch {
+	case endp.Response == textResponse:
+		return "text/plain"
+	case !documented:
+		return "none"
+	case schemaNames[endp.ResponseType] != "":
+		return "component " + schemaNames[endp.ResponseType] + " (from " + endp.ResponseType + ")"
+	default:
+		return "inline (from example)"
+	}
+}
+
+// WritePlan writes a plan as a table.
+func WritePlan(out io.Writer, plan []*PlannedOperation) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENDPOINT\tPARAMS\tBODY\tRESPONSE\tWARNINGS")
+	for _, op := range plan {
+		body := "no"
+		if op.Body {
+			body = "yes"
+		}
+		notes := strings.Join(op.Warnings, "; ")
+		if op.Failed != "" {
+			notes = "SKIPPED: " + op.Failed
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n", op.Endpoint, op.Parameters, body, op.ResponseSource, notes)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,39 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	formatter := new(OpenAPIFormatter)
+	plan, err := formatter.DryRun(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]*PlannedOperation)
+	for _, op := range plan {
+		byName[strings.TrimPrefix(op.Endpoint, fixturePrefix+"/")] = op
+	}
+	if op := byName["options"]; op.Parameters != 8 || op.Body || op.ResponseSource != "text/plain" {
+		t.Errorf("unexpected plan for options: %+v", op)
+	}
+	if w := byName["options"].Warnings; len(w) != 0 {
+		t.Errorf("options should have no warnings, got %v", w)
+	}
+	if op := byName["upload"]; !op.Body || !strings.HasPrefix(op.ResponseSource, "component FixtureOutput") {
+		t.Errorf("unexpected plan for upload: %+v", op)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := WritePlan(buf, plan); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != len(plan)+1 {
+		t.Errorf("expected a header and %d rows, got:\n%s", len(plan), buf)
+	}
+}
@@ -0,0 +1,174 @@
+package docs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PostmanFormatter implements a generator of Postman collections (v2.1), with
+// one request per endpoint, grouped in folders by command namespace.
+type PostmanFormatter struct {
+	// BaseURL is the default value of the {{baseUrl}} collection variable.
+	BaseURL string
+}
+
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []*postmanItem    `json:"item"`
+	Variable []postmanVariable `json:"variable"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Schema      string `json:"schema"`
+}
+
+type postmanVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// postmanItem is either a folder (with Item) or a request.
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []*postmanItem  `json:"item,omitempty"`
+	Request *postmanRequest `json:"request,omitempty"`
+}
+
+type postmanRequest struct {
+	Method      string       `json:"method"`
+	Description string       `json:"description,omitempty"`
+	URL         postmanURL   `json:"url"`
+	Body        *postmanBody `json:"body,omitempty"`
+}
+
+type postmanURL struct {
+	Raw   string         `json:"raw"`
+	Host  []string       `json:"host"`
+	Path  []string       `json:"path"`
+	Query []postmanParam `json:"query,omitempty"`
+}
+
+type postmanParam struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+type postmanBody struct {
+	Mode     string         `json:"mode"`
+	FormData []postmanParam `json:"formdata"`
+}
+
+// Generate returns the Postman collection for the given endpoints as JSON.
+func (pf *PostmanFormatter) Generate(ctx context.Context, api []*Endpoint) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	baseURL := pf.BaseURL
+	if baseURL == "" {
+		baseURL = "http://127.0.0.1:5001"
+	}
+
+	collection := postmanCollection{
+		Info: postmanInfo{
+			Name:        "Kubo RPC API",
+			Description: fmt.Sprintf("Kubo RPC API v0, generated from kubo v%s. See https://docs.ipfs.tech/reference/kubo/rpc/", IPFSVersion()),
+			Schema:      postmanSchema,
+		},
+		Variable: []postmanVariable{{Key: "baseUrl", Value: baseURL}},
+	}
+
+	folders := make(map[string]*postmanItem)
+	for _, endp := range api {
+		item := &postmanItem{
+			Name:    strings.TrimPrefix(endp.Name, APIPrefix+"/"),
+			Request: genPostmanRequest(endp),
+		}
+
+		// Group by the first path component, e.g. "pin" for pin/add.
+		namespace, _, nested := strings.Cut(item.Name, "/")
+		if !nested {
+			collection.Item = append(collection.Item, item)
+			continue
+		}
+		folder, ok := folders[namespace]
+		if !ok {
+			folder = &postmanItem{Name: namespace}
+			folders[namespace] = folder
+			collection.Item = append(collection.Item, folder)
+		}
+		folder.Item = append(folder.Item, item)
+	}
+
+	out, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func genPostmanRequest(endp *Endpoint) *postmanRequest {
+	req := &postmanRequest{
+		Method:      "POST",
+		Description: endp.Description,
+		URL: postmanURL{
+			Host: []string{"{{baseUrl}}"},
+			Path: strings.Split(strings.TrimPrefix(endp.Name, "/"), "/"),
+		},
+	}
+
+	for _, arg := range endp.Arguments {
+		if arg.Type == "file" {
+			if req.Body == nil {
+				req.Body = &postmanBody{Mode: "formdata"}
+			}
+			req.Body.FormData = append(req.Body.FormData, postmanParam{
+				Key:         "file",
+				Type:        "file",
+				Description: postmanDescription(arg),
+				Disabled:    !arg.Required,
+			})
+			continue
+		}
+		req.URL.Query = append(req.URL.Query, postmanParam{
+			Key:         "arg",
+			Value:       "<" + arg.Name + ">",
+			Description: postmanDescription(arg),
+			Disabled:    !arg.Required,
+		})
+	}
+	for _, opt := range endp.Options {
+		// Options are prefilled with their default, but only sent when
+		// enabled by the user.
+		req.URL.Query = append(req.URL.Query, postmanParam{
+			Key:         opt.Name,
+			Value:       opt.Default,
+			Description: postmanDescription(opt),
+			Disabled:    true,
+		})
+	}
+
+	var query []string
+	for _, q := range req.URL.Query {
+		if !q.Disabled {
+			query = append(query, q.Key+"="+q.Value)
+		}
+	}
+	req.URL.Raw = "{{baseUrl}}" + endp.Name
+	if len(query) > 0 {
+		req.URL.Raw += "?" + strings.Join(query, "&")
+	}
+	return req
+}
+
+func postmanDescription(arg *Argument) string {
+	return fmt.Sprintf("[%s] %s", arg.Type, fixDesc.ReplaceAllString(arg.Description, ""))
+}
@@ -0,0 +1,39 @@
+package docs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestPostman(t *testing.T) {
+	api := []*Endpoint{
+		{Name: "/api/v0/add", Arguments: []*Argument{{Name: "path", Type: "file", Required: true}}},
+		{Name: "/api/v0/pin/add", Arguments: []*Argument{{Name: "ipfs-path", Type: "string", Required: true}},
+			Options: []*Argument{{Name: "recursive", Type: "bool", Default: "true"}}},
+		{Name: "/api/v0/pin/ls"},
+	}
+	formatter := new(PostmanFormatter)
+	out, err := formatter.Generate(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var collection postmanCollection
+	if err := json.Unmarshal([]byte(out), &collection); err != nil {
+		t.Fatal(err)
+	}
+	if len(collection.Item) != 2 || len(collection.Item[1].Item) != 2 {
+		t.Fatalf("expected add and a pin folder with 2 requests, got %s", out)
+	}
+	if body := collection.Item[0].Request.Body; body == nil || body.Mode != "formdata" {
+		t.Errorf("add should have a form-data body")
+	}
+	pinAdd := collection.Item[1].Item[0].Request
+	if pinAdd.URL.Raw != "{{baseUrl}}/api/v0/pin/add?arg=<ipfs-path>" {
+		t.Errorf("unexpected url %s", pinAdd.URL.Raw)
+	}
+	if q := pinAdd.URL.Query[1]; q.Key != "recursive" || q.Value != "true" || !q.Disabled {
+		t.Errorf("unexpected option %+v", q)
+	}
+}
@@ -0,0 +1,253 @@
+package docs
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// FieldPresence tells whether a field of a response can be null or left
+// out. The zero value is a field which is always present and non-null.
+type FieldPresence struct {
+	// Nullable is set for the fields which are null when unset: slices,
+	// maps and pointers without omitempty.
+	Nullable bool `json:",omitempty"`
+	// Optional is set for the fields which are left out when empty, with
+	// omitempty.
+	Optional bool `json:",omitempty"`
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// fieldPresence returns the presence of the fields of the response of an
+// endpoint, by path (see applyTimeFormats), read from the Go type of the
+// response and corrected by responseFieldPresence. It returns nil for
+// endpoints returning text.
+func fieldPresence(endpoint string, res interface{}) map[string]FieldPresence {
+	fields := make(map[string]FieldPresence)
+	if res != nil {
+		reflectFields(fields, reflect.TypeOf(res), "", make(map[reflect.Type]bool))
+	}
+	for path, presence := range responseFieldPresence[endpoint] {
+		fields[path] = presence
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// reflectFields records the presence of the fields of the structs of t,
+// named as encoding/json names them: the fields of embedded structs without
+// a name in their tag are promoted to the parent, and the types with a
+// marshaler of their own are opaque.
+func reflectFields(fields map[string]FieldPresence, t reflect.Type, path string, visiting map[reflect.Type]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if visiting[t] || isOpaque(t) {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		reflectFields(fields, t.Elem(), path, visiting)
+	case reflect.Struct:
+		visiting[t] = true
+		defer delete(visiting, t)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, omitempty, ok := jsonField(f)
+			if !ok {
+				continue
+			}
+			if isEmbedded(f) {
+				reflectFields(fields, f.Type, path, visiting)
+				continue
+			}
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			// encoding/json never leaves out structs.
+			presence := FieldPresence{Optional: omitempty && f.Type.Kind() != reflect.Struct}
+			switch f.Type.Kind() {
+			case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+				presence.Nullable = !omitempty
+			}
+			fields[fieldPath] = presence
+			reflectFields(fields, f.Type, fieldPath, visiting)
+		}
+	}
+}
+
+// jsonField returns the name of a struct field in JSON and whether it has
+// omitempty, or false if encoding/json leaves it out.
+func jsonField(f reflect.StructField) (name string, omitempty bool, ok bool) {
+	if f.PkgPath != "" {
+		return "", false, false
+	}
+	name = f.Name
+	if tag := f.Tag.Get("json"); tag != "" {
+		parts := strings.Split(tag, ",")
+		switch parts[0] {
+		case "-":
+			return "", false, false
+		case "":
+		default:
+			name = parts[0]
+		}
+		for _, opt := range parts[1:] {
+			omitempty = omitempty || opt == "omitempty"
+		}
+	}
+	return name, omitempty, true
+}
+
+// isEmbedded tells whether encoding/json promotes the fields of a struct
+// field to its parent: an embedded struct without a name in its tag.
+func isEmbedded(f reflect.StructField) bool {
+	t := f.Type
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return f.Anonymous && t.Kind() == reflect.Struct && !isOpaque(t) && strings.Split(f.Tag.Get("json"), ",")[0] == ""
+}
+
+// isOpaque tells whether a type has a marshaler of its own.
+func isOpaque(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+// promoteEmbedded moves the fields of the embedded structs of t, which the
+// description x of JsondocGlossary nests under the name of their type, to
+// their parent, as encoding/json does. The fields of the parent win over
+// the promoted ones. It tells whether x changed.
+func promoteEmbedded(t reflect.Type, x any) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if isOpaque(t) {
+		return false
+	}
+	changed := false
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if items, ok := x.([]any); ok && len(items) == 1 {
+			changed = promoteEmbedded(t.Elem(), items[0])
+		}
+	case reflect.Map:
+		if m, ok := x.(map[string]any); ok && len(m) == 1 {
+			for _, value := range m {
+				changed = promoteEmbedded(t.Elem(), value)
+			}
+		}
+	case reflect.Struct:
+		m, ok := x.(map[string]any)
+		if !ok {
+			return false
+		}
+		promoted := make(map[string]any)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, ok := jsonField(f)
+			if !ok {
+				continue
+			}
+			value, ok := m[name]
+			if !ok {
+				continue
+			}
+			changed = promoteEmbedded(f.Type, value) || changed
+			if !isEmbedded(f) {
+				continue
+			}
+			if fields, ok := value.(map[string]any); ok {
+				delete(m, name)
+				for k, v := range fields {
+					promoted[k] = v
+				}
+				changed = true
+			}
+		}
+		for k, v := range promoted {
+			if _, ok := m[k]; !ok {
+				m[k] = v
+			}
+		}
+	}
+	return changed
+}
+
+// applyPresence documents which fields of a documented response x, whose
+// schema was generated by genSchemaOrRefForResponse, are required and
+// which are nullable, from the presence of the fields of the endpoint (see
+// Endpoint.ResponseFields). Objects with fields of unknown presence are left
+// without required fields.
+func applyPresence(fields map[string]FieldPresence, path string, x any, schema *openapi3.SchemaOrRef) *openapi3.SchemaOrRef {
+	if schema == nil || schema.Schema == nil || len(fields) == 0 {
+		return schema
+	}
+	s := schema.Schema
+	switch v := x.(type) {
+	case []any:
+		if len(v) == 1 && s.Items != nil {
+			s.Items = applyPresence(fields, path, v[0], s.Items)
+		}
+	case map[string]any:
+		if value, ok := v["<string>"]; ok && len(v) == 1 && s.AdditionalProperties != nil {
+			s.AdditionalProperties.SchemaOrRef = applyPresence(fields, path, value, s.AdditionalProperties.SchemaOrRef)
+			return schema
+		}
+		var required []string
+		known := true
+		for k, value := range v {
+			prop, ok := s.Properties[k]
+			if !ok {
+				continue
+			}
+			fieldPath := k
+			if path != "" {
+				fieldPath = path + "." + k
+			}
+			presence, ok := fields[fieldPath]
+			known = known && ok
+			if ok && !presence.Optional {
+				required = append(required, k)
+			}
+			p := applyPresence(fields, fieldPath, value, &prop)
+			if presence.Nullable {
+				p = nullable(p)
+			}
+			s.Properties[k] = *p
+		}
+		if known && len(required) > 0 {
+			sort.Strings(required)
+			s.Required = required
+		}
+	}
+	return schema
+}
+
+// nullable returns a schema which also accepts null. References can't have
+// siblings, and in OpenAPI 3.0.3 nullable only applies to the type of its
+// own schema, not to an allOf, so the shared schemas are inlined.
+func nullable(schema *openapi3.SchemaOrRef) *openapi3.SchemaOrRef {
+	if schema.Schema == nil {
+		shared := inlineSchema(schema)
+		if shared == nil {
+			return schema
+		}
+		inlined := *shared
+		schema = &openapi3.SchemaOrRef{Schema: &inlined}
+	}
+	schema.Schema.Nullable = ptr(true)
+	return schema
+}
@@ -0,0 +1,135 @@
+package docs
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+type presenceItem struct {
+	Name   string
+	Links  []string `json:",omitempty"`
+	Parent *presenceItem
+}
+
+type presenceOutput struct {
+	Items   []presenceItem
+	Count   int `json:"count,omitempty"`
+	Root    cid.Cid
+	Meta    struct{ Note string } `json:",omitempty"`
+	Ignored string                `json:"-"`
+	private string
+}
+
+func TestFieldPresence(t *testing.T) {
+	got := fieldPresence("/presence/v0/test", &presenceOutput{})
+	want := map[string]FieldPresence{
+		"Items":        {Nullable: true},
+		"Items.Name":   {},
+		"Items.Links":  {Optional: true},
+		"Items.Parent": {Nullable: true},
+		"count":        {Optional: true},
+		"Root":         {},
+		"Meta":         {},
+		"Meta.Note":    {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := fieldPresence("/presence/v0/text", nil); got != nil {
+		t.Errorf("text responses have no fields, got %v", got)
+	}
+
+	responseFieldPresence["/presence/v0/test"] = map[string]FieldPresence{"Items": {}}
+	t.Cleanup(func() { delete(responseFieldPresence, "/presence/v0/test") })
+	if got := fieldPresence("/presence/v0/test", presenceOutput{}); got["Items"].Nullable {
+		t.Errorf("responseFieldPresence should override the Go type")
+	}
+}
+
+type PresenceSizes struct {
+	RepoSize   uint64
+	StorageMax uint64 `json:",omitempty"`
+}
+
+type PresenceNamed struct{ Note string }
+
+type presenceEmbedding struct {
+	PresenceSizes
+	*PresenceNamed `json:"Named"`
+	Version        string
+	// The fields of the parent win over the promoted ones.
+	RepoSize string
+}
+
+func TestEmbeddedFields(t *testing.T) {
+	got := fieldPresence("/presence/v0/embedded", &presenceEmbedding{})
+	want := map[string]FieldPresence{
+		"StorageMax": {Optional: true},
+		"Named":      {Nullable: true},
+		"Named.Note": {},
+		"Version":    {},
+		"RepoSize":   {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(buildResponse(&presenceEmbedding{})), &doc); err != nil {
+		t.Fatal(err)
+	}
+	wantDoc := map[string]any{
+		"StorageMax": "<uint64>",
+		"Named":      map[string]any{"Note": "<string>"},
+		"Version":    "<string>",
+		"RepoSize":   "<string>",
+	}
+	if !reflect.DeepEqual(doc, wantDoc) {
+		t.Errorf("the embedded structs should be promoted as encoding/json does: got %v, want %v", doc, wantDoc)
+	}
+}
+
+func TestApplyPresence(t *testing.T) {
+	endp := &Endpoint{Name: "/presence/v0/test"}
+	endp.WithResponse(presenceOutput{})
+	var doc any
+	if err := json.Unmarshal([]byte(endp.Response), &doc); err != nil {
+		t.Fatal(err)
+	}
+	schema := applyPresence(endp.ResponseFields, "", doc, genSchemaOrRefForResponse(&warnings{}, nil, doc, true)).Schema
+
+	if want := []string{"Items", "Meta", "Root"}; !slices.Equal(schema.Required, want) {
+		t.Errorf("got required fields %v, want %v", schema.Required, want)
+	}
+	items := schema.Properties["Items"].Schema
+	if items.Nullable == nil || !*items.Nullable {
+		t.Errorf("Items should be nullable")
+	}
+	item := items.Items.Schema
+	if want := []string{"Name", "Parent"}; !slices.Equal(item.Required, want) {
+		t.Errorf("got required item fields %v, want %v", item.Required, want)
+	}
+	if links := item.Properties["Links"].Schema; links.Nullable != nil {
+		t.Errorf("Links is left out rather than null")
+	}
+	// The CID object, described by JsondocGlossary, has no known fields.
+	if root := schema.Properties["Root"]; root.Schema != nil && root.Schema.Required != nil {
+		t.Errorf("the fields of Root are unknown, got required %v", root.Schema.Required)
+	}
+
+	ref := nullable(schemaRef(cidSchemaName))
+	if ref.Schema == nil || ref.Schema.Nullable == nil || !*ref.Schema.Nullable || ref.Schema.Type == nil || *ref.Schema.Type != openapi3.SchemaTypeString {
+		t.Errorf("nullable references should be inlined with their type, got %+v", ref)
+	}
+	if shared := sharedSchemas()[cidSchemaName].Schema; shared.Nullable != nil {
+		t.Errorf("inlining should not make the shared schema nullable")
+	}
+	if s := nullable(&openapi3.SchemaOrRef{Schema: stringSchema()}); s.Schema.Nullable == nil || !*s.Schema.Nullable {
+		t.Errorf("nullable schemas should be marked")
+	}
+}
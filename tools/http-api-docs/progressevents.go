@@ -0,0 +1,45 @@
+package docs
+
+import (
+	"encoding/json"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// genProgressEvents adds the component schemas of the events of an
+// endpoint (see progressEvents) to schemas, and returns the oneOf schema
+// referencing them, or nil if the endpoint has none. Like the variants, each
+// event forbids the Required fields of the others which it doesn't have.
+func (myself *OpenAPIFormatter) genProgressEvents(w *warnings, schemas map[string]*openapi3.Schema, endp *Endpoint) *openapi3.SchemaOrRef {
+	events := progressEvents[endp.Name]
+	if len(events) == 0 {
+		return nil
+	}
+	var distinctive []string
+	for _, ev := range events {
+		distinctive = append(distinctive, ev.Required...)
+	}
+
+	oneOf := make([]openapi3.SchemaOrRef, 0, len(events))
+	for _, ev := range events {
+		var doc any
+		if err := json.Unmarshal([]byte(ev.Response), &doc); err != nil {
+			w.warnf("Couldn't parse JSON for the event %s: %s", ev.Schema, err)
+			return nil
+		}
+		schema := applyPresence(endp.ResponseFields, "", doc, applyUnits(endp.Name, "", doc, myself.applyTimeFormats(endp.Name, "", doc, genSchemaOrRefForResponse(w, myself.TypeMap, doc, true))))
+		if schema == nil || schema.Schema == nil {
+			w.warnf("The event %s is not an object", ev.Schema)
+			return nil
+		}
+		s := schema.Schema
+		requireDistinctive(s, ev.Required, distinctive)
+		if ev.Description != "" {
+			s.WithDescription(ev.Description)
+		}
+		myself.setProvenance(s, ProvenanceManual)
+		schemas[ev.Schema] = s
+		oneOf = append(oneOf, *schemaRef(ev.Schema))
+	}
+	return &openapi3.SchemaOrRef{Schema: &openapi3.Schema{OneOf: oneOf}}
+}
@@ -0,0 +1,37 @@
+package docs
+
+import (
+	"context"
+	"testing"
+)
+
+func TestProgressEvents(t *testing.T) {
+	var api []*Endpoint
+	for _, endp := range AllEndpoints() {
+		if endp.Name == "/api/v0/add" {
+			api = append(api, endp)
+		}
+	}
+	formatter := new(OpenAPIFormatter)
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+	schemas := formatter.spec.Components.Schemas.MapOfSchemaOrRefValues
+	schema := schemas[formatter.schemaNames[api[0].ResponseType]].Schema
+	if schema == nil || len(schema.OneOf) != 2 || schema.OneOf[0].SchemaReference.Ref != "#/components/schemas/AddProgressEvent" {
+		t.Fatalf("expected references to the two events of add, got %+v", schema)
+	}
+	progress, result := schemas["AddProgressEvent"].Schema, schemas["AddResultEvent"].Schema
+	if progress == nil || result == nil {
+		t.Fatalf("missing event schemas")
+	}
+	if _, ok := progress.Properties["Hash"]; ok || progress.Not == nil || progress.Not.Schema.Required[0] != "Hash" {
+		t.Errorf("the progress event should forbid Hash, got %+v", progress)
+	}
+	if result.Not == nil || result.Not.Schema.Required[0] != "Bytes" {
+		t.Errorf("the result event should forbid Bytes, got %+v", result)
+	}
+	if bytes := progress.Properties["Bytes"].Schema; bytes.MapOfAnything["x-unit"] != UnitBytes {
+		t.Errorf("the fields of the events should have their units, got %+v", bytes)
+	}
+}
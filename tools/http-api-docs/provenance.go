@@ -0,0 +1,29 @@
+package docs
+
+import "github.com/swaggest/openapi-go/openapi3"
+
+// Provenance tells where a schema of the spec comes from, so that reviewers
+// can spot the lowest-quality ones. It is emitted as x-provenance.
+type Provenance string
+
+const (
+	// ProvenancePlaceholder is for response schemas inferred from the
+	// placeholder document (e.g. "<string>") generated for the Go type.
+	ProvenancePlaceholder Provenance = "doc-placeholder"
+	// ProvenanceOption is for parameter schemas derived from the type of
+	// the cmds arguments and options.
+	ProvenanceOption Provenance = "cmds-option"
+	// ProvenanceManual is for schemas written by hand in this generator.
+	ProvenanceManual Provenance = "manual"
+	// ProvenanceOverlay is for schemas given in the overlay.
+	ProvenanceOverlay Provenance = "overlay"
+)
+
+// setProvenance records the provenance of a schema, unless StripProvenance
+// is set.
+func (myself *OpenAPIFormatter) setProvenance(schema *openapi3.Schema, p Provenance) {
+	if myself.StripProvenance || schema == nil {
+		return
+	}
+	schema.WithMapOfAnythingItem("x-provenance", string(p))
+}
@@ -0,0 +1,173 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+)
+
+// quickstartEndpoints are the endpoints walked through in the quickstart,
+// with the sample values used for their arguments in the examples.
+var quickstartEndpoints = []struct {
+	name    string
+	title   string
+	intro   string
+	samples map[string]string
+}{
+	{
+		name:  APIPrefix + "/id",
+		title: "Show the identity of your node",
+		intro: "The simplest call takes no arguments at all and tells you who your node is:",
+	},
+	{
+		name:    APIPrefix + "/add",
+		title:   "Add a file",
+		intro:   "Files are uploaded as `multipart/form-data`. The response contains the CID of the added file:",
+		samples: map[string]string{"path": "hello.txt"},
+	},
+	{
+		name:    APIPrefix + "/cat",
+		title:   "Read it back",
+		intro:   "Positional arguments are passed with the `arg` query parameter. Use the `Hash` returned by `add`:",
+		samples: map[string]string{"ipfs-path": "<cid>"},
+	},
+}
+
+// QuickstartFormatter generates a "Getting started with the RPC API" page.
+type QuickstartFormatter struct{}
+
+// Generate returns the page. The walkthrough uses the given endpoints, so
+// examples and the listed parameters always match the reference.
+func (qf *QuickstartFormatter) Generate(ctx context.Context, api []*Endpoint) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	byName := make(map[string]*Endpoint, len(api))
+	for _, endp := range api {
+		byName[endp.Name] = endp
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, `---
+title: Getting started with the Kubo RPC API
+description: Your first calls to the RPC API of a Kubo IPFS daemon.
+---
+
+# Getting started with the Kubo RPC API
+
+<!-- DO NOT EDIT THIS FILE. It is generated by http-api-docs -formatter=quickstart. -->
+
+::: tip Generated from kubo v%s
+This guide was generated from the same command definitions as the [RPC API reference](./rpc.md).
+:::
+
+## Enable the API
+
+The RPC API is served by the Kubo daemon. Start it with:
+
+`+"```sh"+`
+> ipfs daemon
+`+"```"+`
+
+By default, it listens on `+"`127.0.0.1:5001`"+` (see `+"`Addresses.API`"+` in the config). All calls are `+"`POST`"+` requests to endpoints under `+"`/api/v0/`"+`.
+
+## Authentication
+
+The RPC API grants admin-level access to your node and is bound to localhost on purpose. Never expose it to the public internet.
+
+If you need remote access, configure `+"`API.Authorizations`"+` (Kubo 0.25 and later) and send the matching secret with every request:
+
+`+"```sh"+`
+> curl -X POST -H "Authorization: Bearer <secret>" "http://127.0.0.1:5001/api/v0/id"
+`+"```"+`
+
+## Your first calls
+`, IPFSVersion())
+
+	for _, qs := range quickstartEndpoints {
+		endp, ok := byName[qs.name]
+		if !ok {
+			log.Printf("WARN: Quickstart endpoint %s does not exist\n", qs.name)
+			continue
+		}
+		fmt.Fprintf(buf, "\n### %s\n\n%s\n\n", qs.title, qs.intro)
+		fmt.Fprintf(buf, "```sh\n> %s\n```\n\n", quickstartCurl(endp, qs.samples, nil))
+		fmt.Fprint(buf, quickstartResponse(endp))
+		if len(endp.Options) > 0 {
+			fmt.Fprintf(buf, "`%s` also accepts these optional parameters:\n\n", strings.TrimPrefix(endp.Name, APIPrefix+"/"))
+			for _, opt := range endp.Options {
+				fmt.Fprintf(buf, "- `%s`: %s\n", opt.Name, html.EscapeString(fixDesc.ReplaceAllString(opt.Description, "")))
+			}
+			fmt.Fprintln(buf)
+		}
+		fmt.Fprintf(buf, "See [`%s`](./rpc.md#%s) in the reference for details.\n", endp.Name, endpointAnchor(endp.Name))
+	}
+
+	fmt.Fprint(buf, `
+## Handling streaming responses
+
+Some endpoints, like `+"`add`"+` with `+"`progress=true`"+`, `+"`ping`"+` or `+"`log/tail`"+`, do not return a single JSON document. Instead, they stream newline-delimited JSON (ndjson): one JSON object per line, sent as soon as it is available.
+
+Read such responses line by line instead of waiting for the whole body:
+
+`+"```sh"+`
+> curl -sN -X POST "http://127.0.0.1:5001/api/v0/ping?arg=<peer-id>&count=3" | while read -r line; do echo "$line" | jq .Text; done
+`+"```"+`
+
+Errors which happen after streaming started can't change the status code anymore. They are reported in the `+"`X-Stream-Error`"+` trailer instead, so check it after reading the body.
+
+## Next steps
+
+Every CLI command is available over the RPC API. Browse the [RPC API reference](./rpc.md) for the full list of endpoints.
+`)
+	return buf.String(), nil
+}
+
+// quickstartResponse shows the response of an endpoint: JSON examples are
+// fenced, and the sentences standing for text responses are kept as prose.
+func quickstartResponse(endp *Endpoint) string {
+	response := strings.TrimSpace(endp.Response)
+	switch {
+	case response == "":
+		return ""
+	case json.Valid([]byte(response)):
+		return fmt.Sprintf("The response looks like this:\n\n```json\n%s\n```\n\n", response)
+	default:
+		return response + "\n\n"
+	}
+}
+
+// quickstartCurl builds a minimal curl invocation for an endpoint, passing
+// only the required arguments with the given sample values, and the given
+// options.
+func quickstartCurl(endp *Endpoint, samples map[string]string, options []sampleOption) string {
+	var flags []string
+	var query []string
+	for _, arg := range endp.Arguments {
+		if !arg.Required {
+			continue
+		}
+		value, ok := samples[arg.Name]
+		if !ok {
+			value = "<" + arg.Name + ">"
+		}
+		if arg.Type == "file" {
+			flags = append(flags, "-F file=@"+value)
+		} else {
+			query = append(query, "arg="+value)
+		}
+	}
+	for _, opt := range options {
+		query = append(query, opt.arg.Name+"="+opt.value)
+	}
+
+	url := "http://127.0.0.1:5001" + endp.Name
+	if len(query) > 0 {
+		url += "?" + strings.Join(query, "&")
+	}
+	return strings.Join(append(append([]string{"curl -X POST"}, flags...), `"`+url+`"`), " ")
+}
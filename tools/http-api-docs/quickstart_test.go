@@ -0,0 +1,29 @@
+package docs
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestQuickstart(t *testing.T) {
+	out, err := new(QuickstartFormatter).Generate(context.Background(), AllEndpoints())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`curl -X POST "http://127.0.0.1:5001/api/v0/id"`,
+		`curl -X POST -F file=@hello.txt "http://127.0.0.1:5001/api/v0/add"`,
+		`curl -X POST "http://127.0.0.1:5001/api/v0/cat?arg=<cid>"`,
+		"(./rpc.md#api-v0-add)",
+		"```json\n{\n  \"Bytes\": \"<int64>\",",
+		"\n" + textResponse + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("quickstart does not contain %q", want)
+		}
+	}
+	if strings.Contains(out, "```json\n"+textResponse) {
+		t.Errorf("the text response of cat should not be fenced as JSON")
+	}
+}
@@ -0,0 +1,58 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+)
+
+// Policies for the removed endpoints in the OpenAPI spec (see
+// OpenAPIFormatter.Removed). The daemon still routes them, but only answers
+// with an error.
+const (
+	// RemovedDeprecate documents them as deprecated operations, with the
+	// x-removed extension.
+	RemovedDeprecate = "deprecate"
+	// RemovedOmit leaves them out of the spec.
+	RemovedOmit = "omit"
+	// RemovedAppendix leaves them out of the operations and lists them at
+	// the end of the description of the spec.
+	RemovedAppendix = "appendix"
+)
+
+// RemovedPolicies lists the accepted policies.
+var RemovedPolicies = []string{RemovedDeprecate, RemovedOmit, RemovedAppendix}
+
+// splitRemoved returns the endpoints to generate operations for under the
+// given policy, and the removed endpoints left out.
+func splitRemoved(api []*Endpoint, policy string) (kept, removed []*Endpoint, err error) {
+	switch policy {
+	case "", RemovedDeprecate:
+		return api, nil, nil
+	case RemovedOmit, RemovedAppendix:
+	default:
+		return nil, nil, fmt.Errorf("unknown policy %q for the removed endpoints, expected one of %s", policy, strings.Join(RemovedPolicies, ", "))
+	}
+	for _, endp := range api {
+		if endp.Status == cmds.Removed {
+			removed = append(removed, endp)
+		} else {
+			kept = append(kept, endp)
+		}
+	}
+	return kept, removed, nil
+}
+
+// removedAppendix lists the removed endpoints, as CommonMark.
+func removedAppendix(removed []*Endpoint) string {
+	var b strings.Builder
+	b.WriteString("## Removed endpoints\n\nThese commands were removed from Kubo and answer with an error. They are not documented as operations.\n")
+	for _, endp := range removed {
+		fmt.Fprintf(&b, "\n- `%s`", endp.Name)
+		if endp.Description != "" {
+			fmt.Fprintf(&b, ": %s", endp.Description)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package docs
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRemovedPolicy(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	for _, tc := range []struct {
+		policy            string
+		operation, listed bool
+	}{
+		{"", true, false},
+		{RemovedDeprecate, true, false},
+		{RemovedOmit, false, false},
+		{RemovedAppendix, false, true},
+	} {
+		formatter := &OpenAPIFormatter{Removed: tc.policy}
+		if err := formatter.Build(context.Background(), api); err != nil {
+			t.Fatal(err)
+		}
+		path, ok := formatter.spec.Paths.MapOfPathItemValues["/fixture/v0/removed"]
+		if ok != tc.operation {
+			t.Errorf("%q: operation %v, want %v", tc.policy, ok, tc.operation)
+		}
+		if ok && path.MapOfOperationValues["post"].MapOfAnything["x-removed"] != true {
+			t.Errorf("%q: the operation should be marked as removed", tc.policy)
+		}
+		listed := strings.Contains(*formatter.spec.Info.Description, "- `/fixture/v0/removed`: Removed command.")
+		if listed != tc.listed {
+			t.Errorf("%q: listed in the description %v, want %v", tc.policy, listed, tc.listed)
+		}
+	}
+
+	if err := (&OpenAPIFormatter{Removed: "hide"}).Build(context.Background(), api); err == nil {
+		t.Error("expected an error for an unknown policy")
+	}
+}
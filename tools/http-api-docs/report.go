@@ -0,0 +1,120 @@
+package docs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// Report tells how well the endpoints are documented, to track the quality
+// of the generated docs over time. It is meant to be written as JSON.
+type Report struct {
+	KuboVersion string `json:"kuboVersion,omitempty"`
+	Endpoints   int    `json:"endpoints"`
+	Options     int    `json:"options"`
+
+	// UnparsableResponses are the endpoints whose Response is not valid
+	// JSON, so that no schema can be derived from it.
+	UnparsableResponses []ReportItem `json:"unparsableResponses"`
+	// UnsupportedArguments are the arguments and options of a type which
+	// has no schema, documented as strings.
+	UnsupportedArguments []ReportItem `json:"unsupportedArguments"`
+	// NoResponseSchema are the endpoints whose response is documented
+	// without a schema.
+	NoResponseSchema []ReportItem `json:"noResponseSchema"`
+	// UndocumentedOptions are the options without a description.
+	UndocumentedOptions []ReportItem `json:"undocumentedOptions"`
+	// CLIOnly are the commands of the CLI which are not available over the
+	// RPC API, so they have no endpoint.
+	CLIOnly []CLIOnlyCommand `json:"cliOnly"`
+	// Failures are the endpoints which couldn't be generated at all.
+	Failures []ReportItem `json:"failures"`
+	// Warnings are all the warnings of the generation, including the
+	// ones above.
+	Warnings []Warning `json:"warnings"`
+}
+
+// ReportItem is an endpoint, or an argument or option of it (Name), found
+// by a Report.
+type ReportItem struct {
+	Endpoint string `json:"endpoint"`
+	Name     string `json:"name,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// Report generates the operations of the given endpoints and reports on
+// what is missing from them.
+func (myself *OpenAPIFormatter) Report(ctx context.Context, api []*Endpoint) (*Report, error) {
+	if err := myself.Build(ctx, api); err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		KuboVersion:          myself.KuboVersion,
+		Endpoints:            len(api),
+		UnparsableResponses:  []ReportItem{},
+		UnsupportedArguments: []ReportItem{},
+		NoResponseSchema:     []ReportItem{},
+		UndocumentedOptions:  []ReportItem{},
+		CLIOnly:              CLIOnlyCommands(),
+		Failures:             []ReportItem{},
+		Warnings:             append([]Warning{}, myself.Warnings...),
+	}
+	for _, f := range myself.Failures {
+		report.Failures = append(report.Failures, ReportItem{Endpoint: f.Endpoint, Detail: f.Err.Error()})
+	}
+	for _, endp := range api {
+		for _, arg := range append(append([]*Argument{}, endp.Arguments...), endp.Options...) {
+			if arg.Type == "file" {
+				continue
+			}
+			if _, ok := kindSchemas[argumentKind(arg)]; !ok {
+				report.UnsupportedArguments = append(report.UnsupportedArguments, ReportItem{Endpoint: endp.Name, Name: arg.Name, Detail: arg.Type})
+			}
+		}
+		for _, opt := range endp.Options {
+			report.Options++
+			if opt.Description == "" {
+				report.UndocumentedOptions = append(report.UndocumentedOptions, ReportItem{Endpoint: endp.Name, Name: opt.Name})
+			}
+		}
+
+		if endp.Response != "" && endp.Response != textResponse {
+			var x any
+			if err := json.Unmarshal([]byte(endp.Response), &x); err != nil {
+				report.UnparsableResponses = append(report.UnparsableResponses, ReportItem{Endpoint: endp.Name, Detail: err.Error()})
+			}
+		}
+		op, ok := myself.spec.Paths.MapOfPathItemValues[endp.Name].MapOfOperationValues["post"]
+		if !ok {
+			continue
+		}
+		if detail := missingResponseSchema(op.Responses.MapOfResponseOrRefValues["200"].Response); detail != "" {
+			report.NoResponseSchema = append(report.NoResponseSchema, ReportItem{Endpoint: endp.Name, Detail: detail})
+		}
+	}
+	sort.SliceStable(report.Warnings, func(i, j int) bool { return report.Warnings[i].Endpoint < report.Warnings[j].Endpoint })
+	return report, nil
+}
+
+// missingResponseSchema tells why a response has no schema, or returns ""
+// if it has one. Plain text responses don't need one.
+func missingResponseSchema(resp *openapi3.Response) string {
+	if resp == nil {
+		return "no response documented"
+	}
+	var mimes []string
+	for mime, media := range resp.Content {
+		if mime != "text/plain" && media.Schema == nil {
+			mimes = append(mimes, mime)
+		}
+	}
+	if len(mimes) == 0 {
+		return ""
+	}
+	sort.Strings(mimes)
+	return fmt.Sprintf("no schema for %v", mimes)
+}
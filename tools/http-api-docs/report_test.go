@@ -0,0 +1,54 @@
+package docs
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	api := []*Endpoint{
+		{
+			Name:        "/report/v0/good",
+			Description: "Documented.",
+			Options:     []*Argument{{Name: "verbose", Type: "bool", Description: "Be verbose."}},
+			Response:    `{"Name": "<string>"}`,
+		},
+		{
+			Name:        "/report/v0/broken",
+			Description: "Not documented.",
+			Arguments:   []*Argument{{Name: "when", Type: "time"}},
+			Options:     []*Argument{{Name: "quiet", Type: "bool"}},
+			Response:    `{"Name": `,
+		},
+		{
+			Name:        "/report/v0/text",
+			Description: "Text.",
+			Response:    textResponse,
+		},
+	}
+	report, err := new(OpenAPIFormatter).Report(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Endpoints != 3 || report.Options != 2 {
+		t.Errorf("got %d endpoints and %d options, want 3 and 2", report.Endpoints, report.Options)
+	}
+	broken := "/report/v0/broken"
+	if len(report.UnparsableResponses) != 1 || report.UnparsableResponses[0].Endpoint != broken {
+		t.Errorf("unexpected unparsable responses %v", report.UnparsableResponses)
+	}
+	if want := []ReportItem{{Endpoint: broken, Name: "when", Detail: "time"}}; !reflect.DeepEqual(report.UnsupportedArguments, want) {
+		t.Errorf("got unsupported arguments %v, want %v", report.UnsupportedArguments, want)
+	}
+	if want := []ReportItem{{Endpoint: broken, Detail: "no response documented"}}; !reflect.DeepEqual(report.NoResponseSchema, want) {
+		t.Errorf("got endpoints without response schema %v, want %v", report.NoResponseSchema, want)
+	}
+	if want := []ReportItem{{Endpoint: broken, Name: "quiet"}}; !reflect.DeepEqual(report.UndocumentedOptions, want) {
+		t.Errorf("got undocumented options %v, want %v", report.UndocumentedOptions, want)
+	}
+	if len(report.Warnings) != 2 {
+		t.Errorf("expected the warnings about the type and the response, got %v", report.Warnings)
+	}
+}
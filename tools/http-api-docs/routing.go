@@ -0,0 +1,204 @@
+package docs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// This file describes the Delegated Routing V1 HTTP API
+// (https://specs.ipfs.tech/routing/http-routing-v1/), which Kubo serves on
+// the gateway with Gateway.ExposeRoutingAPI. Like the gateway, it is
+// modeled by hand.
+
+// RoutingFormatter generates the OpenAPI spec of the Routing V1 HTTP API.
+// It references the CIDString, PeerID and Multiaddr schemas shared with
+// the RPC spec.
+type RoutingFormatter struct {
+	// Info overrides the default metadata. The default server is
+	// http://127.0.0.1:8080.
+	Info OpenAPIInfo
+}
+
+const (
+	peerRecordSchemaName = "PeerRecord"
+	mimeIPNSRecord       = "application/vnd.ipfs.ipns-record"
+)
+
+// routingErrors are the error responses of the Routing V1 API, with a
+// text/plain body.
+var routingErrors = []struct {
+	status      int
+	description string
+}{
+	{http.StatusBadRequest, "Invalid CID, peer ID, IPNS name or record."},
+	{http.StatusNotFound, "No results were found."},
+	{http.StatusInternalServerError, "The router failed."},
+}
+
+// Generate returns the spec as YAML. The routing API is not made of
+// commands, so the endpoints are ignored.
+func (rf *RoutingFormatter) Generate(ctx context.Context, _ []*Endpoint) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	spec, err := rf.genSpec()
+	if err != nil {
+		return "", err
+	}
+	out, err := spec.MarshalYAML()
+	return string(out), err
+}
+
+func (rf *RoutingFormatter) genSpec() (*openapi3.Spec, error) {
+	info := rf.Info
+	if info.Title == "" {
+		info.Title = "IPFS Delegated Routing V1 HTTP API"
+	}
+	if info.Version == "" {
+		info.Version = IPFSVersion()
+	}
+	if info.Description == "" {
+		info.Description = "Content, peer and IPNS routing over HTTP, served by Kubo on the gateway when Gateway.ExposeRoutingAPI is enabled, as specified by https://specs.ipfs.tech/routing/http-routing-v1/."
+	}
+	if len(info.Servers) == 0 {
+		info.Servers = []string{"http://127.0.0.1:8080"}
+	}
+
+	spec := &openapi3.Spec{Openapi: "3.0.0"}
+	spec.Info.WithTitle(info.Title).WithVersion(info.Version).WithDescription(info.Description)
+	for _, url := range info.Servers {
+		spec.Servers = append(spec.Servers, openapi3.Server{URL: url})
+	}
+	spec.WithExternalDocs(openapi3.ExternalDocumentation{URL: "https://specs.ipfs.tech/routing/http-routing-v1/"})
+
+	schemas := sharedSchemas()
+	schemas[peerRecordSchemaName] = openapi3.SchemaOrRef{Schema: genPeerRecordSchema()}
+	spec.ComponentsEns().SchemasEns().WithMapOfSchemaOrRefValues(schemas)
+
+	ops := []struct {
+		method, path string
+		op           openapi3.Operation
+	}{
+		{http.MethodGet, "/routing/v1/providers/{cid}", genRecordsOperation("getProviders", "Find the providers of a CID",
+			"Providers", routingPathParam("cid", "CID of the content to find.", cidSchemaName))},
+		{http.MethodGet, "/routing/v1/peers/{peer-id}", genRecordsOperation("getPeers", "Find the addresses of a peer",
+			"Peers", routingPathParam("peer-id", "ID of the peer to find.", peerIDSchemaName))},
+		{http.MethodGet, "/routing/v1/ipns/{name}", genGetIPNSOperation()},
+		{http.MethodPut, "/routing/v1/ipns/{name}", genPutIPNSOperation()},
+	}
+	for _, o := range ops {
+		if err := spec.AddOperation(o.method, o.path, o.op); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", o.method, o.path, err)
+		}
+	}
+	return spec, nil
+}
+
+// genPeerRecordSchema returns the schema of the peer records of the
+// providers and peers responses.
+func genPeerRecordSchema() *openapi3.Schema {
+	array := func(items *openapi3.SchemaOrRef) openapi3.SchemaOrRef {
+		return openapi3.SchemaOrRef{Schema: (&openapi3.Schema{}).WithType(openapi3.SchemaTypeArray).WithItems(*items)}
+	}
+	schema := (&openapi3.Schema{}).
+		WithType(openapi3.SchemaTypeObject).
+		WithDescription("A peer, with its known addresses and protocols. Records of other schemas may be returned, and should be skipped by clients which don't know them.").
+		WithRequired("Schema", "ID").
+		WithProperties(map[string]openapi3.SchemaOrRef{
+			"Schema":    {Schema: stringSchema("peer")},
+			"ID":        *schemaRef(peerIDSchemaName),
+			"Addrs":     array(schemaRef(multiaddrSchemaName)),
+			"Protocols": array(&openapi3.SchemaOrRef{Schema: stringSchema()}),
+		})
+	return schema
+}
+
+// routingPathParam returns a path parameter referencing a shared schema.
+func routingPathParam(name, description, schemaName string) openapi3.ParameterOrRef {
+	return openapi3.ParameterOrRef{Parameter: &openapi3.Parameter{
+		Name:        name,
+		In:          openapi3.ParameterInPath,
+		Description: &description,
+		Required:    ptr(true),
+		Schema:      schemaRef(schemaName),
+	}}
+}
+
+// ipnsNameParam is the path parameter of the IPNS operations.
+func ipnsNameParam() openapi3.ParameterOrRef {
+	return specParam{"name", openapi3.ParameterInPath, "IPNS name, as a CIDv1 of the libp2p-key codec.", true, stringSchema()}.parameter()
+}
+
+// genRecordsOperation returns an operation listing peer records in the
+// field of the JSON response, or streaming them as NDJSON.
+func genRecordsOperation(id, summary, field string, pathParam openapi3.ParameterOrRef) openapi3.Operation {
+	op := openapi3.Operation{ID: &id, Summary: &summary}
+	op.Parameters = append(op.Parameters, pathParam,
+		specParam{"Accept", openapi3.ParameterInHeader, "`application/x-ndjson` streams the records as they are found.", false, stringSchema("application/json", "application/x-ndjson")}.parameter(),
+		specParam{"filter-addrs", openapi3.ParameterInQuery, "Comma-separated list of multiaddr protocols (e.g. `tcp,quic-v1`) the addresses must have, `!` excluding one, and `unknown` keeping the records without addresses.", false, stringSchema()}.parameter(),
+		specParam{"filter-protocols", openapi3.ParameterInQuery, "Comma-separated list of transfer protocols (e.g. `transport-bitswap`) the records must have, with `unknown` keeping the records without protocols.", false, stringSchema()}.parameter(),
+	)
+
+	body := (&openapi3.Schema{}).
+		WithType(openapi3.SchemaTypeObject).
+		WithProperties(map[string]openapi3.SchemaOrRef{
+			field: {Schema: (&openapi3.Schema{}).WithType(openapi3.SchemaTypeArray).WithItems(*schemaRef(peerRecordSchemaName))},
+		})
+	ok := openapi3.Response{
+		Description: "The records found.",
+		Content: map[string]openapi3.MediaType{
+			"application/json": {Schema: &openapi3.SchemaOrRef{Schema: body}},
+			mimeNDJSON:         {Schema: schemaRef(peerRecordSchemaName)},
+		},
+	}
+	op.Responses.WithMapOfResponseOrRefValuesItem("200", openapi3.ResponseOrRef{Response: &ok})
+	addRoutingErrors(&op)
+	return op
+}
+
+func genGetIPNSOperation() openapi3.Operation {
+	id, summary := "getIPNS", "Retrieve the IPNS record of a name"
+	op := openapi3.Operation{ID: &id, Summary: &summary}
+	op.Parameters = append(op.Parameters, ipnsNameParam())
+	ok := openapi3.Response{
+		Description: "The signed IPNS record.",
+		Content: map[string]openapi3.MediaType{
+			mimeIPNSRecord: {Schema: &openapi3.SchemaOrRef{Schema: stringSchema().WithFormat("binary")}},
+		},
+	}
+	op.Responses.WithMapOfResponseOrRefValuesItem("200", openapi3.ResponseOrRef{Response: &ok})
+	addRoutingErrors(&op)
+	return op
+}
+
+func genPutIPNSOperation() openapi3.Operation {
+	id, summary := "putIPNS", "Publish the IPNS record of a name"
+	op := openapi3.Operation{ID: &id, Summary: &summary}
+	op.Parameters = append(op.Parameters, ipnsNameParam())
+	op.RequestBody = &openapi3.RequestBodyOrRef{RequestBody: &openapi3.RequestBody{
+		Required: ptr(true),
+		Content: map[string]openapi3.MediaType{
+			mimeIPNSRecord: {Schema: &openapi3.SchemaOrRef{Schema: stringSchema().WithFormat("binary")}},
+		},
+	}}
+	op.Responses.WithMapOfResponseOrRefValuesItem("200", openapi3.ResponseOrRef{Response: &openapi3.Response{
+		Description: "The record was published.",
+	}})
+	addRoutingErrors(&op)
+	return op
+}
+
+func addRoutingErrors(op *openapi3.Operation) {
+	for _, e := range routingErrors {
+		op.Responses.WithMapOfResponseOrRefValuesItem(strconv.Itoa(e.status), openapi3.ResponseOrRef{Response: &openapi3.Response{
+			Description: e.description,
+			Content: map[string]openapi3.MediaType{
+				"text/plain": {Schema: &openapi3.SchemaOrRef{Schema: stringSchema()}},
+			},
+		}})
+	}
+}
@@ -0,0 +1,68 @@
+package docs
+
+import (
+	"context"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRoutingFormatter(t *testing.T) {
+	out, err := new(RoutingFormatter).Generate(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type schema struct {
+		Ref        string `yaml:"$ref"`
+		Properties map[string]schema
+		Items      *schema
+	}
+	var spec struct {
+		Paths map[string]map[string]struct {
+			OperationID string `yaml:"operationId"`
+			Parameters  []struct {
+				Name   string
+				Schema schema
+			}
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema schema
+				}
+			}
+		}
+		Components struct {
+			Schemas map[string]schema
+		}
+	}
+	if err := yaml.Unmarshal([]byte(out), &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{cidSchemaName, peerIDSchemaName, multiaddrSchemaName, peerRecordSchemaName} {
+		if _, ok := spec.Components.Schemas[name]; !ok {
+			t.Errorf("missing component schema %s", name)
+		}
+	}
+	record := spec.Components.Schemas[peerRecordSchemaName]
+	if record.Properties["ID"].Ref != "#/components/schemas/PeerID" || record.Properties["Addrs"].Items.Ref != "#/components/schemas/Multiaddr" {
+		t.Errorf("PeerRecord doesn't reference the shared schemas: %+v", record)
+	}
+
+	providers := spec.Paths["/routing/v1/providers/{cid}"]["get"]
+	if providers.Parameters[0].Name != "cid" || providers.Parameters[0].Schema.Ref != "#/components/schemas/CIDString" {
+		t.Errorf("unexpected cid parameter: %+v", providers.Parameters[0])
+	}
+	content := providers.Responses["200"].Content
+	if content["application/json"].Schema.Properties["Providers"].Items.Ref != "#/components/schemas/PeerRecord" {
+		t.Errorf("unexpected JSON response: %+v", content["application/json"])
+	}
+	if content[mimeNDJSON].Schema.Ref != "#/components/schemas/PeerRecord" {
+		t.Errorf("unexpected NDJSON response: %+v", content[mimeNDJSON])
+	}
+	for path, method := range map[string]string{"/routing/v1/peers/{peer-id}": "get", "/routing/v1/ipns/{name}": "put"} {
+		if _, ok := spec.Paths[path][method]; !ok {
+			t.Errorf("missing %s %s", method, path)
+		}
+	}
+}
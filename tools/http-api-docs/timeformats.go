@@ -0,0 +1,151 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// Representations of the times and durations of the responses (see
+// OpenAPIFormatter.TimeFormats).
+const (
+	// TimeDateTime is an RFC 3339 string, as encoding/json marshals
+	// time.Time.
+	TimeDateTime = "date-time"
+	// TimeUnix is an integer number of seconds since the Unix epoch.
+	TimeUnix = "unix"
+	// DurationNanoseconds is an integer number of nanoseconds, as
+	// encoding/json marshals time.Duration.
+	DurationNanoseconds = "duration-ns"
+	// DurationGo is a string as formatted by time.Duration.String, e.g.
+	// "1m30s".
+	DurationGo = "go-duration"
+)
+
+// DefaultTimeFormats are the representations of the placeholders of times
+// and durations in the documented responses, which are the ones of
+// encoding/json.
+var DefaultTimeFormats = map[string]string{
+	"<timestamp>":   TimeDateTime,
+	"<duration-ns>": DurationNanoseconds,
+}
+
+// timeSchema returns the schema of a representation of times or durations,
+// or nil for unknown ones.
+func timeSchema(format string) *openapi3.Schema {
+	switch format {
+	case TimeDateTime:
+		return stringSchema().WithFormat("date-time").WithExample("2024-01-02T15:04:05Z")
+	case TimeUnix:
+		return integerSchema().WithFormat("int64").
+			WithDescription("Seconds since the Unix epoch.").
+			WithExample(1704207845)
+	case DurationNanoseconds:
+		return integerSchema().WithFormat("int64").
+			WithMapOfAnythingItem("x-go-duration", "nanoseconds").
+			WithExample(1500000000)
+	case DurationGo:
+		return stringSchema().
+			WithMapOfAnythingItem("x-go-duration", "string").
+			WithExample("1.5s")
+	default:
+		return nil
+	}
+}
+
+func integerSchema() *openapi3.Schema {
+	t := openapi3.SchemaTypeInteger
+	return &openapi3.Schema{Type: &t}
+}
+
+// SetTimeFormat overrides the representation of the times or durations
+// documented by a placeholder, e.g. "<duration-ns>", or of a field of the
+// response of an endpoint, given as "/api/v0/swarm/peers:Peers.Latency".
+// The components of the path of a field are the names of the properties,
+// skipping arrays and maps.
+func (myself *OpenAPIFormatter) SetTimeFormat(key, format string) error {
+	if timeSchema(format) == nil {
+		return fmt.Errorf("unknown time format %q, expected %s, %s, %s or %s", format, TimeDateTime, TimeUnix, DurationNanoseconds, DurationGo)
+	}
+	if strings.HasPrefix(key, "<") {
+		if myself.TimeFormats == nil {
+			myself.TimeFormats = make(map[string]string)
+		}
+		myself.TimeFormats[key] = format
+		return nil
+	}
+	endpoint, path, ok := strings.Cut(key, ":")
+	if !ok || path == "" {
+		return fmt.Errorf("invalid time format key %q, expected a placeholder like <timestamp> or endpoint:field.path", key)
+	}
+	if myself.TimeFields == nil {
+		myself.TimeFields = make(map[string]map[string]string)
+	}
+	if myself.TimeFields[endpoint] == nil {
+		myself.TimeFields[endpoint] = make(map[string]string)
+	}
+	myself.TimeFields[endpoint][path] = format
+	return nil
+}
+
+// timeFormat returns the representation of the value x of the field at path
+// in the response of an endpoint, or "" if it is not a time.
+func (myself *OpenAPIFormatter) timeFormat(endpoint, path string, x any) string {
+	if format := myself.TimeFields[endpoint][path]; format != "" {
+		return format
+	}
+	if format := timeFieldFormats[endpoint][path]; format != "" {
+		return format
+	}
+	placeholder, ok := x.(string)
+	if !ok {
+		return ""
+	}
+	if format := myself.TimeFormats[placeholder]; format != "" {
+		return format
+	}
+	return DefaultTimeFormats[placeholder]
+}
+
+// applyTimeFormats replaces the schemas of the times and durations of a
+// documented response x, whose schema was generated by
+// genSchemaOrRefForResponse, by the ones of their configured
+// representation.
+func (myself *OpenAPIFormatter) applyTimeFormats(endpoint, path string, x any, schema *openapi3.SchemaOrRef) *openapi3.SchemaOrRef {
+	if schema == nil || schema.Schema == nil {
+		return schema
+	}
+	if format := myself.timeFormat(endpoint, path, x); format != "" {
+		// The type map is applied after the time formats.
+		if v, isString := x.(string); isString {
+			if _, overridden := myself.TypeMap[v]; !overridden {
+				return &openapi3.SchemaOrRef{Schema: timeSchema(format)}
+			}
+		}
+	}
+	s := schema.Schema
+	switch v := x.(type) {
+	case []any:
+		if len(v) == 1 && s.Items != nil {
+			s.Items = myself.applyTimeFormats(endpoint, path, v[0], s.Items)
+		}
+	case map[string]any:
+		if value, ok := v["<string>"]; ok && len(v) == 1 && s.AdditionalProperties != nil {
+			s.AdditionalProperties.SchemaOrRef = myself.applyTimeFormats(endpoint, path, value, s.AdditionalProperties.SchemaOrRef)
+			return schema
+		}
+		for k, value := range v {
+			prop, ok := s.Properties[k]
+			if !ok {
+				continue
+			}
+			fieldPath := k
+			if path != "" {
+				fieldPath = path + "." + k
+			}
+			s.Properties[k] = *myself.applyTimeFormats(endpoint, fieldPath, value, &prop)
+		}
+	}
+	return schema
+}
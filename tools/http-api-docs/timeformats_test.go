@@ -0,0 +1,50 @@
+package docs
+
+import (
+	"testing"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+func TestTimeFormats(t *testing.T) {
+	doc := map[string]any{
+		"Peers":   []any{map[string]any{"Latency": "<string>", "Seen": "<timestamp>"}},
+		"TTL":     "<duration-ns>",
+		"Entries": map[string]any{"<string>": "<duration-ns>"},
+	}
+	formatter := new(OpenAPIFormatter)
+	if err := formatter.SetTimeFormat("<duration-ns>", DurationGo); err != nil {
+		t.Fatal(err)
+	}
+	if err := formatter.SetTimeFormat("/api/v0/test:Peers.Latency", DurationGo); err != nil {
+		t.Fatal(err)
+	}
+	s := formatter.applyTimeFormats("/api/v0/test", "", doc, genSchemaOrRefForResponse(nil, nil, doc, true)).Schema
+
+	peer := s.Properties["Peers"].Schema.Items.Schema
+	if seen := peer.Properties["Seen"].Schema; seen.Format == nil || *seen.Format != "date-time" {
+		t.Errorf("timestamps should be date-time strings, got %+v", seen)
+	}
+	for name, field := range map[string]*openapi3.Schema{
+		"Peers.Latency": peer.Properties["Latency"].Schema,
+		"TTL":           s.Properties["TTL"].Schema,
+		"Entries":       s.Properties["Entries"].Schema.AdditionalProperties.SchemaOrRef.Schema,
+	} {
+		if field.MapOfAnything["x-go-duration"] != "string" {
+			t.Errorf("%s should be a Go duration string, got %+v", name, field)
+		}
+	}
+
+	stat := map[string]any{"Mtime": "<int64>"}
+	s = new(OpenAPIFormatter).applyTimeFormats("/api/v0/files/stat", "", stat, genSchemaOrRefForResponse(nil, nil, stat, true)).Schema
+	if s.Properties["Mtime"].Schema.Description == nil {
+		t.Errorf("Mtime should be documented as Unix seconds")
+	}
+
+	if err := formatter.SetTimeFormat("<timestamp>", "rfc1123"); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+	if err := formatter.SetTimeFormat("Peers.Latency", DurationGo); err == nil {
+		t.Errorf("expected an error for a field without endpoint")
+	}
+}
@@ -0,0 +1,170 @@
+package docs
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+	"gopkg.in/yaml.v3"
+)
+
+// PlaceholderType tells what a placeholder of the documented responses (e.g.
+// "<peer-id>") stands for in each generated output.
+type PlaceholderType struct {
+	// Type is the JSON type: boolean, integer, number, string, array or
+	// object.
+	Type string `yaml:"type,omitempty"`
+	// Format is the format of the schema, e.g. "int64".
+	Format string `yaml:"format,omitempty"`
+	// Pattern is the pattern of the schema of strings, e.g. "^[0-7]{3,4}$".
+	Pattern string `yaml:"pattern,omitempty"`
+	// Schema is the name of the shared component schema referenced by the
+	// OpenAPI spec instead of an inline schema, e.g. "PeerID".
+	Schema string `yaml:"schema,omitempty"`
+	// Go is the type of the generated Go client.
+	Go string `yaml:"go,omitempty"`
+	// TypeScript is the type of the generated TypeScript definitions.
+	TypeScript string `yaml:"typescript,omitempty"`
+	// Example is the value of the placeholder in the examples of the
+	// OpenAPI spec and in the responses of http-api-mock.
+	Example any `yaml:"example,omitempty"`
+}
+
+// placeholderTypes are the built-in types of the placeholders of the
+// documented responses, see TypeMap. The times and durations are first
+// mapped by their time format (see DefaultTimeFormats).
+var placeholderTypes = map[string]PlaceholderType{
+	"<bool>":        {Type: "boolean", Go: "bool", TypeScript: "boolean", Example: true},
+	"<int>":         {Type: "integer", Go: "int", TypeScript: "number", Example: 1},
+	"<uint>":        {Type: "integer", Go: "uint", TypeScript: "number", Example: 1},
+	"<int32>":       {Type: "integer", Go: "int32", TypeScript: "number", Example: 1},
+	"<uint32>":      {Type: "integer", Go: "uint32", TypeScript: "number", Example: 1},
+	"<int64>":       {Type: "integer", Go: "int64", TypeScript: "number", Example: 1},
+	"<uint64>":      {Type: "integer", Go: "uint64", TypeScript: "number", Example: 1},
+	"<duration-ns>": {Type: "integer", Go: "int64", TypeScript: "number", Example: 1000000000},
+	"<timestamp>":   {Type: "string", Go: "string", TypeScript: "string", Example: "2024-01-02T15:04:05Z"},
+	"<float32>":     {Type: "number", Go: "float32", TypeScript: "number", Example: 1.5},
+	"<float64>":     {Type: "number", Go: "float64", TypeScript: "number", Example: 1.5},
+	"<string>":      {Type: "string", Go: "string", TypeScript: "string", Example: "string"},
+	// Not from jsondoc: the Unix permissions which Kubo marshals as octal
+	// strings, in the responses documented by hand.
+	"<octal-mode>":       {Type: "string", Pattern: "^[0-7]{3,4}$", Go: "string", TypeScript: "string", Example: "0644"},
+	"<peer-id>":          {Type: "string", Schema: peerIDSchemaName, Go: "string", TypeScript: "string", Example: "12D3KooWGzxzKZYveHXtpG6AsrUJBcWxHBFS2HsEoGTxrMLvKXtf"},
+	"peer-id":            {Type: "string", Schema: peerIDSchemaName, Go: "string", TypeScript: "string", Example: "12D3KooWGzxzKZYveHXtpG6AsrUJBcWxHBFS2HsEoGTxrMLvKXtf"},
+	"<cid-string>":       {Type: "string", Schema: cidSchemaName, Go: "string", TypeScript: "string", Example: "bafkqaaa"}, // the empty identity block
+	"<multiaddr-string>": {Type: "string", Schema: multiaddrSchemaName, Go: "string", TypeScript: "string", Example: "/ip4/127.0.0.1/tcp/4001"},
+	"<array>":            {Type: "array", Go: "[]json.RawMessage", TypeScript: "unknown[]", Example: []any{}},
+	"<object>":           {Type: "object", Go: "map[string]json.RawMessage", Example: map[string]any{}},
+}
+
+// placeholderJSONTypes are the accepted values of PlaceholderType.Type.
+var placeholderJSONTypes = []string{"boolean", "integer", "number", "string", "array", "object"}
+
+// TypeMap overrides the built-in types of placeholders, by placeholder. The
+// formatters apply it after the time formats, so that it can also change
+// the representation of "<timestamp>" or "<duration-ns>".
+type TypeMap map[string]PlaceholderType
+
+// lookup returns the type of a placeholder, from the type map or else the
+// built-in ones.
+func (m TypeMap) lookup(placeholder string) (PlaceholderType, bool) {
+	if t, ok := m[placeholder]; ok {
+		return t, true
+	}
+	t, ok := placeholderTypes[placeholder]
+	return t, ok
+}
+
+// LoadTypeMap returns the placeholder types of a YAML file, mapping
+// placeholders to PlaceholderType, merged over the built-in ones, e.g. to
+// fix a wrong mapping or to support a placeholder introduced by a new Kubo
+// release:
+//
+//	"<cid-string>":
+//	  typescript: CIDString
+//	"<bytes>":
+//	  type: string
+//	  format: byte
+//	  go: "[]byte"
+//	  typescript: string
+//
+// The fields of a built-in placeholder which are not given are kept. New
+// placeholders need a type.
+func LoadTypeMap(path string) (TypeMap, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var types map[string]PlaceholderType
+	if err := yaml.Unmarshal(b, &types); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	merged := make(TypeMap, len(types))
+	shared := sharedSchemas()
+	for _, name := range names {
+		t, known := placeholderTypes[name]
+		t = t.merge(types[name])
+		switch {
+		case t.Type == "":
+			return nil, fmt.Errorf("%s: new placeholder %s has no type", path, name)
+		case !slices.Contains(placeholderJSONTypes, t.Type):
+			return nil, fmt.Errorf("%s: unknown type %q of %s, expected %s", path, t.Type, name, strings.Join(placeholderJSONTypes, ", "))
+		}
+		if _, ok := shared[t.Schema]; t.Schema != "" && !ok {
+			return nil, fmt.Errorf("%s: unknown shared schema %q of %s", path, t.Schema, name)
+		}
+		if !known && t.Go == "" {
+			t.Go = "json.RawMessage"
+		}
+		merged[name] = t
+	}
+	return merged, nil
+}
+
+// merge returns the type with the fields set in override replaced.
+func (t PlaceholderType) merge(override PlaceholderType) PlaceholderType {
+	if override.Type != "" {
+		t.Type = override.Type
+	}
+	if override.Format != "" {
+		t.Format = override.Format
+	}
+	if override.Pattern != "" {
+		t.Pattern = override.Pattern
+	}
+	if override.Schema != "" {
+		t.Schema = override.Schema
+	}
+	if override.Go != "" {
+		t.Go = override.Go
+	}
+	if override.TypeScript != "" {
+		t.TypeScript = override.TypeScript
+	}
+	if override.Example != nil {
+		t.Example = override.Example
+	}
+	return t
+}
+
+// schema returns the inline schema of the placeholder.
+func (t PlaceholderType) schema() *openapi3.Schema {
+	schemaType := openapi3.SchemaType(t.Type)
+	schema := &openapi3.Schema{Type: &schemaType}
+	if t.Format != "" {
+		schema.Format = &t.Format
+	}
+	if t.Pattern != "" {
+		schema.Pattern = &t.Pattern
+	}
+	return schema
+}
@@ -0,0 +1,86 @@
+package docs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTypeMap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "types.yaml")
+	typeMap := `"<cid-string>":
+  typescript: CIDString
+"<bytes>":
+  type: string
+  format: byte
+  example: aGVsbG8=
+`
+	if err := os.WriteFile(path, []byte(typeMap), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	types, err := LoadTypeMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := tsType(types, "<cid-string>", nil, "", ""); got != "CIDString" {
+		t.Errorf("the TypeScript type should be overridden, got %s", got)
+	}
+	if cid := types["<cid-string>"]; cid.Schema != cidSchemaName || cid.Go != "string" {
+		t.Errorf("the other fields should be kept, got %+v", cid)
+	}
+	s := genSchemaForResponse(nil, types, map[string]any{"Data": "<bytes>"}).Properties["Data"].Schema
+	if s == nil || *s.Type != "string" || *s.Format != "byte" {
+		t.Errorf("unexpected schema of the new placeholder: %+v", s)
+	}
+	if got := goType(types, "<bytes>"); got != "json.RawMessage" {
+		t.Errorf("unexpected Go type of the new placeholder: %s", got)
+	}
+	if got := mockValue(types, "<bytes>"); got != "aGVsbG8=" {
+		t.Errorf("unexpected mock value of the new placeholder: %v", got)
+	}
+	if _, ok := placeholderTypes["<bytes>"]; ok || placeholderTypes["<cid-string>"].TypeScript != "string" {
+		t.Errorf("the built-in types should not be changed")
+	}
+
+	for _, invalid := range []string{
+		"\"<new>\":\n  go: string\n",
+		"\"<new>\":\n  type: text\n",
+		"\"<peer-id>\":\n  schema: Missing\n",
+	} {
+		if err := os.WriteFile(path, []byte(invalid), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadTypeMap(path); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestTypeMapOverridesTimeFormats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "types.yaml")
+	typeMap := `"<timestamp>":
+  type: integer
+  format: int64
+`
+	if err := os.WriteFile(path, []byte(typeMap), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	types, err := LoadTypeMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := map[string]any{"Seen": "<timestamp>"}
+	formatter := &OpenAPIFormatter{TypeMap: types}
+	s := formatter.applyTimeFormats("/api/v0/test", "", doc, genSchemaOrRefForResponse(nil, types, doc, true)).Schema
+	seen := s.Properties["Seen"].Schema
+	if seen == nil || seen.Type == nil || *seen.Type != "integer" || *seen.Format != "int64" {
+		t.Errorf("the type map should override the time format, got %+v", seen)
+	}
+
+	s = new(OpenAPIFormatter).applyTimeFormats("/api/v0/test", "", doc, genSchemaOrRefForResponse(nil, nil, doc, true)).Schema
+	if seen := s.Properties["Seen"].Schema; seen.Format == nil || *seen.Format != "date-time" {
+		t.Errorf("timestamps should still be date-time strings without type map, got %+v", seen)
+	}
+}
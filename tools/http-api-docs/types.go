@@ -0,0 +1,89 @@
+package docs
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// responseType returns the package-qualified name of the Go type returned
+// by a command, like "pin.AddPinOutput", or an empty string when the type has
+// no name (slices, maps...) or the command returns text.
+func responseType(t interface{}) string {
+	if t == nil {
+		return ""
+	}
+	rt := reflect.TypeOf(t)
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt.Name() == "" || rt.PkgPath() == "" || rt.Kind() != reflect.Struct {
+		return ""
+	}
+	return path.Base(rt.PkgPath()) + "." + rt.Name()
+}
+
+// ResponseSchemaNames returns the names under which the response types of
+// the given endpoints are registered as component schemas, indexed by
+// Endpoint.ResponseType. Types are named after their Go type. When types of
+// different packages share a name, the package name is prepended to it.
+func ResponseSchemaNames(api []*Endpoint) map[string]string {
+	byName := make(map[string][]string)
+	for _, endp := range api {
+		if endp.ResponseType == "" {
+			continue
+		}
+		_, name, _ := strings.Cut(endp.ResponseType, ".")
+		qualified := byName[name]
+		found := false
+		for _, q := range qualified {
+			found = found || q == endp.ResponseType
+		}
+		if !found {
+			byName[name] = append(qualified, endp.ResponseType)
+		}
+	}
+
+	names := make(map[string]string)
+	for name, qualified := range byName {
+		for _, q := range qualified {
+			if len(qualified) == 1 {
+				names[q] = exportName(name)
+			} else {
+				pkg, _, _ := strings.Cut(q, ".")
+				names[q] = exportName(pkg) + exportName(name)
+			}
+		}
+	}
+	return names
+}
+
+func exportName(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// ResponseTypeIndex maps the name of each response component schema to the
+// (sorted) names of all the endpoints which return it.
+func ResponseTypeIndex(api []*Endpoint) map[string][]string {
+	names := ResponseSchemaNames(api)
+	index := make(map[string][]string)
+	for _, endp := range api {
+		if name, ok := names[endp.ResponseType]; ok {
+			index[name] = append(index[name], endp.Name)
+		}
+	}
+	for _, endps := range index {
+		sort.Strings(endps)
+	}
+	return index
+}
+
+// ResponseTypeIndexJSON returns the ResponseTypeIndex as indented JSON.
+func ResponseTypeIndexJSON(api []*Endpoint) ([]byte, error) {
+	return json.MarshalIndent(ResponseTypeIndex(api), "", "  ")
+}
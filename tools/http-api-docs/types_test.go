@@ -0,0 +1,28 @@
+package docs
+
+import "testing"
+
+func TestResponseSchemaNames(t *testing.T) {
+	api := []*Endpoint{
+		{Name: "/api/v0/a", ResponseType: "bitswap.Stat"},
+		{Name: "/api/v0/b", ResponseType: "corerepo.Stat"},
+		{Name: "/api/v0/c", ResponseType: "commands.stringList"},
+		{Name: "/api/v0/d", ResponseType: "commands.stringList"},
+		{Name: "/api/v0/e"},
+	}
+	names := ResponseSchemaNames(api)
+	for qualified, want := range map[string]string{
+		"bitswap.Stat":        "BitswapStat",
+		"corerepo.Stat":       "CorerepoStat",
+		"commands.stringList": "StringList",
+	} {
+		if names[qualified] != want {
+			t.Errorf("name of %s is %q, want %q", qualified, names[qualified], want)
+		}
+	}
+
+	index := ResponseTypeIndex(api)
+	if len(index) != 3 || len(index["StringList"]) != 2 {
+		t.Errorf("unexpected index: %v", index)
+	}
+}
@@ -0,0 +1,202 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TypeScriptFormatter generates TypeScript type definitions (a .d.ts file)
+// for the endpoints: an interface of the query parameters of each endpoint
+// (<Name>Options) and the type of its response (<Name>Response), named after
+// the operation ID in PascalCase, e.g. PinAddOptions for pin/add. The
+// Operations interface maps the operation IDs to them.
+type TypeScriptFormatter struct {
+	// TypeMap overrides the types of placeholders (see LoadTypeMap).
+	TypeMap TypeMap
+}
+
+// tsOptionTypes are the TypeScript types of the options, by Argument type.
+var tsOptionTypes = map[string]string{
+	"string":  "string",
+	"bool":    "boolean",
+	"int":     "number",
+	"uint":    "number",
+	"int64":   "number",
+	"uint64":  "number",
+	"float64": "number",
+	"array":   "string[]",
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Generate returns the type definitions.
+func (tf *TypeScriptFormatter) Generate(ctx context.Context, api []*Endpoint) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "// Generated by http-api-docs from kubo v%s. DO NOT EDIT.\n", IPFSVersion())
+
+	operations := new(bytes.Buffer)
+	names := make(map[string]string)
+	for _, endp := range api {
+		name := pascalName(endp.Name)
+		if other, ok := names[name]; ok {
+			return "", fmt.Errorf("%s and %s both map to %s", other, endp.Name, name)
+		}
+		names[name] = endp.Name
+		if err := genTypeScript(buf, tf.TypeMap, name, endp); err != nil {
+			return "", &EndpointError{Endpoint: endp.Name, Err: err}
+		}
+		fmt.Fprintf(operations, "  %s: { options: %sOptions; response: %sResponse; streaming: %t }\n",
+			tsKey(strings.TrimPrefix(endp.Name, APIPrefix+"/")), name, name, endp.Streaming)
+	}
+
+	fmt.Fprintf(buf, "\n/** The options and response of each operation, by operation ID. */\nexport interface Operations {\n%s}\n", operations)
+	return buf.String(), nil
+}
+
+func genTypeScript(buf *bytes.Buffer, types TypeMap, name string, endp *Endpoint) error {
+	fmt.Fprintf(buf, "\n/** Query parameters of %s. */\nexport interface %sOptions {\n", endp.Name, name)
+	var args []*Argument
+	for _, arg := range endp.Arguments {
+		if arg.Type != "file" {
+			args = append(args, arg)
+		}
+	}
+	if len(args) > 0 {
+		typ := "string"
+		if len(args) > 1 || args[0].Variadic {
+			typ = "string[]"
+		}
+		var descs []string
+		required := false
+		for _, arg := range args {
+			descs = append(descs, fmt.Sprintf("`%s`: %s", arg.Name, goComment(arg.Description)))
+			required = required || arg.Required
+		}
+		writeTSProperty(buf, "arg", typ, strings.Join(descs, " "), !required)
+	}
+	for _, opt := range endp.Options {
+		typ, ok := tsOptionTypes[opt.Type]
+		if !ok {
+			return fmt.Errorf("unsupported type %s for option %s", opt.Type, opt.Name)
+		}
+		if len(opt.Enum) > 0 {
+			typ = tsUnion(typ, opt.Enum)
+		}
+		desc := goComment(opt.Description)
+		if opt.Default != "" {
+			desc += fmt.Sprintf(" Default: `%s`.", opt.Default)
+		}
+		writeTSProperty(buf, opt.Name, typ, desc, true)
+	}
+	fmt.Fprintf(buf, "}\n")
+
+	typ := "string"
+	if endp.Response != "" && endp.Response != textResponse {
+		var doc any
+		if err := json.Unmarshal([]byte(endp.Response), &doc); err != nil {
+			return fmt.Errorf("parsing the response: %w", err)
+		}
+		typ = tsType(types, doc, endp.ResponseFields, "", "")
+	}
+	desc := "Response of " + endp.Name
+	switch {
+	case endp.Streaming:
+		desc += ", streamed as newline-delimited JSON values of this type"
+	case typ == "string":
+		desc += " (text)"
+	}
+	fmt.Fprintf(buf, "\n/** %s. */\nexport type %sResponse = %s\n", desc, name, typ)
+	return nil
+}
+
+func writeTSProperty(buf *bytes.Buffer, name, typ, desc string, optional bool) {
+	if desc != "" {
+		fmt.Fprintf(buf, "  /** %s */\n", strings.ReplaceAll(desc, "*/", "*\\/"))
+	}
+	opt := ""
+	if optional {
+		opt = "?"
+	}
+	fmt.Fprintf(buf, "  %s%s: %s\n", tsKey(name), opt, typ)
+}
+
+// tsUnion returns the union of the literal values of an enum.
+func tsUnion(typ string, values []string) string {
+	literals := make([]string, len(values))
+	for i, v := range values {
+		if typ == "number" {
+			literals[i] = v
+		} else {
+			literal, _ := json.Marshal(v)
+			literals[i] = string(literal)
+		}
+	}
+	return strings.Join(literals, " | ")
+}
+
+// tsKey quotes property names which are not identifiers.
+func tsKey(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	key, _ := json.Marshal(name)
+	return string(key)
+}
+
+// tsType returns the TypeScript type of a documented response, whose fields
+// have the given presence (see Endpoint.ResponseFields): the optional ones
+// are marked with "?" and the nullable ones accept null.
+func tsType(types TypeMap, x any, fields map[string]FieldPresence, path, indent string) string {
+	switch v := x.(type) {
+	case string:
+		if t, ok := types.lookup(v); ok && t.TypeScript != "" {
+			return t.TypeScript
+		}
+	case []any:
+		if len(v) == 1 {
+			item := tsType(types, v[0], fields, path, indent)
+			if strings.ContainsAny(item, " <{") {
+				return "Array<" + item + ">"
+			}
+			return item + "[]"
+		}
+		return "unknown[]"
+	case map[string]any:
+		if len(v) == 1 {
+			if item, ok := v["<string>"]; ok {
+				return "Record<string, " + tsType(types, item, fields, path, indent) + ">"
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		b.WriteString("{\n")
+		for _, k := range keys {
+			fieldPath := k
+			if path != "" {
+				fieldPath = path + "." + k
+			}
+			typ, opt := tsType(types, v[k], fields, fieldPath, indent+"  "), ""
+			if presence := fields[fieldPath]; presence.Optional {
+				opt = "?"
+			} else if presence.Nullable {
+				typ += " | null"
+			}
+			fmt.Fprintf(&b, "%s  %s%s: %s\n", indent, tsKey(k), opt, typ)
+		}
+		b.WriteString(indent + "}")
+		return b.String()
+	}
+	return "unknown"
+}
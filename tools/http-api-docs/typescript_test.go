@@ -0,0 +1,44 @@
+package docs
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTypeScriptFixture(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	src, err := new(TypeScriptFormatter).Generate(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"export interface FixtureV0JsonOptions {\n  /** `key`: A required argument. */\n  arg: string\n}",
+		"export type FixtureV0JsonResponse = {\n  Counts: Record<string, number> | null\n  Name: string\n  Size: number\n  Tags: string[] | null\n}",
+		"export interface FixtureV0MultiOptions {\n  /** `from`: First argument. `to`: Second argument. */\n  arg: string[]\n}",
+		"/** Response of /fixture/v0/multi (text). */\nexport type FixtureV0MultiResponse = string",
+		"  strings?: string[]\n",
+		"/** Response of /fixture/v0/stream, streamed as newline-delimited JSON values of this type. */",
+		`  "/fixture/v0/upload": { options: FixtureV0UploadOptions; response: FixtureV0UploadResponse; streaming: false }`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("definitions do not contain %q", want)
+		}
+	}
+}
+
+func TestTypeScriptKubo(t *testing.T) {
+	src, err := new(TypeScriptFormatter).Generate(context.Background(), AllEndpoints())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(src, "  \"cid-version\"?: 0 | 1\n") {
+		t.Errorf("enums should be unions of literals")
+	}
+	if !strings.Contains(src, "  \"pin/add\": { options: PinAddOptions; response: PinAddResponse; streaming: true }") {
+		t.Errorf("missing pin/add operation")
+	}
+	if !strings.Contains(src, "    Latency?: string\n") || !strings.Contains(src, "    Streams?: Array<{\n") {
+		t.Errorf("fields with omitempty should be optional")
+	}
+}
@@ -0,0 +1,200 @@
+package docs
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// Helpers to render values with units (byte sizes, durations) in a way
+// readers don't need to guess the unit. They are shared by all formatters
+// so that the same value always reads the same.
+
+var byteUnits = []string{"KiB", "MiB", "GiB", "TiB"}
+
+// formatBytes renders a number of bytes in the largest binary unit which
+// represents it exactly, e.g. "256 KiB" for 262144, or else in bytes. The
+// raw number is next to it wherever it is used, e.g. " Default: `262144`
+// (256 KiB).", so it is not repeated.
+func formatBytes(n int64) string {
+	unit := ""
+	v := n
+	for _, u := range byteUnits {
+		if v == 0 || v%1024 != 0 {
+			break
+		}
+		v /= 1024
+		unit = u
+	}
+	if unit == "" {
+		return pluralize(n, "byte")
+	}
+	return fmt.Sprintf("%d %s", v, unit)
+}
+
+var durationUnits = []struct {
+	d    time.Duration
+	name string
+}{
+	{24 * time.Hour, "day"},
+	{time.Hour, "hour"},
+	{time.Minute, "minute"},
+	{time.Second, "second"},
+	{time.Millisecond, "millisecond"},
+	{time.Microsecond, "microsecond"},
+}
+
+// formatDuration renders a duration in the largest unit which represents it
+// exactly, e.g. "2 days" for 48h.
+func formatDuration(d time.Duration) string {
+	for _, u := range durationUnits {
+		if d >= u.d && d%u.d == 0 {
+			return pluralize(int64(d/u.d), u.name)
+		}
+	}
+	return pluralize(int64(d), "nanosecond")
+}
+
+func pluralize(n int64, unit string) string {
+	if n == 1 || n == -1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// Units of the quantities of the options and responses, documented with
+// the x-unit extension.
+const (
+	UnitBytes          = "bytes"
+	UnitBytesPerSecond = "bytes/s"
+	UnitBits           = "bits"
+)
+
+// ByteCountFormat is the format of the integers counting bytes. They are
+// 64-bit in Kubo.
+const ByteCountFormat = "byte-count"
+
+// byteFieldName matches the names of the integer fields of the responses
+// which count bytes, e.g. CumulativeSize or BlockBytesCount.
+var byteFieldName = regexp.MustCompile(`(Size|Bytes|BytesCount)$`)
+
+// isByteSize tells whether an option takes a number of bytes.
+func isByteSize(arg *Argument) bool {
+	return optionUnit(arg) == UnitBytes
+}
+
+// optionUnit returns the unit of a numeric option, from optionUnits or
+// guessed from its description, or "" if it is not a quantity.
+func optionUnit(arg *Argument) string {
+	switch arg.Type {
+	case "int", "uint", "int64", "uint64":
+	default:
+		return ""
+	}
+	if unit, ok := optionUnits[arg.Endpoint][arg.Name]; ok {
+		return unit
+	}
+	desc := strings.ToLower(arg.Description)
+	if strings.Contains(desc, "size") || strings.Contains(desc, "bytes") {
+		return UnitBytes
+	}
+	return ""
+}
+
+// fieldUnit returns the unit of the field at path in the response of an
+// endpoint, from responseFieldUnits or guessed from its name for integers,
+// or "" if it is not a quantity.
+func fieldUnit(endpoint, path string, schema *openapi3.Schema) string {
+	if unit, ok := responseFieldUnits[endpoint][path]; ok {
+		return unit
+	}
+	name := path[strings.LastIndex(path, ".")+1:]
+	if schema.Type != nil && *schema.Type == openapi3.SchemaTypeInteger && byteFieldName.MatchString(name) {
+		return UnitBytes
+	}
+	return ""
+}
+
+// withUnit documents the unit of a quantity. Integers counting bytes get
+// the ByteCountFormat, so that clients can present them as sizes.
+func withUnit(schema *openapi3.Schema, unit string) {
+	schema.WithMapOfAnythingItem("x-unit", unit)
+	if unit == UnitBytes && schema.Type != nil && *schema.Type == openapi3.SchemaTypeInteger {
+		schema.WithFormat(ByteCountFormat)
+	}
+}
+
+// applyUnits documents the units of the fields of a documented response x,
+// whose schema was generated by genSchemaOrRefForResponse. The paths of the
+// fields are the ones of applyTimeFormats.
+func applyUnits(endpoint, path string, x any, schema *openapi3.SchemaOrRef) *openapi3.SchemaOrRef {
+	if schema == nil || schema.Schema == nil {
+		return schema
+	}
+	s := schema.Schema
+	switch v := x.(type) {
+	case []any:
+		if len(v) == 1 && s.Items != nil {
+			applyUnits(endpoint, path, v[0], s.Items)
+		}
+	case map[string]any:
+		if value, ok := v["<string>"]; ok && len(v) == 1 && s.AdditionalProperties != nil {
+			applyUnits(endpoint, path, value, s.AdditionalProperties.SchemaOrRef)
+			return schema
+		}
+		for k, value := range v {
+			prop, ok := s.Properties[k]
+			if !ok || prop.Schema == nil {
+				continue
+			}
+			fieldPath := k
+			if path != "" {
+				fieldPath = path + "." + k
+			}
+			if unit := fieldUnit(endpoint, fieldPath, prop.Schema); unit != "" {
+				withUnit(prop.Schema, unit)
+			}
+			applyUnits(endpoint, fieldPath, value, &prop)
+		}
+	}
+	return schema
+}
+
+// humanizeDefault returns a human-readable rendering of the default value
+// of an option, like "2 days" for "48h0m0s" or "32 bytes" for a block size.
+// It returns an empty string when the raw default needs no explanation.
+func humanizeDefault(arg *Argument) string {
+	def := arg.Default
+	if def == "" {
+		return ""
+	}
+
+	if isByteSize(arg) {
+		n, err := strconv.ParseInt(def, 10, 64)
+		if err != nil || n < 0 {
+			return ""
+		}
+		return formatBytes(n)
+	}
+
+	if arg.Type != "string" {
+		return ""
+	}
+	if size, ok := strings.CutPrefix(def, "size-"); ok {
+		// Chunker specification
+		n, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return ""
+		}
+		return formatBytes(n) + " chunks"
+	}
+	// Durations need a unit, so plain numbers don't parse here.
+	if d, err := time.ParseDuration(def); err == nil && d > 0 {
+		return formatDuration(d)
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package docs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+func TestHumanizeDefault(t *testing.T) {
+	for _, c := range []struct {
+		arg  Argument
+		want string
+	}{
+		{Argument{Type: "int", Default: "32", Description: "Maximum block size to inline."}, "32 bytes"},
+		{Argument{Type: "int", Default: "262144", Description: "Block size."}, "256 KiB"},
+		{Argument{Type: "string", Default: "size-1048576"}, "1 MiB chunks"},
+		{Argument{Type: "string", Default: "48h0m0s"}, "2 days"},
+		{Argument{Type: "string", Default: "1m0s"}, "1 minute"},
+		{Argument{Type: "string", Default: "1ms"}, "1 millisecond"},
+		{Argument{Type: "string", Default: "90s"}, "90 seconds"},
+		{Argument{Type: "int", Default: "10", Description: "Number of ping messages to send."}, ""},
+		{Argument{Type: "string", Default: "0"}, ""},
+		{Argument{Type: "string", Default: "dag-json"}, ""},
+	} {
+		if got := humanizeDefault(&c.arg); got != c.want {
+			t.Errorf("humanizeDefault(%q) = %q, want %q", c.arg.Default, got, c.want)
+		}
+	}
+}
+
+func TestOptionUnit(t *testing.T) {
+	for _, c := range []struct {
+		arg  Argument
+		want string
+	}{
+		{Argument{Type: "int", Description: "Maximum block size to inline."}, UnitBytes},
+		{Argument{Type: "int64", Description: "Maximum number of bytes to read."}, UnitBytes},
+		{Argument{Type: "int64", Endpoint: "/api/v0/cat", Name: "offset", Description: "Byte offset to begin reading from."}, UnitBytes},
+		{Argument{Type: "int", Endpoint: "/api/v0/key/gen", Name: "size", Description: "size of the key to generate."}, UnitBits},
+		{Argument{Type: "int", Description: "Number of ping messages to send."}, ""},
+		{Argument{Type: "string", Description: "Chunking algorithm, size-[bytes]."}, ""},
+	} {
+		if got := optionUnit(&c.arg); got != c.want {
+			t.Errorf("optionUnit(%q) = %q, want %q", c.arg.Description, got, c.want)
+		}
+	}
+}
+
+func TestApplyUnits(t *testing.T) {
+	var response any
+	if err := json.Unmarshal([]byte(`{"RepoSize": "<uint64>", "StorageMax": "<uint64>", "NumObjects": "<uint64>", "Links": [{"Size": "<uint64>"}]}`), &response); err != nil {
+		t.Fatal(err)
+	}
+	var w warnings
+	schema := applyUnits("/api/v0/repo/stat", "", response, genSchemaOrRefForResponse(&w, nil, response, true)).Schema
+	sizes := schema.Properties
+	for _, field := range []*openapi3.Schema{sizes["RepoSize"].Schema, sizes["StorageMax"].Schema, schema.Properties["Links"].Schema.Items.Schema.Properties["Size"].Schema} {
+		if field.Format == nil || *field.Format != ByteCountFormat || field.MapOfAnything["x-unit"] != UnitBytes {
+			t.Errorf("expected a byte count, got %+v", field)
+		}
+	}
+	if _, ok := schema.Properties["NumObjects"].Schema.MapOfAnything["x-unit"]; ok {
+		t.Error("NumObjects is not a quantity of bytes")
+	}
+}
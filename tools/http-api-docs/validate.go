@@ -0,0 +1,206 @@
+package docs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// Mismatch is a difference between the documented response of an endpoint
+// and the response of a running daemon.
+type Mismatch struct {
+	Endpoint string
+	// Path is the location of the mismatch in the response body, like
+	// "Peers[].Addr", or empty for the body itself.
+	Path    string
+	Message string
+}
+
+func (m Mismatch) String() string {
+	if m.Path == "" {
+		return fmt.Sprintf("%s: %s", m.Endpoint, m.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", m.Endpoint, m.Path, m.Message)
+}
+
+// ValidateAgainst calls a safe subset of the given endpoints on the RPC API
+// at baseURL (e.g. "http://127.0.0.1:5001") and compares the response bodies
+// against the generated response schemas. It returns an error only when the
+// daemon can't be reached or the context is done.
+func ValidateAgainst(ctx context.Context, client *http.Client, baseURL string, api []*Endpoint) ([]Mismatch, error) {
+	byName := make(map[string]*Endpoint, len(api))
+	for _, endp := range api {
+		byName[endp.Name] = endp
+	}
+
+	var mismatches []Mismatch
+	for _, name := range validationEndpoints {
+		endp, ok := byName[name]
+		if !ok {
+			continue
+		}
+		found, err := validateEndpoint(ctx, client, strings.TrimSuffix(baseURL, "/"), endp)
+		if err != nil {
+			return mismatches, err
+		}
+		mismatches = append(mismatches, found...)
+	}
+	return mismatches, nil
+}
+
+func validateEndpoint(ctx context.Context, client *http.Client, baseURL string, endp *Endpoint) ([]Mismatch, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+endp.Name, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	mismatch := func(path, format string, a ...any) Mismatch {
+		return Mismatch{Endpoint: endp.Name, Path: path, Message: fmt.Sprintf(format, a...)}
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return []Mismatch{mismatch("", "unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))}, nil
+	}
+
+	var documented any
+	if err := json.Unmarshal([]byte(endp.Response), &documented); err != nil {
+		// text/plain or undocumented responses have nothing to compare.
+		return nil, nil
+	}
+	schema := inlineSchema(applyPresence(endp.ResponseFields, "", documented, genSchemaOrRefForResponse(nil, nil, documented, false)))
+	if schema == nil {
+		return nil, nil
+	}
+
+	var mismatches []Mismatch
+	dec := json.NewDecoder(resp.Body)
+	for i := 0; ; i++ {
+		var value any
+		err := dec.Decode(&value)
+		if err == io.EOF {
+			if i == 0 {
+				mismatches = append(mismatches, mismatch("", "empty response body"))
+			}
+			break
+		}
+		if err != nil {
+			mismatches = append(mismatches, mismatch("", "invalid JSON in response: %s", err))
+			break
+		}
+		if i == 1 && !endp.Streaming {
+			mismatches = append(mismatches, mismatch("", "several JSON values in the response of an endpoint not documented as streaming"))
+		}
+		for _, m := range validateValue("", schema, value) {
+			mismatches = append(mismatches, mismatch(m.Path, "%s", m.Message))
+		}
+	}
+	return dedupMismatches(mismatches), nil
+}
+
+// validateValue compares a decoded JSON value with a schema generated by
+// genSchemaForResponse. Only the Endpoint field of the returned mismatches is
+// left empty.
+func validateValue(path string, schema *openapi3.Schema, value any) []Mismatch {
+	if schema.Type == nil || value == nil {
+		// Untyped schema, or a nil Go slice or map.
+		return nil
+	}
+
+	var got openapi3.SchemaType
+	switch v := value.(type) {
+	case bool:
+		got = openapi3.SchemaTypeBoolean
+	case float64:
+		got = openapi3.SchemaTypeNumber
+		if v == float64(int64(v)) {
+			got = openapi3.SchemaTypeInteger
+		}
+	case string:
+		got = openapi3.SchemaTypeString
+	case []any:
+		got = openapi3.SchemaTypeArray
+	case map[string]any:
+		got = openapi3.SchemaTypeObject
+	}
+	want := *schema.Type
+	if got != want && !(want == openapi3.SchemaTypeNumber && got == openapi3.SchemaTypeInteger) {
+		return []Mismatch{{Path: path, Message: fmt.Sprintf("documented as %s, got %s", want, got)}}
+	}
+
+	var mismatches []Mismatch
+	switch v := value.(type) {
+	case []any:
+		if schema.Items != nil && schema.Items.Schema != nil {
+			for _, item := range v {
+				mismatches = append(mismatches, validateValue(path+"[]", schema.Items.Schema, item)...)
+			}
+		}
+	case map[string]any:
+		if ap := schema.AdditionalProperties; ap != nil && ap.SchemaOrRef != nil {
+			// The items of maps referencing a shared schema are not
+			// checked.
+			if ap.SchemaOrRef.Schema != nil {
+				for _, item := range v {
+					mismatches = append(mismatches, validateValue(path+".<key>", ap.SchemaOrRef.Schema, item)...)
+				}
+			}
+			break
+		}
+		keys := make([]string, 0, len(v)+len(schema.Properties))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		for key := range schema.Properties {
+			if _, ok := v[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field := strings.TrimPrefix(path+"."+key, ".")
+			prop, documented := schema.Properties[key]
+			item, present := v[key]
+			switch {
+			case !documented:
+				mismatches = append(mismatches, Mismatch{Path: field, Message: "undocumented field"})
+			case !present && len(schema.Required) > 0 && !slices.Contains(schema.Required, key):
+				// Optional field, left out when empty.
+			case !present:
+				mismatches = append(mismatches, Mismatch{Path: field, Message: missingFieldMessage})
+			case prop.Schema != nil:
+				mismatches = append(mismatches, validateValue(field, prop.Schema, item)...)
+			}
+		}
+	}
+	return mismatches
+}
+
+// missingFieldMessage is the message of the mismatches of documented fields
+// missing from a value.
+const missingFieldMessage = "documented field is missing"
+
+// dedupMismatches drops repeated mismatches, e.g. the same field missing in
+// every item of an array.
+func dedupMismatches(mismatches []Mismatch) []Mismatch {
+	seen := make(map[Mismatch]bool)
+	var out []Mismatch
+	for _, m := range mismatches {
+		if !seen[m] {
+			seen[m] = true
+			out = append(out, m)
+		}
+	}
+	return out
+}
@@ -0,0 +1,55 @@
+package docs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateAgainst(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v0/version":
+			fmt.Fprint(w, `{"Version": "0.30.0", "Commit": 1, "Extra": true}`)
+		case "/api/v0/refs/local":
+			fmt.Fprint(w, `{"Ref": "a", "Err": ""}`+"\n"+`{"Ref": "b"}`+"\n")
+		case "/api/v0/id":
+			fmt.Fprint(w, `{"ID": "a"}`)
+		default:
+			http.Error(w, "unexpected call", http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+
+	api := []*Endpoint{
+		{Name: "/api/v0/version", Response: `{"Version": "<string>", "Commit": "<string>", "Repo": "<string>"}`},
+		{Name: "/api/v0/refs/local", Response: `{"Ref": "<string>", "Err": "<string>"}`, Streaming: true},
+		{
+			Name:           "/api/v0/id",
+			Response:       `{"ID": "<string>", "Addresses": ["<string>"]}`,
+			ResponseFields: map[string]FieldPresence{"ID": {}, "Addresses": {Optional: true}},
+		},
+		{Name: "/api/v0/not-safe"},
+	}
+	mismatches, err := ValidateAgainst(context.Background(), ts.Client(), ts.URL+"/", api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"/api/v0/refs/local: Err: documented field is missing",
+		"/api/v0/version: Commit: documented as string, got integer",
+		"/api/v0/version: Extra: undocumented field",
+		"/api/v0/version: Repo: documented field is missing",
+	}
+	if len(mismatches) != len(want) {
+		t.Fatalf("got %v, want %v", mismatches, want)
+	}
+	for i, m := range mismatches {
+		if m.String() != want[i] {
+			t.Errorf("got %q, want %q", m, want[i])
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+// genResponseVariants returns the oneOf schema of the variants of the
+// response of an endpoint (see responseVariants), or nil if it has none.
+// Each variant forbids the Required fields of the others which it doesn't
+// have, so that a response matches only one of them.
+func (myself *OpenAPIFormatter) genResponseVariants(w *warnings, endp *Endpoint) *openapi3.SchemaOrRef {
+	variants := responseVariants[endp.Name]
+	if len(variants) == 0 {
+		return nil
+	}
+	var distinctive []string
+	for _, v := range variants {
+		distinctive = append(distinctive, v.Required...)
+	}
+
+	oneOf := make([]openapi3.SchemaOrRef, 0, len(variants))
+	for _, v := range variants {
+		var doc any
+		if err := json.Unmarshal([]byte(v.Response), &doc); err != nil {
+			w.warnf("Couldn't parse JSON for the response variant %s: %s", variantName(v.When), err)
+			return nil
+		}
+		schema := applyPresence(endp.ResponseFields, "", doc, applyUnits(endp.Name, "", doc, myself.applyTimeFormats(endp.Name, "", doc, genSchemaOrRefForResponse(w, myself.TypeMap, doc, true))))
+		if schema == nil || schema.Schema == nil {
+			w.warnf("The response variant %s is not an object", variantName(v.When))
+			return nil
+		}
+		s := schema.Schema
+		requireDistinctive(s, v.Required, distinctive)
+		if v.Description != "" {
+			s.WithDescription(v.Description)
+		}
+		s.WithMapOfAnythingItem("x-variant-when", v.When)
+		oneOf = append(oneOf, *schema)
+	}
+	return &openapi3.SchemaOrRef{Schema: &openapi3.Schema{OneOf: oneOf}}
+}
+
+// requireDistinctive requires the given fields of one of the shapes of a
+// oneOf schema, and forbids the distinctive fields of the other shapes
+// which it doesn't have, so that a value matches only one of them.
+func requireDistinctive(s *openapi3.Schema, required, distinctive []string) {
+	for _, field := range required {
+		if !slices.Contains(s.Required, field) {
+			s.Required = append(s.Required, field)
+		}
+	}
+	var forbidden []openapi3.SchemaOrRef
+	for _, field := range distinctive {
+		if _, ok := s.Properties[field]; !ok {
+			forbidden = append(forbidden, openapi3.SchemaOrRef{Schema: &openapi3.Schema{Required: []string{field}}})
+		}
+	}
+	switch len(forbidden) {
+	case 0:
+	case 1:
+		s.Not = &forbidden[0]
+	default:
+		s.Not = &openapi3.SchemaOrRef{Schema: &openapi3.Schema{AnyOf: forbidden}}
+	}
+}
+
+// variantName returns the option values of a variant, e.g.
+// "with-local=true", for the warnings.
+func variantName(when map[string]string) string {
+	options := make([]string, 0, len(when))
+	for option, value := range when {
+		options = append(options, fmt.Sprintf("%s=%s", option, value))
+	}
+	sort.Strings(options)
+	if len(options) == 0 {
+		return "without options"
+	}
+	return strings.Join(options, ",")
+}
@@ -0,0 +1,39 @@
+package docs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+func TestResponseVariants(t *testing.T) {
+	var api []*Endpoint
+	for _, endp := range AllEndpoints() {
+		if endp.Name == "/api/v0/files/stat" {
+			api = append(api, endp)
+		}
+	}
+	formatter := new(OpenAPIFormatter)
+	if err := formatter.Build(context.Background(), api); err != nil {
+		t.Fatal(err)
+	}
+	schema := formatter.spec.Components.Schemas.MapOfSchemaOrRefValues[formatter.schemaNames[api[0].ResponseType]].Schema
+	if schema == nil || len(schema.OneOf) != 2 {
+		t.Fatalf("expected the two variants of files/stat, got %+v", schema)
+	}
+	without, with := schema.OneOf[0].Schema, schema.OneOf[1].Schema
+	if when := with.MapOfAnything["x-variant-when"]; when.(map[string]string)["with-local"] != "true" {
+		t.Errorf("unexpected x-variant-when %v", when)
+	}
+	if _, ok := with.Properties["SizeLocal"]; !ok || len(with.Required) != 1 || with.Required[0] != "WithLocality" {
+		t.Errorf("the with-local variant should have the locality fields and require WithLocality, got %+v", with)
+	}
+	// statOutput.MarshalJSON sends the mode as an octal string.
+	if mode := with.Properties["Mode"].Schema; mode == nil || *mode.Type != openapi3.SchemaTypeString || mode.Pattern == nil || *mode.Pattern != "^[0-7]{3,4}$" {
+		t.Errorf("the mode should be an octal string, got %+v", with.Properties["Mode"])
+	}
+	if _, ok := without.Properties["SizeLocal"]; ok || without.Not == nil || without.Not.Schema.Required[0] != "WithLocality" {
+		t.Errorf("the variant without with-local should forbid WithLocality, got %+v", without)
+	}
+}
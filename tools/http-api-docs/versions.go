@@ -0,0 +1,45 @@
+package docs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// VersionedSpecName returns the name of the spec file of a Kubo version,
+// e.g. "openapi-v0.24.yaml" for 0.24.1. Patch releases don't change the
+// RPC API, so they share the file of their minor version.
+func VersionedSpecName(kuboVersion string) string {
+	v := strings.TrimPrefix(kuboVersion, "v")
+	if parts := strings.SplitN(v, ".", 3); len(parts) >= 2 {
+		v = parts[0] + "." + parts[1]
+	}
+	return "openapi-v" + v + ".yaml"
+}
+
+// GenerateVersions generates the spec of each dump with the given
+// formatter, as if built against its Kubo version (see KuboVersion), and
+// returns them by file name (see VersionedSpecName). It fails if two dumps
+// have the same file name.
+func GenerateVersions(ctx context.Context, dumps []*EndpointsDump, formatter OpenAPIFormatter) (map[string]string, error) {
+	specs := make(map[string]string, len(dumps))
+	versions := make(map[string]string, len(dumps))
+	for _, dump := range dumps {
+		name := VersionedSpecName(dump.KuboVersion)
+		if other, ok := versions[name]; ok {
+			return nil, fmt.Errorf("Kubo %s and %s would both be written to %s", other, dump.KuboVersion, name)
+		}
+		versions[name] = dump.KuboVersion
+
+		formatter.KuboVersion = dump.KuboVersion
+		if err := formatter.Build(ctx, dump.Endpoints); err != nil {
+			return nil, fmt.Errorf("Kubo %s: %w", dump.KuboVersion, err)
+		}
+		spec, err := formatter.SpecYAML()
+		if err != nil {
+			return nil, fmt.Errorf("Kubo %s: %w", dump.KuboVersion, err)
+		}
+		specs[name] = spec
+	}
+	return specs, nil
+}
@@ -0,0 +1,44 @@
+package docs
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestVersionedSpecName(t *testing.T) {
+	for version, want := range map[string]string{
+		"0.24.0":      "openapi-v0.24.yaml",
+		"v0.25.1":     "openapi-v0.25.yaml",
+		"0.30.0-rc1":  "openapi-v0.30.yaml",
+		"development": "openapi-vdevelopment.yaml",
+	} {
+		if got := VersionedSpecName(version); got != want {
+			t.Errorf("VersionedSpecName(%q) = %q, want %q", version, got, want)
+		}
+	}
+}
+
+func TestGenerateVersions(t *testing.T) {
+	api, _ := fixtureEndpoints(t)
+	dumps := []*EndpointsDump{
+		{KuboVersion: "0.24.0", Endpoints: api[:1]},
+		{KuboVersion: "0.25.0", Endpoints: api},
+	}
+	specs, err := GenerateVersions(context.Background(), dumps, OpenAPIFormatter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, current := specs["openapi-v0.24.yaml"], specs["openapi-v0.25.yaml"]
+	if len(specs) != 2 || !strings.Contains(old, "x-kubo-version: 0.24.0") || !strings.Contains(current, "x-kubo-version: 0.25.0") {
+		t.Fatalf("unexpected specs: %v", specs)
+	}
+	if len(old) >= len(current) {
+		t.Error("the 0.24 spec isn't generated from its own endpoints")
+	}
+
+	dumps = append(dumps, &EndpointsDump{KuboVersion: "0.25.1", Endpoints: api})
+	if _, err := GenerateVersions(context.Background(), dumps, OpenAPIFormatter{}); err == nil {
+		t.Error("no error for two dumps of 0.25")
+	}
+}
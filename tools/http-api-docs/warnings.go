@@ -0,0 +1,50 @@
+package docs
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Warning is a problem found while generating an endpoint, which degrades
+// the result without making it fail, like an unsupported type.
+type Warning struct {
+	Endpoint string `json:"endpoint"`
+	Message  string `json:"message"`
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Endpoint, w.Message)
+}
+
+// WarningsError is returned by Build in Strict mode when warnings were
+// found, so that a degraded spec fails CI.
+type WarningsError struct {
+	Warnings []Warning
+}
+
+func (e *WarningsError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s:", pluralize(int64(len(e.Warnings)), "warning"))
+	for _, w := range e.Warnings {
+		fmt.Fprintf(&b, "\n  %s", w)
+	}
+	return b.String()
+}
+
+// warnings collects the warnings of an endpoint. A nil *warnings only logs
+// them.
+type warnings struct {
+	endpoint string
+	list     []Warning
+}
+
+func (w *warnings) warnf(format string, a ...any) {
+	msg := fmt.Sprintf(format, a...)
+	if w == nil {
+		log.Printf("WARN: %s\n", msg)
+		return
+	}
+	log.Printf("WARN: %s: %s\n", w.endpoint, msg)
+	w.list = append(w.list, Warning{Endpoint: w.endpoint, Message: msg})
+}